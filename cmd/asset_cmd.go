@@ -26,6 +26,7 @@ import (
 	"github.com/ontio/ontology/common/config"
 	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
 	"github.com/urfave/cli"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -40,7 +41,7 @@ var AssetCommand = cli.Command{
 			Name:        "transfer",
 			Usage:       "Transfer ont or ong to another account",
 			ArgsUsage:   " ",
-			Description: "Transfer ont or ong to another account. If from address does not specified, using default account",
+			Description: "Transfer ont or ong to another account. If from address does not specified, using default account. Using --batch to transfer to many accounts listed in a csv file",
 			Flags: []cli.Flag{
 				utils.RPCPortFlag,
 				utils.TransactionGasPriceFlag,
@@ -49,6 +50,7 @@ var AssetCommand = cli.Command{
 				utils.TransactionFromFlag,
 				utils.TransactionToFlag,
 				utils.TransactionAmountFlag,
+				utils.TransactionBatchFlag,
 				utils.ForceSendTxFlag,
 				utils.WalletFileFlag,
 			},
@@ -136,6 +138,9 @@ var AssetCommand = cli.Command{
 
 func transfer(ctx *cli.Context) error {
 	SetRpcPort(ctx)
+	if ctx.IsSet(utils.GetFlagName(utils.TransactionBatchFlag)) {
+		return transferBatch(ctx)
+	}
 	if !ctx.IsSet(utils.GetFlagName(utils.TransactionToFlag)) ||
 		!ctx.IsSet(utils.GetFlagName(utils.TransactionFromFlag)) ||
 		!ctx.IsSet(utils.GetFlagName(utils.TransactionAmountFlag)) {
@@ -221,6 +226,87 @@ func transfer(ctx *cli.Context) error {
 	return nil
 }
 
+func transferBatch(ctx *cli.Context) error {
+	if !ctx.IsSet(utils.GetFlagName(utils.TransactionFromFlag)) {
+		PrintErrorMsg("Missing %s argument.", utils.TransactionFromFlag.Name)
+		cli.ShowSubcommandHelp(ctx)
+		return nil
+	}
+	asset := ctx.String(utils.GetFlagName(utils.TransactionAssetFlag))
+	if asset == "" {
+		asset = utils.ASSET_ONT
+	}
+	asset = strings.ToLower(asset)
+	fromAddr, err := cmdcom.ParseAddress(ctx.String(utils.TransactionFromFlag.Name), ctx)
+	if err != nil {
+		return err
+	}
+	batchFile := ctx.String(utils.GetFlagName(utils.TransactionBatchFlag))
+	file, err := os.Open(batchFile)
+	if err != nil {
+		return fmt.Errorf("open batch file:%s error:%s", batchFile, err)
+	}
+	defer file.Close()
+	recipients, err := utils.ParseTransferBatch(asset, file)
+	if err != nil {
+		return fmt.Errorf("parse batch file:%s error:%s", batchFile, err)
+	}
+	var total uint64
+	for _, recipient := range recipients {
+		total += recipient.Value
+		if err := utils.CheckAssetAmount(asset, total); err != nil {
+			return err
+		}
+	}
+	format := utils.FormatOnt
+	if asset == utils.ASSET_ONG {
+		format = utils.FormatOng
+	}
+
+	force := ctx.Bool(utils.GetFlagName(utils.ForceSendTxFlag))
+	if !force {
+		balance, err := utils.GetAccountBalance(fromAddr, asset)
+		if err != nil {
+			return err
+		}
+		if balance < total {
+			PrintErrorMsg("Account:%s balance not enough.", fromAddr)
+			PrintInfoMsg("\nTip:")
+			PrintInfoMsg("  If you want to send transaction compulsively, please using %s flag.", utils.GetFlagName(utils.ForceSendTxFlag))
+			return nil
+		}
+	}
+
+	gasPrice := ctx.Uint64(utils.TransactionGasPriceFlag.Name)
+	gasLimit := ctx.Uint64(utils.TransactionGasLimitFlag.Name)
+	networkId, err := utils.GetNetworkId()
+	if err != nil {
+		return err
+	}
+	if networkId == config.NETWORK_ID_SOLO_NET {
+		gasPrice = 0
+	}
+
+	signer, err := cmdcom.GetAccount(ctx, fromAddr)
+	if err != nil {
+		return err
+	}
+	txHash, err := utils.TransferMulti(gasPrice, gasLimit, signer, asset, recipients)
+	if err != nil {
+		return fmt.Errorf("transfer error:%s", err)
+	}
+	PrintInfoMsg("Transfer %s", strings.ToUpper(asset))
+	PrintInfoMsg("  From:%s", fromAddr)
+	for _, recipient := range recipients {
+		PrintInfoMsg("  To:%s Amount:%s", recipient.To.ToBase58(), format(recipient.Value))
+	}
+	PrintInfoMsg("  Total:%s", format(total))
+	PrintInfoMsg("  TxHash:%s", txHash)
+	PrintInfoMsg("\nTip:")
+	PrintInfoMsg("  Using './ontology info status %s' to query transaction status.", txHash)
+	return nil
+}
+
 func getBalance(ctx *cli.Context) error {
 	SetRpcPort(ctx)
 	if ctx.NArg() < 1 {
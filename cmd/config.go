@@ -49,6 +49,9 @@ func SetOntologyConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 		cfg.P2PNode.NetworkMagic = config.GetNetworkMagic(cfg.P2PNode.NetworkId)
 		cfg.Common.GasPrice = 0
 	}
+	if cfg.P2PNode.LightClient && cfg.Consensus.EnableConsensus {
+		return nil, fmt.Errorf("light client can not enable consensus")
+	}
 	if cfg.P2PNode.NetworkId == config.NETWORK_ID_MAIN_NET ||
 		cfg.P2PNode.NetworkId == config.NETWORK_ID_POLARIS_NET {
 		defNetworkId, err := cfg.GetDefaultNetworkId()
@@ -73,12 +76,28 @@ func setGenesis(ctx *cli.Context, cfg *config.OntologyConfig) error {
 		cfg.Genesis = config.PolarisConfig
 	}
 
+	if ctx.Bool(utils.GetFlagName(utils.DevModeFlag)) {
+		cfg.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
+		cfg.Genesis.SOLO.DevMode = true
+		prefund, err := utils.ParsePrefundAccounts(ctx.String(utils.GetFlagName(utils.DevModePrefundFlag)))
+		if err != nil {
+			return fmt.Errorf("invalid devmode-prefund: %s", err)
+		}
+		cfg.Genesis.SOLO.Prefund = prefund
+		return nil
+	}
+
 	if ctx.Bool(utils.GetFlagName(utils.EnableTestModeFlag)) {
 		cfg.Genesis.ConsensusType = config.CONSENSUS_TYPE_SOLO
 		cfg.Genesis.SOLO.GenBlockTime = ctx.Uint(utils.GetFlagName(utils.TestModeGenBlockTimeFlag))
 		if cfg.Genesis.SOLO.GenBlockTime <= 1 {
 			cfg.Genesis.SOLO.GenBlockTime = config.DEFAULT_GEN_BLOCK_TIME
 		}
+		cfg.Genesis.SOLO.MaxBlockTime = ctx.Uint(utils.GetFlagName(utils.TestModeMaxBlockTimeFlag))
+		if cfg.Genesis.SOLO.MaxBlockTime != 0 && cfg.Genesis.SOLO.MaxBlockTime < cfg.Genesis.SOLO.GenBlockTime {
+			return fmt.Errorf("testmode-max-block-time must not be less than testmode-gen-block-time")
+		}
+		cfg.Genesis.SOLO.TxThreshold = ctx.Uint(utils.GetFlagName(utils.TestModeTxThresholdFlag))
 		return nil
 	}
 
@@ -133,6 +152,7 @@ func setCommonConfig(ctx *cli.Context, cfg *config.CommonConfig) {
 func setConsensusConfig(ctx *cli.Context, cfg *config.ConsensusConfig) {
 	cfg.EnableConsensus = ctx.Bool(utils.GetFlagName(utils.EnableConsensusFlag))
 	cfg.MaxTxInBlock = ctx.Uint(utils.GetFlagName(utils.MaxTxInBlockFlag))
+	cfg.RemoteSigner = ctx.String(utils.GetFlagName(utils.ConsensusSignerFlag))
 }
 
 func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
@@ -145,6 +165,12 @@ func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
 	cfg.MaxConnInBound = ctx.Uint(utils.GetFlagName(utils.MaxConnInBoundFlag))
 	cfg.MaxConnOutBound = ctx.Uint(utils.GetFlagName(utils.MaxConnOutBoundFlag))
 	cfg.MaxConnInBoundForSingleIP = ctx.Uint(utils.GetFlagName(utils.MaxConnInBoundForSingleIPFlag))
+	cfg.EnableSecureLink = ctx.Bool(utils.GetFlagName(utils.EnableSecureLinkFlag))
+	cfg.RequireSecureLink = ctx.Bool(utils.GetFlagName(utils.RequireSecureLinkFlag))
+	cfg.NodeKeyFile = ctx.String(utils.GetFlagName(utils.NodeKeyFileFlag))
+	cfg.BanDuration = ctx.Uint(utils.GetFlagName(utils.BanDurationFlag))
+	cfg.BanWholeIP = ctx.Bool(utils.GetFlagName(utils.BanWholeIPFlag))
+	cfg.LightClient = ctx.Bool(utils.GetFlagName(utils.LightClientFlag))
 
 	rsvfile := ctx.String(utils.GetFlagName(utils.ReservedPeersFileFlag))
 	if cfg.ReservedPeersOnly {
@@ -163,6 +189,9 @@ func setP2PNodeConfig(ctx *cli.Context, cfg *config.P2PNodeConfig) {
 		for i := 0; i < len(cfg.ReservedCfg.MaskPeers); i++ {
 			log.Info("mask addr: " + cfg.ReservedCfg.MaskPeers[i])
 		}
+		for i := 0; i < len(cfg.ReservedCfg.ReservedPubKeys); i++ {
+			log.Info("reserved node key: " + cfg.ReservedCfg.ReservedPubKeys[i])
+		}
 	}
 
 }
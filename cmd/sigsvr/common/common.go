@@ -23,10 +23,14 @@ import (
 	"fmt"
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/cmd/sigsvr/store"
+	"github.com/ontio/ontology/consensus/signer"
 )
 
 var DefWalletStore *store.WalletStore
 
+//DefConsensusSigner signs consensus data for ontology node, nil if not enabled
+var DefConsensusSigner *signer.LocalSigner
+
 type CliRpcRequest struct {
 	Qid     string          `json:"qid"`
 	Params  json.RawMessage `json:"params"`
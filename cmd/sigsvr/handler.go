@@ -18,7 +18,10 @@
 
 package sigsvr
 
-import "github.com/ontio/ontology/cmd/sigsvr/handlers"
+import (
+	"github.com/ontio/ontology/cmd/sigsvr/handlers"
+	"github.com/ontio/ontology/consensus/signer"
+)
 
 func init() {
 	DefCliRpcSvr.RegHandler("createaccount", handlers.CreateAccount)
@@ -30,4 +33,7 @@ func init() {
 	DefCliRpcSvr.RegHandler("signeovminvoketx", handlers.SigNeoVMInvokeTx)
 	DefCliRpcSvr.RegHandler("signeovminvokeabitx", handlers.SigNeoVMInvokeAbiTx)
 	DefCliRpcSvr.RegHandler("signativeinvoketx", handlers.SigNativeInvokeTx)
+	DefCliRpcSvr.RegHandler(signer.METHOD_GET_PUBKEY, handlers.GetConsensusPubKey)
+	DefCliRpcSvr.RegHandler(signer.METHOD_SIGN, handlers.SigConsensus)
+	DefCliRpcSvr.RegHandler(signer.METHOD_VRF, handlers.ConsensusVrf)
 }
@@ -0,0 +1,137 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ontio/ontology-crypto/keypair"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/consensus/signer"
+	"github.com/ontio/ontology/core/types"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+)
+
+func GetConsensusPubKey(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	if clisvrcom.DefConsensusSigner == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_NO_CONSENSUS_SIGNER
+		return
+	}
+	resp.Result = &signer.ConsensusPubKeyRsp{
+		PubKey: hex.EncodeToString(keypair.SerializePublicKey(clisvrcom.DefConsensusSigner.PubKey())),
+	}
+}
+
+func SigConsensus(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	if clisvrcom.DefConsensusSigner == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_NO_CONSENSUS_SIGNER
+		return
+	}
+	rawReq := &signer.SigConsensusReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	rawData, err := hex.DecodeString(rawReq.RawData)
+	if err != nil {
+		log.Infof("Cli Qid:%s SigConsensus hex.DecodeString error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	//the client is not trusted, only data which can not be a block hash is
+	//signed without guard
+	switch rawReq.Kind {
+	case signer.SIGN_KIND_BLOCK, signer.SIGN_KIND_ENDORSE, signer.SIGN_KIND_COMMIT:
+	case signer.SIGN_KIND_MSG:
+		payload := &p2pmsg.ConsensusPayload{}
+		source := common.NewZeroCopySource(rawData)
+		if err := payload.DeserializationUnsigned(source); err != nil || source.Len() != 0 {
+			log.Infof("Cli Qid:%s SigConsensus invalid consensus payload", req.Qid)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+	case signer.SIGN_KIND_TX:
+		if _, err := types.TransactionFromRawBytes(rawData); err != nil {
+			log.Infof("Cli Qid:%s SigConsensus invalid transaction:%s", req.Qid, err)
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+	case signer.SIGN_KIND_SUBMIT:
+		if len(rawData) != common.UINT256_SIZE {
+			resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+			return
+		}
+		rawData = signer.SubmitData(rawData)
+	default:
+		log.Infof("Cli Qid:%s SigConsensus unknown kind:%s", req.Qid, rawReq.Kind)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	sigData, err := clisvrcom.DefConsensusSigner.Sign(&signer.SignRequest{
+		Kind:   rawReq.Kind,
+		Height: rawReq.Height,
+		Round:  rawReq.Round,
+		Data:   rawData,
+	})
+	if err != nil {
+		log.Warnf("Cli Qid:%s SigConsensus Sign error:%s", req.Qid, err)
+		if _, ok := err.(*signer.DoubleSignError); ok {
+			resp.ErrorCode = clisvrcom.CLIERR_DOUBLE_SIGN
+		} else {
+			resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		}
+		resp.ErrorInfo = err.Error()
+		return
+	}
+	resp.Result = &signer.SigConsensusRsp{
+		SignedData: hex.EncodeToString(sigData),
+	}
+}
+
+func ConsensusVrf(req *clisvrcom.CliRpcRequest, resp *clisvrcom.CliRpcResponse) {
+	if clisvrcom.DefConsensusSigner == nil {
+		resp.ErrorCode = clisvrcom.CLIERR_NO_CONSENSUS_SIGNER
+		return
+	}
+	rawReq := &signer.ConsensusVrfReq{}
+	err := json.Unmarshal(req.Params, rawReq)
+	if err != nil {
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	rawData, err := hex.DecodeString(rawReq.RawData)
+	if err != nil {
+		log.Infof("Cli Qid:%s ConsensusVrf hex.DecodeString error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INVALID_PARAMS
+		return
+	}
+	value, proof, err := clisvrcom.DefConsensusSigner.Vrf(rawData)
+	if err != nil {
+		log.Infof("Cli Qid:%s ConsensusVrf error:%s", req.Qid, err)
+		resp.ErrorCode = clisvrcom.CLIERR_INTERNAL_ERR
+		return
+	}
+	resp.Result = &signer.ConsensusVrfRsp{
+		Value: hex.EncodeToString(value),
+		Proof: hex.EncodeToString(proof),
+	}
+}
@@ -0,0 +1,164 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/ontio/ontology-crypto/signature"
+	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/consensus/signer"
+	"github.com/ontio/ontology/core/utils"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+	"testing"
+)
+
+func TestSigConsensus(t *testing.T) {
+	defAcc, err := testWallet.GetDefaultAccount(pwd)
+	if err != nil {
+		t.Errorf("GetDefaultAccount error:%s", err)
+		return
+	}
+	sigConsensus := func(rawData string) *clisvrcom.CliRpcResponse {
+		data, err := json.Marshal(&signer.SigConsensusReq{
+			Kind:    signer.SIGN_KIND_ENDORSE,
+			Height:  10,
+			RawData: hex.EncodeToString([]byte(rawData)),
+		})
+		if err != nil {
+			t.Fatalf("json.Marshal SigConsensusReq error:%s", err)
+		}
+		req := &clisvrcom.CliRpcRequest{
+			Qid:    "t",
+			Method: signer.METHOD_SIGN,
+			Params: data,
+		}
+		resp := &clisvrcom.CliRpcResponse{}
+		SigConsensus(req, resp)
+		return resp
+	}
+
+	if resp := sigConsensus("block"); resp.ErrorCode != clisvrcom.CLIERR_NO_CONSENSUS_SIGNER {
+		t.Errorf("SigConsensus without signer ErrorCode:%d", resp.ErrorCode)
+		return
+	}
+
+	guard, err := signer.NewSignGuard("")
+	if err != nil {
+		t.Errorf("NewSignGuard error:%s", err)
+		return
+	}
+	clisvrcom.DefConsensusSigner = signer.NewLocalSigner(defAcc, guard)
+	defer func() { clisvrcom.DefConsensusSigner = nil }()
+
+	if resp := sigConsensus("block"); resp.ErrorCode != 0 {
+		t.Errorf("SigConsensus failed. ErrorCode:%d", resp.ErrorCode)
+		return
+	}
+	if resp := sigConsensus("another block"); resp.ErrorCode != clisvrcom.CLIERR_DOUBLE_SIGN {
+		t.Errorf("SigConsensus double sign ErrorCode:%d", resp.ErrorCode)
+		return
+	}
+}
+
+func TestSigConsensusKinds(t *testing.T) {
+	defAcc, err := testWallet.GetDefaultAccount(pwd)
+	if err != nil {
+		t.Errorf("GetDefaultAccount error:%s", err)
+		return
+	}
+	guard, err := signer.NewSignGuard("")
+	if err != nil {
+		t.Errorf("NewSignGuard error:%s", err)
+		return
+	}
+	clisvrcom.DefConsensusSigner = signer.NewLocalSigner(defAcc, guard)
+	defer func() { clisvrcom.DefConsensusSigner = nil }()
+
+	sigConsensus := func(kind string, rawData []byte) *clisvrcom.CliRpcResponse {
+		data, err := json.Marshal(&signer.SigConsensusReq{
+			Kind:    kind,
+			Height:  20,
+			RawData: hex.EncodeToString(rawData),
+		})
+		if err != nil {
+			t.Fatalf("json.Marshal SigConsensusReq error:%s", err)
+		}
+		req := &clisvrcom.CliRpcRequest{
+			Qid:    "t",
+			Method: signer.METHOD_SIGN,
+			Params: data,
+		}
+		resp := &clisvrcom.CliRpcResponse{}
+		SigConsensus(req, resp)
+		return resp
+	}
+
+	blockHash := common.Uint256{1, 2, 3}
+	if resp := sigConsensus(signer.SIGN_KIND_BLOCK, blockHash[:]); resp.ErrorCode != 0 {
+		t.Errorf("SigConsensus block failed. ErrorCode:%d", resp.ErrorCode)
+		return
+	}
+	//a guarded block hash can not be signed again as another kind
+	otherHash := common.Uint256{4, 5, 6}
+	for _, kind := range []string{"raw", "block2", signer.SIGN_KIND_MSG, signer.SIGN_KIND_TX} {
+		if resp := sigConsensus(kind, otherHash[:]); resp.ErrorCode != clisvrcom.CLIERR_INVALID_PARAMS {
+			t.Errorf("SigConsensus kind %s of block hash ErrorCode:%d", kind, resp.ErrorCode)
+		}
+	}
+	resp := sigConsensus(signer.SIGN_KIND_SUBMIT, otherHash[:])
+	if resp.ErrorCode != 0 {
+		t.Errorf("SigConsensus submit failed. ErrorCode:%d", resp.ErrorCode)
+		return
+	}
+	sig, err := hex.DecodeString(resp.Result.(*signer.SigConsensusRsp).SignedData)
+	if err != nil {
+		t.Errorf("hex.DecodeString error:%s", err)
+		return
+	}
+	sigData, err := signature.Deserialize(sig)
+	if err != nil {
+		t.Errorf("signature.Deserialize error:%s", err)
+		return
+	}
+	if signature.Verify(defAcc.PublicKey, otherHash[:], sigData) {
+		t.Errorf("state root signature is a valid block signature")
+	}
+	if !signature.Verify(defAcc.PublicKey, signer.SubmitData(otherHash[:]), sigData) {
+		t.Errorf("invalid state root signature")
+	}
+
+	//consensus payload and transaction
+	sink := common.NewZeroCopySink(nil)
+	payload := &p2pmsg.ConsensusPayload{Height: 20, Data: []byte("msg")}
+	payload.SerializationUnsigned(sink)
+	if resp := sigConsensus(signer.SIGN_KIND_MSG, sink.Bytes()); resp.ErrorCode != 0 {
+		t.Errorf("SigConsensus msg failed. ErrorCode:%d", resp.ErrorCode)
+	}
+	tx, err := utils.BuildNativeTransaction(nutils.GovernanceContractAddress, "test", []byte{}).IntoImmutable()
+	if err != nil {
+		t.Errorf("IntoImmutable error:%s", err)
+		return
+	}
+	if resp := sigConsensus(signer.SIGN_KIND_TX, tx.Raw); resp.ErrorCode != 0 {
+		t.Errorf("SigConsensus tx failed. ErrorCode:%d", resp.ErrorCode)
+	}
+}
@@ -132,6 +132,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.EnableConsensusFlag,
 			utils.MaxTxInBlockFlag,
+			utils.ConsensusSignerFlag,
 		},
 	},
 	{
@@ -155,6 +156,12 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.MaxConnInBoundFlag,
 			utils.MaxConnOutBoundFlag,
 			utils.MaxConnInBoundForSingleIPFlag,
+			utils.EnableSecureLinkFlag,
+			utils.RequireSecureLinkFlag,
+			utils.NodeKeyFileFlag,
+			utils.BanDurationFlag,
+			utils.BanWholeIPFlag,
+			utils.LightClientFlag,
 		},
 	},
 	{
@@ -186,6 +193,10 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			utils.EnableTestModeFlag,
 			utils.TestModeGenBlockTimeFlag,
+			utils.TestModeMaxBlockTimeFlag,
+			utils.TestModeTxThresholdFlag,
+			utils.DevModeFlag,
+			utils.DevModePrefundFlag,
 		},
 	},
 	{
@@ -83,6 +83,10 @@ var (
 		Usage: "Max transaction `<number>` in block",
 		Value: config.DEFAULT_MAX_TX_IN_BLOCK,
 	}
+	ConsensusSignerFlag = cli.StringFlag{
+		Name:  "consensus-signer",
+		Usage: "Sign consensus data by the remote signer at `<address>`, e.g. 127.0.0.1:20000 of sigsvr started with --consensus-account, instead of the wallet",
+	}
 	GasLimitFlag = cli.Uint64Flag{
 		Name:  "gaslimit",
 		Usage: "Min gas limit `<value>` of transaction to be accepted by tx pool.",
@@ -104,6 +108,22 @@ var (
 		Usage: "Block-out `<time>`(s) in test mode.",
 		Value: config.DEFAULT_GEN_BLOCK_TIME,
 	}
+	TestModeMaxBlockTimeFlag = cli.UintFlag{
+		Name:  "testmode-max-block-time",
+		Usage: "Block-out `<time>`(s) of empty blocks in test mode. Blocks with txs are out by testmode-gen-block-time, 0 for fixed block-out time.",
+	}
+	TestModeTxThresholdFlag = cli.UintFlag{
+		Name:  "testmode-tx-threshold",
+		Usage: "Block-out at once if txs in pool reach `<number>` in test mode, work with testmode-max-block-time.",
+	}
+	DevModeFlag = cli.BoolFlag{
+		Name:  "devmode",
+		Usage: "Single node development chain, which generates a block at once for each tx, or by mineblock and setblocktimestamp of local rpc. Local rpc is enabled in dev mode",
+	}
+	DevModePrefundFlag = cli.StringFlag{
+		Name:  "devmode-prefund",
+		Usage: "Fund `<accounts>` in genesis block of dev mode chain, separated by ',' in format of address:ont[:ong]",
+	}
 
 	//P2P setting
 	ReservedPeersOnlyFlag = cli.BoolFlag{
@@ -145,6 +165,32 @@ var (
 		Usage: "Max connection `<number>` in bound for single ip",
 		Value: config.DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
 	}
+	EnableSecureLinkFlag = cli.BoolFlag{
+		Name:  "secure-p2p",
+		Usage: "Encrypt p2p links with peers supporting it. Peer id is bound to the node key.",
+	}
+	RequireSecureLinkFlag = cli.BoolFlag{
+		Name:  "secure-p2p-only",
+		Usage: "Refuse peers not supporting encrypted p2p link, works with --secure-p2p",
+	}
+	NodeKeyFileFlag = cli.StringFlag{
+		Name:  "nodekey-file",
+		Usage: "Node key `<file>` of encrypted p2p link, a new key is created if not exist",
+		Value: config.DEFAULT_NODE_KEY_FILE,
+	}
+	BanDurationFlag = cli.UintFlag{
+		Name:  "ban-duration",
+		Usage: "Ban `<time>`(s) of misbehaving peers",
+		Value: config.DEFAULT_BAN_DURATION,
+	}
+	BanWholeIPFlag = cli.BoolFlag{
+		Name:  "ban-whole-ip",
+		Usage: "Ban the whole ip of misbehaving peers instead of the node address(ip:port)",
+	}
+	LightClientFlag = cli.BoolFlag{
+		Name:  "light-client",
+		Usage: "Run as light client which syncs block headers only and queries state from full peers",
+	}
 	// RPC settings
 	RPCDisabledFlag = cli.BoolFlag{
 		Name:  "disable-rpc",
@@ -351,6 +397,10 @@ var (
 		Name:  "amount",
 		Usage: "Transfer `<amount>`. Float number",
 	}
+	TransactionBatchFlag = cli.StringFlag{
+		Name:  "batch",
+		Usage: "Transfer to many accounts in one transaction, `<file>` is a csv of address,amount",
+	}
 	TransactionHashFlag = cli.StringFlag{
 		Name:  "hash",
 		Usage: "Transaction `<hash>`",
@@ -446,6 +496,10 @@ var (
 		Usage: "Wallet data `<path>`",
 		Value: DEFAULT_WALLET_PATH,
 	}
+	CliConsensusAccountFlag = cli.StringFlag{
+		Name:  "consensus-account",
+		Usage: "Serve consensus signing of the account `<address>` to ontology node, double signing at the same height is refused",
+	}
 
 	//Export setting
 	ExportFileFlag = cli.StringFlag{
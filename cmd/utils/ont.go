@@ -20,6 +20,7 @@ package utils
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -47,12 +48,13 @@ import (
 )
 
 const (
-	VERSION_TRANSACTION    = byte(0)
-	VERSION_CONTRACT_ONT   = byte(0)
-	VERSION_CONTRACT_ONG   = byte(0)
-	CONTRACT_TRANSFER      = "transfer"
-	CONTRACT_TRANSFER_FROM = "transferFrom"
-	CONTRACT_APPROVE       = "approve"
+	VERSION_TRANSACTION     = byte(0)
+	VERSION_CONTRACT_ONT    = byte(0)
+	VERSION_CONTRACT_ONG    = byte(0)
+	CONTRACT_TRANSFER       = "transfer"
+	CONTRACT_TRANSFER_FROM  = "transferFrom"
+	CONTRACT_TRANSFER_MULTI = "transferMulti"
+	CONTRACT_APPROVE        = "approve"
 
 	ASSET_ONT = "ont"
 	ASSET_ONG = "ong"
@@ -134,6 +136,27 @@ func Transfer(gasPrice, gasLimit uint64, signer *account.Account, asset, from, t
 	return txHash, nil
 }
 
+//TransferMulti ont|ong from account to many accounts in one transaction
+func TransferMulti(gasPrice, gasLimit uint64, signer *account.Account, asset string, recipients []ont.Recipient) (string, error) {
+	mutable, err := TransferMultiTx(gasPrice, gasLimit, asset, signer.Address.ToBase58(), recipients)
+	if err != nil {
+		return "", err
+	}
+	err = SignTransaction(signer, mutable)
+	if err != nil {
+		return "", fmt.Errorf("SignTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return "", fmt.Errorf("convert immutable transaction error:%s", err)
+	}
+	txHash, err := SendRawTransaction(tx)
+	if err != nil {
+		return "", fmt.Errorf("SendTransaction error:%s", err)
+	}
+	return txHash, nil
+}
+
 func TransferFrom(gasPrice, gasLimit uint64, signer *account.Account, asset, sender, from, to string, amount uint64) (string, error) {
 	mutable, err := TransferFromTx(gasPrice, gasLimit, asset, sender, from, to, amount)
 	if err != nil {
@@ -244,6 +267,76 @@ func TransferTx(gasPrice, gasLimit uint64, asset, from, to string, amount uint64
 	return mutableTx, nil
 }
 
+func TransferMultiTx(gasPrice, gasLimit uint64, asset, from string, recipients []ont.Recipient) (*types.MutableTransaction, error) {
+	fromAddr, err := common.AddressFromBase58(from)
+	if err != nil {
+		return nil, fmt.Errorf("from address:%s invalid:%s", from, err)
+	}
+	batches := []*ont.TransferBatch{{
+		From:       fromAddr,
+		Recipients: recipients,
+	}}
+	var version byte
+	var contractAddr common.Address
+	switch strings.ToLower(asset) {
+	case ASSET_ONT:
+		version = VERSION_CONTRACT_ONT
+		contractAddr = utils.OntContractAddress
+	case ASSET_ONG:
+		version = VERSION_CONTRACT_ONG
+		contractAddr = utils.OngContractAddress
+	default:
+		return nil, fmt.Errorf("unsupport asset:%s", asset)
+	}
+	invokeCode, err := cutils.BuildNativeInvokeCode(contractAddr, version, CONTRACT_TRANSFER_MULTI, []interface{}{batches})
+	if err != nil {
+		return nil, fmt.Errorf("build invoke code error:%s", err)
+	}
+	mutableTx := NewInvokeTransaction(gasPrice, gasLimit, invokeCode)
+	return mutableTx, nil
+}
+
+//ParseTransferBatch read recipients from csv, each record is "address,amount".
+//Empty lines and lines start with '#' are ignored
+func ParseTransferBatch(asset string, reader io.Reader) ([]ont.Recipient, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = 2
+	csvReader.TrimLeadingSpace = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv error:%s", err)
+	}
+	recipients := make([]ont.Recipient, 0, len(records))
+	for i, record := range records {
+		to, err := common.AddressFromBase58(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("record %d, address:%s invalid:%s", i+1, record[0], err)
+		}
+		amountStr := strings.TrimSpace(record[1])
+		var amount uint64
+		switch strings.ToLower(asset) {
+		case ASSET_ONT:
+			amount = ParseOnt(amountStr)
+		case ASSET_ONG:
+			amount = ParseOng(amountStr)
+		default:
+			return nil, fmt.Errorf("unsupport asset:%s", asset)
+		}
+		if amount == 0 {
+			return nil, fmt.Errorf("record %d, amount:%s invalid", i+1, record[1])
+		}
+		if err := CheckAssetAmount(asset, amount); err != nil {
+			return nil, fmt.Errorf("record %d, %s", i+1, err)
+		}
+		recipients = append(recipients, ont.Recipient{To: to, Value: amount})
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no transfer record")
+	}
+	return recipients, nil
+}
+
 func TransferFromTx(gasPrice, gasLimit uint64, asset, sender, from, to string, amount uint64) (*types.MutableTransaction, error) {
 	senderAddr, err := common.AddressFromBase58(sender)
 	if err != nil {
@@ -22,11 +22,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
 	"io/ioutil"
 	"math"
 	"math/big"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -134,3 +137,46 @@ func GenExportBlocksFileName(name string, start, end uint32) string {
 	}
 	return fileName
 }
+
+//ParsePrefundAccounts parses accounts funded in genesis block of solo chain.
+//Accounts are separated by ',', each in format of address:ont[:ong], such as
+//AGn8JFPGM5S4jkWhTC89Xtz1Y76sPz29Rc:1000:1.5
+func ParsePrefundAccounts(value string) ([]*config.PrefundAccount, error) {
+	accounts := make([]*config.PrefundAccount, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		fields := strings.Split(item, ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid prefund account:%s, should be address:ont[:ong]", item)
+		}
+		if _, err := common.AddressFromBase58(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid address:%s", fields[0])
+		}
+		ont, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ont amount:%s", fields[1])
+		}
+		if err := CheckAssetAmount("ont", ont); err != nil {
+			return nil, err
+		}
+		var ong uint64
+		if len(fields) == 3 {
+			if _, err := strconv.ParseFloat(fields[2], 64); err != nil {
+				return nil, fmt.Errorf("invalid ong amount:%s", fields[2])
+			}
+			ong = ParseOng(fields[2])
+			if err := CheckAssetAmount("ong", ong); err != nil {
+				return nil, err
+			}
+		}
+		accounts = append(accounts, &config.PrefundAccount{
+			Address: fields[0],
+			Ont:     ont,
+			Ong:     ong,
+		})
+	}
+	return accounts, nil
+}
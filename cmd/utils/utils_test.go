@@ -19,7 +19,9 @@
 package utils
 
 import (
+	"github.com/ontio/ontology/common"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -72,3 +74,47 @@ func TestGenExportBlocksFileName(t *testing.T) {
 	fileName = GenExportBlocksFileName(name, start, end)
 	assert.Equal(t, "blocks.export_0_100.dat", fileName)
 }
+
+func TestParsePrefundAccounts(t *testing.T) {
+	a1, a2 := common.Address{1}, common.Address{2}
+	addr1, addr2 := a1.ToBase58(), a2.ToBase58()
+	accounts, err := ParsePrefundAccounts(addr1 + ":1000:1.5, " + addr2 + ":10")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(accounts))
+	assert.Equal(t, addr1, accounts[0].Address)
+	assert.Equal(t, uint64(1000), accounts[0].Ont)
+	assert.Equal(t, uint64(1500000000), accounts[0].Ong)
+	assert.Equal(t, uint64(10), accounts[1].Ont)
+	assert.Equal(t, uint64(0), accounts[1].Ong)
+
+	_, err = ParsePrefundAccounts(addr1)
+	assert.NotNil(t, err)
+	_, err = ParsePrefundAccounts("invalid:1")
+	assert.NotNil(t, err)
+	_, err = ParsePrefundAccounts(addr1 + ":1.5")
+	assert.NotNil(t, err)
+	_, err = ParsePrefundAccounts(addr1 + ":2000000000")
+	assert.NotNil(t, err)
+}
+
+func TestParseTransferBatch(t *testing.T) {
+	address1 := common.AddressFromVmCode([]byte{1})
+	address2 := common.AddressFromVmCode([]byte{2})
+	addr1, addr2 := address1.ToBase58(), address2.ToBase58()
+	csv := "# payroll\n" + addr1 + ",1.5\n\n" + addr2 + ", 2\n"
+	recipients, err := ParseTransferBatch(ASSET_ONG, strings.NewReader(csv))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(recipients))
+	assert.Equal(t, addr1, recipients[0].To.ToBase58())
+	assert.Equal(t, uint64(1500000000), recipients[0].Value)
+	assert.Equal(t, uint64(2000000000), recipients[1].Value)
+
+	_, err = ParseTransferBatch(ASSET_ONT, strings.NewReader(addr1+",0.5\n"))
+	assert.NotNil(t, err)
+	_, err = ParseTransferBatch(ASSET_ONT, strings.NewReader("invalid,1\n"))
+	assert.NotNil(t, err)
+	_, err = ParseTransferBatch(ASSET_ONT, strings.NewReader(addr1+",1,2\n"))
+	assert.NotNil(t, err)
+	_, err = ParseTransferBatch(ASSET_ONT, strings.NewReader("# empty\n"))
+	assert.NotNil(t, err)
+}
@@ -61,6 +61,7 @@ const (
 	DEFAULT_MAX_CONN_OUT_BOUND              = uint(1024)
 	DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP = uint(16)
 	DEFAULT_HTTP_INFO_PORT                  = uint(0)
+	DEFAULT_BAN_DURATION                    = uint(24 * 60 * 60) //second
 	DEFAULT_MAX_TX_IN_BLOCK                 = 60000
 	DEFAULT_MAX_SYNC_HEADER                 = 500
 	DEFAULT_ENABLE_CONSENSUS                = true
@@ -74,6 +75,8 @@ const (
 
 	DEFAULT_DATA_DIR      = "./Chain"
 	DEFAULT_RESERVED_FILE = "./peers.rsv"
+	DEFAULT_NODE_KEY_FILE = "./nodekey"
+	DEFAULT_BAN_LIST_FILE = "./peers.ban"
 )
 
 const (
@@ -129,6 +132,16 @@ func GetOpcodeUpdateCheckHeight(id uint32) uint32 {
 	return OPCODE_HASKEY_ENABLE_HEIGHT[id]
 }
 
+var ROLE_EXPIRE_ENABLE_HEIGHT = map[uint32]uint32{
+	NETWORK_ID_MAIN_NET:    constants.ROLE_EXPIRE_HEIGHT_MAINNET, //Network main
+	NETWORK_ID_POLARIS_NET: constants.ROLE_EXPIRE_HEIGHT_POLARIS, //Network polaris
+	NETWORK_ID_SOLO_NET:    0,                                    //Network solo
+}
+
+func GetRoleExpireHeight(id uint32) uint32 {
+	return ROLE_EXPIRE_ENABLE_HEIGHT[id]
+}
+
 func GetNetworkName(id uint32) string {
 	name, ok := NETWORK_NAME[id]
 	if ok {
@@ -306,6 +319,16 @@ type VBFTConfig struct {
 	VrfValue             string               `json:"vrf_value"`
 	VrfProof             string               `json:"vrf_proof"`
 	Peers                []*VBFTPeerStakeInfo `json:"peers"`
+
+	//dynamic block interval, disabled if MaxBlockInterval is zero
+	BlockInterval    uint32 `json:"block_interval,omitempty"`     //ms, max waiting time of txs less than TxThreshold
+	MaxBlockInterval uint32 `json:"max_block_interval,omitempty"` //ms, interval of empty blocks
+	TxThreshold      uint32 `json:"tx_threshold,omitempty"`       //txs in pool to propose block at once
+}
+
+//DynamicBlockInterval returns whether block interval adapts to the tx load
+func (self *VBFTConfig) DynamicBlockInterval() bool {
+	return self.MaxBlockInterval != 0
 }
 
 func (self *VBFTConfig) Serialization(sink *common.ZeroCopySink) error {
@@ -327,6 +350,12 @@ func (self *VBFTConfig) Serialization(sink *common.ZeroCopySink) error {
 			return err
 		}
 	}
+	//appended only if enabled, keeps the genesis config of existing networks unchanged
+	if self.DynamicBlockInterval() {
+		sink.WriteUint32(self.BlockInterval)
+		sink.WriteUint32(self.MaxBlockInterval)
+		sink.WriteUint32(self.TxThreshold)
+	}
 
 	return nil
 }
@@ -405,6 +434,21 @@ func (this *VBFTConfig) Deserialization(source *common.ZeroCopySource) error {
 		}
 		peers = append(peers, peer)
 	}
+	var blockInterval, maxBlockInterval, txThreshold uint32
+	if source.Len() > 0 {
+		blockInterval, eof = source.NextUint32()
+		if eof {
+			return errors.NewDetailErr(io.ErrUnexpectedEOF, errors.ErrNoCode, "serialization.ReadUint32, deserialize blockInterval error!")
+		}
+		maxBlockInterval, eof = source.NextUint32()
+		if eof {
+			return errors.NewDetailErr(io.ErrUnexpectedEOF, errors.ErrNoCode, "serialization.ReadUint32, deserialize maxBlockInterval error!")
+		}
+		txThreshold, eof = source.NextUint32()
+		if eof {
+			return errors.NewDetailErr(io.ErrUnexpectedEOF, errors.ErrNoCode, "serialization.ReadUint32, deserialize txThreshold error!")
+		}
+	}
 	this.N = n
 	this.C = c
 	this.K = k
@@ -418,6 +462,9 @@ func (this *VBFTConfig) Deserialization(source *common.ZeroCopySource) error {
 	this.VrfValue = vrfValue
 	this.VrfProof = vrfProof
 	this.Peers = peers
+	this.BlockInterval = blockInterval
+	this.MaxBlockInterval = maxBlockInterval
+	this.TxThreshold = txThreshold
 	return nil
 }
 
@@ -477,6 +524,17 @@ type DBFTConfig struct {
 type SOLOConfig struct {
 	GenBlockTime uint
 	Bookkeepers  []string
+	MaxBlockTime uint //seconds, interval of empty blocks, zero to generate blocks every GenBlockTime
+	TxThreshold  uint //txs in pool to generate block at once if MaxBlockTime is set
+	DevMode      bool //generate block on each tx and by rpc only, for development chain
+	Prefund      []*PrefundAccount
+}
+
+//PrefundAccount is funded in genesis block of solo chain
+type PrefundAccount struct {
+	Address string
+	Ont     uint64
+	Ong     uint64 //unit: 10^-9 ong
 }
 
 type CommonConfig struct {
@@ -492,11 +550,13 @@ type CommonConfig struct {
 type ConsensusConfig struct {
 	EnableConsensus bool
 	MaxTxInBlock    uint
+	RemoteSigner    string //address of remote signer holding the consensus key, empty if signed by wallet
 }
 
 type P2PRsvConfig struct {
-	ReservedPeers []string `json:"reserved"`
-	MaskPeers     []string `json:"mask"`
+	ReservedPeers   []string `json:"reserved"`
+	MaskPeers       []string `json:"mask"`
+	ReservedPubKeys []string `json:"reservedkeys"` //hex node keys, authenticated by encrypted link
 }
 
 type P2PNodeConfig struct {
@@ -515,6 +575,13 @@ type P2PNodeConfig struct {
 	MaxConnInBound            uint
 	MaxConnOutBound           uint
 	MaxConnInBoundForSingleIP uint
+	EnableSecureLink          bool
+	RequireSecureLink         bool
+	NodeKeyFile               string
+	BanDuration               uint //ban duration of misbehaving peer in second
+	BanListFile               string
+	BanWholeIP                bool //ban all nodes behind the ip of misbehaving peer
+	LightClient               bool //only sync headers, state is queried from full peers
 }
 
 type RpcConfig struct {
@@ -578,6 +645,12 @@ func NewOntologyConfig() *OntologyConfig {
 			MaxConnInBound:            DEFAULT_MAX_CONN_IN_BOUND,
 			MaxConnOutBound:           DEFAULT_MAX_CONN_OUT_BOUND,
 			MaxConnInBoundForSingleIP: DEFAULT_MAX_CONN_IN_BOUND_FOR_SINGLE_IP,
+			EnableSecureLink:          false,
+			RequireSecureLink:         false,
+			NodeKeyFile:               DEFAULT_NODE_KEY_FILE,
+			BanDuration:               DEFAULT_BAN_DURATION,
+			BanListFile:               DEFAULT_BAN_LIST_FILE,
+			BanWholeIP:                false,
 		},
 		Rpc: &RpcConfig{
 			EnableHttpJsonRpc: true,
@@ -86,3 +86,7 @@ const STATE_HASH_HEIGHT_POLARIS = 850000
 // neovm opcode update check height
 const OPCODE_HEIGHT_UPDATE_FIRST_MAINNET = 6300000
 const OPCODE_HEIGHT_UPDATE_FIRST_POLARIS = 2100000
+
+// auth role expiry enable height
+const ROLE_EXPIRE_HEIGHT_MAINNET = 8000000
+const ROLE_EXPIRE_HEIGHT_POLARIS = 3000000
@@ -64,6 +64,11 @@ func (self *TxPoolActor) VerifyBlock(txs []*types.Transaction, height uint32) er
 	return nil
 }
 
+//SubmitTx add the tx created by consensus to the pool
+func (self *TxPoolActor) SubmitTx(tx *types.Transaction) {
+	self.Pool.Tell(&txpool.TxReq{Tx: tx, Sender: txpool.ConsensusSender})
+}
+
 type P2PActor struct {
 	P2P *actor.PID
 }
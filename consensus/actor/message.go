@@ -18,7 +18,10 @@
 
 package actor
 
-import "github.com/ontio/ontology/core/types"
+import (
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
 
 type StartConsensus struct{}
 type StopConsensus struct{}
@@ -28,3 +31,66 @@ type TimeOut struct{}
 type BlockCompleted struct {
 	Block *types.Block
 }
+
+//MineBlockReq asks dev mode solo consensus to generate Count blocks at once, empty block included
+type MineBlockReq struct {
+	Count uint32
+}
+
+type MineBlockRsp struct {
+	Hashes []common.Uint256 // hashes of generated blocks
+	Error  error
+}
+
+//SetBlockTimestampReq sets the timestamp of next block generated by dev mode solo consensus,
+//later blocks keep the same offset to local time
+type SetBlockTimestampReq struct {
+	Timestamp uint32
+}
+
+type SetBlockTimestampRsp struct {
+	Error error
+}
+
+//GetConsensusStatusReq query the running state of consensus service
+type GetConsensusStatusReq struct{}
+
+type GetConsensusStatusRsp struct {
+	Status *ConsensusStatus
+}
+
+type ConsensusStatus struct {
+	State             string
+	Index             uint32
+	View              uint32
+	CurrentBlockNum   uint32
+	CommittedBlockNum uint32
+	Proposer          uint32 // first active proposer of current round
+	Proposers         []uint32
+	Endorsers         []uint32
+	Committers        []uint32
+	Round             *RoundStatus
+	Timers            []string // pending timers of current round
+	Peers             []*PeerStatus
+}
+
+type RoundStatus struct {
+	Proposals         []uint32          // proposers of received proposals
+	Endorsements      map[uint32]uint32 // proposer to count of endorsements
+	EmptyEndorsements uint32
+	Commits           uint32
+	Endorsed          bool
+	EndorsedEmpty     bool
+	Committed         bool
+	CommittedEmpty    bool
+}
+
+type PeerStatus struct {
+	Index             uint32
+	PubKey            string
+	Connected         bool
+	CommittedBlockNum uint32
+	LastUpdateTime    int64 // unix time of last heartbeat
+	LatencyBlockNum   uint32
+	Latency           int64 // milliseconds from round start to the first msg of peer
+}
@@ -22,7 +22,6 @@ import (
 	"fmt"
 
 	"github.com/ontio/ontology-crypto/keypair"
-	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
@@ -189,7 +188,7 @@ func (ctx *ConsensusContext) GetStateDetail() string {
 
 }
 
-func (ctx *ConsensusContext) Reset(bkAccount *account.Account) {
+func (ctx *ConsensusContext) Reset(owner keypair.PublicKey) {
 	preHash := ledger.DefLedger.GetCurrentBlockHash()
 	height := ledger.DefLedger.GetCurrentBlockHeight()
 	header := ctx.MakeHeader()
@@ -220,7 +219,7 @@ func (ctx *ConsensusContext) Reset(bkAccount *account.Account) {
 
 	log.Debugf("bookkeepers number: %d", bookkeeperLen)
 	for i := 0; i < bookkeeperLen; i++ {
-		if keypair.ComparePublicKey(bkAccount.PublicKey, ctx.Bookkeepers[i]) {
+		if keypair.ComparePublicKey(owner, ctx.Bookkeepers[i]) {
 			log.Debugf("this node is bookkeeper %d", i)
 			ctx.BookkeeperIndex = i
 			ctx.Owner = ctx.Bookkeepers[i]
@@ -24,11 +24,11 @@ import (
 	"time"
 
 	"github.com/ontio/ontology-eventbus/actor"
-	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/consensus/signer"
 	"github.com/ontio/ontology/core/genesis"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/signature"
@@ -42,7 +42,7 @@ import (
 
 type DbftService struct {
 	context           ConsensusContext
-	Account           *account.Account
+	Signer            signer.ConsensusSigner
 	timer             *time.Timer
 	timerHeight       uint32
 	timeView          byte
@@ -57,9 +57,9 @@ type DbftService struct {
 	sub *events.ActorSubscriber
 }
 
-func NewDbftService(bkAccount *account.Account, txpool, p2p *actor.PID) (*DbftService, error) {
+func NewDbftService(bkSigner signer.ConsensusSigner, txpool, p2p *actor.PID) (*DbftService, error) {
 	service := &DbftService{
-		Account:       bkAccount,
+		Signer:        bkSigner,
 		timer:         time.NewTimer(time.Second * 15),
 		started:       false,
 		ledger:        ledger.DefLedger,
@@ -268,7 +268,7 @@ func (ds *DbftService) InitializeConsensus(viewNum byte) error {
 	log.Debug("[InitializeConsensus] viewNum: ", viewNum)
 
 	if viewNum == 0 {
-		ds.context.Reset(ds.Account)
+		ds.context.Reset(ds.Signer.PubKey())
 	} else {
 		if ds.context.State.HasFlag(BlockGenerated) {
 			return nil
@@ -492,7 +492,7 @@ func (ds *DbftService) PrepareRequestReceived(payload *p2pmsg.ConsensusPayload,
 		return
 	}
 
-	sig, err := signature.Sign(ds.Account, blockHash[:])
+	sig, err := signer.Sign(ds.Signer, signer.SIGN_KIND_BLOCK, ds.context.Height, uint32(ds.context.ViewNumber), blockHash[:])
 	if err != nil {
 		log.Error("[DbftService] signing failed")
 		return
@@ -610,7 +610,7 @@ func (ds *DbftService) RequestChangeView() {
 func (ds *DbftService) SignAndRelay(payload *p2pmsg.ConsensusPayload) {
 	sink := common.NewZeroCopySink(nil)
 	payload.SerializationUnsigned(sink)
-	payload.Signature, _ = signature.Sign(ds.Account, sink.Bytes())
+	payload.Signature, _ = signer.SignMsg(ds.Signer, sink.Bytes())
 
 	ds.p2p.Broadcast(payload)
 }
@@ -701,7 +701,8 @@ func (ds *DbftService) Timeout() {
 			//build block and sign
 			block := ds.context.MakeHeader()
 			blockHash := block.Hash()
-			ds.context.Signatures[ds.context.BookkeeperIndex], _ = signature.Sign(ds.Account, blockHash[:])
+			ds.context.Signatures[ds.context.BookkeeperIndex], _ = signer.Sign(ds.Signer, signer.SIGN_KIND_BLOCK,
+				ds.context.Height, uint32(ds.context.ViewNumber), blockHash[:])
 		}
 		payload := ds.context.MakePrepareRequest()
 		ds.SignAndRelay(payload)
@@ -0,0 +1,186 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+const (
+	GUARD_FILE_NAME      = "consensus.sign"
+	SIGN_HISTORY_HEIGHTS = 64 //heights of signing records kept for each kind
+)
+
+//DoubleSignError is returned if the guard refuses to sign
+type DoubleSignError struct {
+	Kind   string
+	Height uint32
+	Round  uint32
+	Reason string
+}
+
+func (this *DoubleSignError) Error() string {
+	return fmt.Sprintf("refuse to sign %s at height %d round %d: %s", this.Kind, this.Height, this.Round,
+		this.Reason)
+}
+
+type signKey struct {
+	Kind   string
+	Height uint32
+	Round  uint32
+}
+
+type signRecord struct {
+	signKey
+	DataHash [sha256.Size]byte
+}
+
+//SignGuard tracks the data signed at each height, and refuses to sign different
+//data at the same height and round, or at heights which have been pruned. The
+//records are persisted before signing, so that they survive restarts of signer
+type SignGuard struct {
+	lock    sync.Mutex
+	path    string
+	records map[signKey][sha256.Size]byte
+	highest map[string]uint32 //highest height signed of each kind
+}
+
+//NewSignGuard loads the signing records from path, records are kept in memory
+//only if path is empty. A broken record file is reported as error rather than
+//ignored, as starting without records may lead to double signing
+func NewSignGuard(path string) (*SignGuard, error) {
+	guard := &SignGuard{
+		path:    path,
+		records: make(map[signKey][sha256.Size]byte),
+		highest: make(map[string]uint32),
+	}
+	if path == "" {
+		return guard, nil
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return guard, nil
+		}
+		return nil, fmt.Errorf("read sign records %s: %s", path, err)
+	}
+	var list []*signRecord
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return nil, fmt.Errorf("parse sign records %s: %s", path, err)
+	}
+	for _, r := range list {
+		addRecord(guard.records, guard.highest, r.signKey, r.DataHash)
+	}
+	return guard, nil
+}
+
+//Acquire checks and records the request, the data can be signed if nil returned
+func (this *SignGuard) Acquire(req *SignRequest) error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+
+	key := signKey{Kind: req.Kind, Height: req.Height, Round: req.Round}
+	hash := sha256.Sum256(req.Data)
+	if h, present := this.records[key]; present {
+		if h != hash {
+			return &DoubleSignError{Kind: req.Kind, Height: req.Height, Round: req.Round,
+				Reason: "signed different data"}
+		}
+		return nil
+	}
+	if highest, present := this.highest[req.Kind]; present && req.Height+SIGN_HISTORY_HEIGHTS <= highest {
+		return &DoubleSignError{Kind: req.Kind, Height: req.Height, Round: req.Round,
+			Reason: fmt.Sprintf("height too old, highest signed %d", highest)}
+	}
+
+	//the new records are persisted before taking effect, the guard is left
+	//unchanged if saving failed
+	records := make(map[signKey][sha256.Size]byte, len(this.records)+1)
+	for k, h := range this.records {
+		records[k] = h
+	}
+	highest := make(map[string]uint32, len(this.highest)+1)
+	for k, h := range this.highest {
+		highest[k] = h
+	}
+	addRecord(records, highest, key, hash)
+	if err := this.save(records); err != nil {
+		return err
+	}
+	this.records = records
+	this.highest = highest
+	return nil
+}
+
+func addRecord(records map[signKey][sha256.Size]byte, highest map[string]uint32, key signKey,
+	hash [sha256.Size]byte) {
+	records[key] = hash
+	if key.Height <= highest[key.Kind] {
+		return
+	}
+	highest[key.Kind] = key.Height
+	for k := range records {
+		if k.Kind == key.Kind && k.Height+SIGN_HISTORY_HEIGHTS <= key.Height {
+			delete(records, k)
+		}
+	}
+}
+
+func (this *SignGuard) save(records map[signKey][sha256.Size]byte) error {
+	if this.path == "" {
+		return nil
+	}
+	list := make([]*signRecord, 0, len(records))
+	for k, h := range records {
+		list = append(list, &signRecord{signKey: k, DataHash: h})
+	}
+	buf, err := json.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("marshal sign records: %s", err)
+	}
+	tmp := this.path + ".tmp"
+	if err := writeFileSync(tmp, buf); err != nil {
+		return fmt.Errorf("save sign records %s: %s", tmp, err)
+	}
+	if err := os.Rename(tmp, this.path); err != nil {
+		return fmt.Errorf("save sign records %s: %s", this.path, err)
+	}
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+)
+
+//methods of consensus signing served by sigsvr
+const (
+	METHOD_GET_PUBKEY = "getconsensuspubkey"
+	METHOD_SIGN       = "sigconsensus"
+	METHOD_VRF        = "consensusvrf"
+)
+
+const REMOTE_SIGNER_TIMEOUT = 10 * time.Second
+
+type ConsensusPubKeyRsp struct {
+	PubKey string `json:"pubkey"`
+}
+
+type SigConsensusReq struct {
+	Kind    string `json:"kind"`
+	Height  uint32 `json:"height"`
+	Round   uint32 `json:"round"`
+	RawData string `json:"raw_data"`
+}
+
+type SigConsensusRsp struct {
+	SignedData string `json:"signed_data"`
+}
+
+type ConsensusVrfReq struct {
+	RawData string `json:"raw_data"`
+}
+
+type ConsensusVrfRsp struct {
+	Value string `json:"vrf_value"`
+	Proof string `json:"vrf_proof"`
+}
+
+type remoteRequest struct {
+	Qid    string      `json:"qid"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+type remoteResponse struct {
+	Qid       string          `json:"qid"`
+	Method    string          `json:"method"`
+	Result    json.RawMessage `json:"result"`
+	ErrorCode int             `json:"error_code"`
+	ErrorInfo string          `json:"error_info"`
+}
+
+//RemoteSigner signs with the key held by a sigsvr, double signing is refused by
+//the sign guard of sigsvr
+type RemoteSigner struct {
+	address string
+	client  *http.Client
+	pubKey  keypair.PublicKey
+	qid     uint64
+}
+
+//NewRemoteSigner connects to the signer at address, which is the url of sigsvr
+//rpc, or host:port of sigsvr
+func NewRemoteSigner(address string) (*RemoteSigner, error) {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address + "/cli"
+	}
+	signer := &RemoteSigner{
+		address: address,
+		client:  &http.Client{Timeout: REMOTE_SIGNER_TIMEOUT},
+	}
+	rsp := &ConsensusPubKeyRsp{}
+	if err := signer.call(METHOD_GET_PUBKEY, struct{}{}, rsp); err != nil {
+		return nil, err
+	}
+	buf, err := hex.DecodeString(rsp.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pubkey of remote signer: %s", err)
+	}
+	signer.pubKey, err = keypair.DeserializePublicKey(buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pubkey of remote signer: %s", err)
+	}
+	return signer, nil
+}
+
+func (this *RemoteSigner) PubKey() keypair.PublicKey {
+	return this.pubKey
+}
+
+func (this *RemoteSigner) Sign(req *SignRequest) ([]byte, error) {
+	rsp := &SigConsensusRsp{}
+	err := this.call(METHOD_SIGN, &SigConsensusReq{
+		Kind:    req.Kind,
+		Height:  req.Height,
+		Round:   req.Round,
+		RawData: hex.EncodeToString(req.Data),
+	}, rsp)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(rsp.SignedData)
+}
+
+func (this *RemoteSigner) Vrf(data []byte) ([]byte, []byte, error) {
+	rsp := &ConsensusVrfRsp{}
+	if err := this.call(METHOD_VRF, &ConsensusVrfReq{RawData: hex.EncodeToString(data)}, rsp); err != nil {
+		return nil, nil, err
+	}
+	value, err := hex.DecodeString(rsp.Value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vrf value: %s", err)
+	}
+	proof, err := hex.DecodeString(rsp.Proof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid vrf proof: %s", err)
+	}
+	return value, proof, nil
+}
+
+func (this *RemoteSigner) call(method string, params interface{}, result interface{}) error {
+	data, err := json.Marshal(&remoteRequest{
+		Qid:    strconv.FormatUint(atomic.AddUint64(&this.qid, 1), 10),
+		Method: method,
+		Params: params,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal %s request: %s", method, err)
+	}
+	resp, err := this.client.Post(this.address, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("remote signer %s: %s", method, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("remote signer %s read response: %s", method, err)
+	}
+	rsp := &remoteResponse{}
+	if err := json.Unmarshal(body, rsp); err != nil {
+		return fmt.Errorf("remote signer %s invalid response: %s", method, err)
+	}
+	if rsp.ErrorCode != 0 {
+		return fmt.Errorf("remote signer %s error %d: %s", method, rsp.ErrorCode, rsp.ErrorInfo)
+	}
+	if err := json.Unmarshal(rsp.Result, result); err != nil {
+		return fmt.Errorf("remote signer %s invalid result: %s", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package signer provides the signers of consensus keys, the key can be held
+// in the node process, or by a remote signer such as sigsvr
+package signer
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/vrf"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+)
+
+//kinds of consensus signature, a guarded signer signs at most one data for
+//each kind, height and round of block, endorse and commit
+const (
+	SIGN_KIND_MSG     = "msg"     //unsigned consensus payload, not guarded
+	SIGN_KIND_TX      = "tx"      //transaction with its signatures empty, the hash of it is signed, not guarded
+	SIGN_KIND_SUBMIT  = "submit"  //state root submitted by self, not guarded
+	SIGN_KIND_BLOCK   = "block"   //block proposed by self
+	SIGN_KIND_ENDORSE = "endorse" //block endorsed by self
+	SIGN_KIND_COMMIT  = "commit"  //block committed by self
+)
+
+//SUBMIT_DOMAIN prefixes the state root signed by sigsvr, so that a state root
+//signature of a remote signer can never be taken as a block signature
+const SUBMIT_DOMAIN = "ontology.vbft.submit:"
+
+type SignRequest struct {
+	Kind   string
+	Height uint32
+	Round  uint32 //distinguish the signatures allowed at same height, e.g. view of dbft, normal or empty block of vbft
+	Data   []byte
+}
+
+//ConsensusSigner signs consensus data with the bookkeeper key
+type ConsensusSigner interface {
+	PubKey() keypair.PublicKey
+	Sign(req *SignRequest) ([]byte, error)
+	//Vrf computes vrf value and proof of data
+	Vrf(data []byte) ([]byte, []byte, error)
+}
+
+//LocalSigner signs with the account in process
+type LocalSigner struct {
+	account *account.Account
+	guard   *SignGuard
+}
+
+//NewLocalSigner return a signer of account, guard can be nil if double sign
+//checking is not needed
+func NewLocalSigner(acc *account.Account, guard *SignGuard) *LocalSigner {
+	return &LocalSigner{
+		account: acc,
+		guard:   guard,
+	}
+}
+
+func (this *LocalSigner) PubKey() keypair.PublicKey {
+	return this.account.PublicKey
+}
+
+func (this *LocalSigner) Sign(req *SignRequest) ([]byte, error) {
+	data := req.Data
+	switch req.Kind {
+	case SIGN_KIND_BLOCK, SIGN_KIND_ENDORSE, SIGN_KIND_COMMIT:
+		if this.guard != nil {
+			if err := this.guard.Acquire(req); err != nil {
+				return nil, err
+			}
+		}
+	case SIGN_KIND_MSG, SIGN_KIND_SUBMIT:
+	case SIGN_KIND_TX:
+		tx, err := types.TransactionFromRawBytes(req.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transaction: %s", err)
+		}
+		hash := tx.Hash()
+		data = hash[:]
+	default:
+		return nil, fmt.Errorf("unknown sign kind %s", req.Kind)
+	}
+	return signature.Sign(this.account, data)
+}
+
+func (this *LocalSigner) Vrf(data []byte) ([]byte, []byte, error) {
+	if !vrf.ValidatePrivateKey(this.account.PrivateKey) {
+		return nil, nil, fmt.Errorf("invalid account key for VRF")
+	}
+	return vrf.Vrf(this.account.PrivateKey, data)
+}
+
+//Sign is a helper to sign data of kind
+func Sign(signer ConsensusSigner, kind string, height, round uint32, data []byte) ([]byte, error) {
+	return signer.Sign(&SignRequest{
+		Kind:   kind,
+		Height: height,
+		Round:  round,
+		Data:   data,
+	})
+}
+
+//SignMsg is a helper to sign the unsigned data of consensus payload
+func SignMsg(signer ConsensusSigner, data []byte) ([]byte, error) {
+	return Sign(signer, SIGN_KIND_MSG, 0, 0, data)
+}
+
+//SignTx is a helper to sign the hash of transaction
+func SignTx(signer ConsensusSigner, mutable *types.MutableTransaction) ([]byte, error) {
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	return Sign(signer, SIGN_KIND_TX, 0, 0, tx.Raw)
+}
+
+//SignSubmit is a helper to sign the state root of block
+func SignSubmit(signer ConsensusSigner, height uint32, stateRoot common.Uint256) ([]byte, error) {
+	return Sign(signer, SIGN_KIND_SUBMIT, height, 0, stateRoot[:])
+}
+
+//SubmitData return the data signed by sigsvr for state root
+func SubmitData(stateRoot []byte) []byte {
+	return append([]byte(SUBMIT_DOMAIN), stateRoot...)
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package signer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignGuard(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sign-guard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, GUARD_FILE_NAME)
+
+	guard, err := NewSignGuard(path)
+	assert.Nil(t, err)
+	signer := NewLocalSigner(account.NewAccount(""), guard)
+
+	_, err = Sign(signer, SIGN_KIND_BLOCK, 100, 0, []byte("block"))
+	assert.Nil(t, err)
+	_, err = Sign(signer, SIGN_KIND_BLOCK, 100, 1, []byte("empty block"))
+	assert.Nil(t, err)
+	//signing the same data again is allowed
+	_, err = Sign(signer, SIGN_KIND_BLOCK, 100, 0, []byte("block"))
+	assert.Nil(t, err)
+	_, err = Sign(signer, SIGN_KIND_BLOCK, 100, 0, []byte("another block"))
+	assert.IsType(t, &DoubleSignError{}, err)
+	//kinds are tracked separately
+	_, err = Sign(signer, SIGN_KIND_ENDORSE, 100, 0, []byte("another block"))
+	assert.Nil(t, err)
+	_, err = SignMsg(signer, []byte("msg"))
+	assert.Nil(t, err)
+	_, err = SignMsg(signer, []byte("another msg"))
+	assert.Nil(t, err)
+
+	//records survive restarts
+	guard, err = NewSignGuard(path)
+	assert.Nil(t, err)
+	assert.IsType(t, &DoubleSignError{}, guard.Acquire(&SignRequest{
+		Kind: SIGN_KIND_BLOCK, Height: 100, Data: []byte("another block"),
+	}))
+	assert.Nil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 100, Data: []byte("block")}))
+
+	//pruned heights are refused
+	assert.Nil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 100 + SIGN_HISTORY_HEIGHTS,
+		Data: []byte("block")}))
+	assert.IsType(t, &DoubleSignError{}, guard.Acquire(&SignRequest{
+		Kind: SIGN_KIND_BLOCK, Height: 100, Data: []byte("block"),
+	}))
+	assert.Nil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 101, Data: []byte("block")}))
+}
+
+func TestSignGuardSaveFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sign-guard")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, GUARD_FILE_NAME)
+
+	guard, err := NewSignGuard(path)
+	assert.Nil(t, err)
+	assert.Nil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 100, Data: []byte("block")}))
+
+	//the guard is unchanged if the records can`t be saved
+	guard.path = filepath.Join(dir, "missing", GUARD_FILE_NAME)
+	assert.NotNil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 100 + SIGN_HISTORY_HEIGHTS,
+		Data: []byte("block")}))
+	guard.path = path
+	assert.Nil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 100, Data: []byte("block")}))
+	assert.IsType(t, &DoubleSignError{}, guard.Acquire(&SignRequest{
+		Kind: SIGN_KIND_BLOCK, Height: 100, Data: []byte("another block"),
+	}))
+	assert.Nil(t, guard.Acquire(&SignRequest{Kind: SIGN_KIND_BLOCK, Height: 101, Data: []byte("block")}))
+}
+
+func TestRemoteSigner(t *testing.T) {
+	acc := account.NewAccount("")
+	guard, err := NewSignGuard("")
+	assert.Nil(t, err)
+	local := NewLocalSigner(acc, guard)
+
+	//serve the signer in the protocol of sigsvr
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &struct {
+			Qid    string          `json:"qid"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(req))
+		resp := map[string]interface{}{"qid": req.Qid, "method": req.Method, "error_code": 0}
+		switch req.Method {
+		case METHOD_GET_PUBKEY:
+			resp["result"] = &ConsensusPubKeyRsp{PubKey: hex.EncodeToString(keypair.SerializePublicKey(local.PubKey()))}
+		case METHOD_SIGN:
+			param := &SigConsensusReq{}
+			assert.Nil(t, json.Unmarshal(req.Params, param))
+			data, _ := hex.DecodeString(param.RawData)
+			sig, err := local.Sign(&SignRequest{Kind: param.Kind, Height: param.Height, Round: param.Round, Data: data})
+			if err != nil {
+				resp["error_code"] = 1011
+				resp["error_info"] = err.Error()
+			} else {
+				resp["result"] = &SigConsensusRsp{SignedData: hex.EncodeToString(sig)}
+			}
+		case METHOD_VRF:
+			param := &ConsensusVrfReq{}
+			assert.Nil(t, json.Unmarshal(req.Params, param))
+			data, _ := hex.DecodeString(param.RawData)
+			value, proof, err := local.Vrf(data)
+			assert.Nil(t, err)
+			resp["result"] = &ConsensusVrfRsp{Value: hex.EncodeToString(value), Proof: hex.EncodeToString(proof)}
+		}
+		assert.Nil(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer svr.Close()
+
+	remote, err := NewRemoteSigner(svr.URL)
+	assert.Nil(t, err)
+	assert.True(t, keypair.ComparePublicKey(acc.PublicKey, remote.PubKey()))
+
+	data := []byte("block")
+	sig, err := Sign(remote, SIGN_KIND_COMMIT, 10, 0, data)
+	assert.Nil(t, err)
+	assert.Nil(t, signature.Verify(acc.PublicKey, data, sig))
+	_, err = Sign(remote, SIGN_KIND_COMMIT, 10, 0, []byte("another block"))
+	assert.NotNil(t, err)
+
+	value, proof, err := remote.Vrf(data)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, value)
+	assert.NotEmpty(t, proof)
+}
@@ -25,13 +25,12 @@ import (
 
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-eventbus/actor"
-	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/consensus/signer"
 	"github.com/ontio/ontology/core/ledger"
-	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/events"
 	"github.com/ontio/ontology/events/message"
@@ -43,22 +42,36 @@ import (
  */
 const ContextVersion uint32 = 0
 
+//interval to check txs in pool with dynamic block interval
+const txPoolCheckInterval = time.Second
+
+//max blocks generated by one mineblock request in dev mode
+const maxMineBlockCount = 1000
+
 type SoloService struct {
-	Account          *account.Account
+	Signer           signer.ConsensusSigner
 	poolActor        *actorTypes.TxPoolActor
 	incrValidator    *increment.IncrementValidator
 	existCh          chan interface{}
 	genBlockInterval time.Duration
+	maxBlockInterval time.Duration //interval of empty blocks, zero for fixed block interval
+	txThreshold      int
+	lastBlockTime    time.Time
+	devMode          bool  //generate block on each tx and by request only
+	timeOffset       int64 //seconds, offset of block timestamp to local time in dev mode
 	pid              *actor.PID
 	sub              *events.ActorSubscriber
 }
 
-func NewSoloService(bkAccount *account.Account, txpool *actor.PID) (*SoloService, error) {
+func NewSoloService(bkSigner signer.ConsensusSigner, txpool *actor.PID) (*SoloService, error) {
 	service := &SoloService{
-		Account:          bkAccount,
+		Signer:           bkSigner,
 		poolActor:        &actorTypes.TxPoolActor{Pool: txpool},
 		incrValidator:    increment.NewIncrementValidator(20),
 		genBlockInterval: time.Duration(config.DefConfig.Genesis.SOLO.GenBlockTime) * time.Second,
+		maxBlockInterval: time.Duration(config.DefConfig.Genesis.SOLO.MaxBlockTime) * time.Second,
+		txThreshold:      int(config.DefConfig.Genesis.SOLO.TxThreshold),
+		devMode:          config.DefConfig.Genesis.SOLO.DevMode,
 	}
 
 	props := actor.FromProducer(func() actor.Actor {
@@ -91,8 +104,19 @@ func (self *SoloService) Receive(context actor.Context) {
 		}
 
 		self.sub.Subscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+		if self.devMode {
+			self.sub.Subscribe(message.TOPIC_TXPOOL_PENDING_TX)
+			self.existCh = make(chan interface{})
+			self.genBlockWithTxs()
+			return
+		}
 
-		timer := time.NewTicker(self.genBlockInterval)
+		tickInterval := self.genBlockInterval
+		if self.maxBlockInterval != 0 && tickInterval > txPoolCheckInterval {
+			tickInterval = txPoolCheckInterval
+		}
+		self.lastBlockTime = time.Now()
+		timer := time.NewTicker(tickInterval)
 		self.existCh = make(chan interface{})
 		go func() {
 			defer timer.Stop()
@@ -112,13 +136,32 @@ func (self *SoloService) Receive(context actor.Context) {
 			self.existCh = nil
 			self.incrValidator.Clean()
 			self.sub.Unsubscribe(message.TOPIC_SAVE_BLOCK_COMPLETE)
+			if self.devMode {
+				self.sub.Unsubscribe(message.TOPIC_TXPOOL_PENDING_TX)
+			}
 		}
 	case *message.SaveBlockCompleteMsg:
 		log.Infof("solo actor receives block complete event. block height=%d txnum=%d", msg.Block.Header.Height, len(msg.Block.Transactions))
-		self.incrValidator.AddBlock(msg.Block)
+		//blocks generated by self are added once submitted
+		if _, end := self.incrValidator.BlockRange(); msg.Block.Header.Height >= end {
+			self.incrValidator.AddBlock(msg.Block)
+		}
+	case *message.TxPoolPendingTxMsg:
+		if self.existCh != nil {
+			self.genBlockWithTxs()
+		}
+	case *actorTypes.MineBlockReq:
+		hashes, err := self.mineBlock(msg.Count)
+		context.Sender().Request(&actorTypes.MineBlockRsp{Hashes: hashes, Error: err}, context.Self())
+	case *actorTypes.SetBlockTimestampReq:
+		err := self.setBlockTimestamp(msg.Timestamp)
+		context.Sender().Request(&actorTypes.SetBlockTimestampRsp{Error: err}, context.Self())
 
 	case *actorTypes.TimeOut:
-		err := self.genBlock()
+		if !self.readyToGenBlock() {
+			return
+		}
+		_, err := self.genBlock()
 		if err != nil {
 			log.Errorf("Solo genBlock error %s", err)
 		}
@@ -141,26 +184,81 @@ func (self *SoloService) Halt() error {
 	return nil
 }
 
-func (self *SoloService) genBlock() error {
+func (self *SoloService) genBlock() (common.Uint256, error) {
 	block, err := self.makeBlock()
 	if err != nil {
-		return fmt.Errorf("makeBlock error %s", err)
+		return common.UINT256_EMPTY, fmt.Errorf("makeBlock error %s", err)
 	}
 
 	result, err := ledger.DefLedger.ExecuteBlock(block)
 	if err != nil {
-		return fmt.Errorf("genBlock DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
+		return common.UINT256_EMPTY, fmt.Errorf("genBlock DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
 	}
 	err = ledger.DefLedger.SubmitBlock(block, result)
 	if err != nil {
-		return fmt.Errorf("genBlock DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
+		return common.UINT256_EMPTY, fmt.Errorf("genBlock DefLedgerPid.RequestFuture Height:%d error:%s", block.Header.Height, err)
 	}
+	//add at once, so that txs of the block are not packed again before save block complete event
+	self.incrValidator.AddBlock(block)
+	self.lastBlockTime = time.Now()
+	return block.Hash(), nil
+}
+
+//genBlockWithTxs generates block in dev mode if there are txs in pool
+func (self *SoloService) genBlockWithTxs() {
+	if len(self.validTxs(ledger.DefLedger.GetCurrentBlockHeight())) == 0 {
+		return
+	}
+	if _, err := self.genBlock(); err != nil {
+		log.Errorf("Solo genBlock error %s", err)
+	}
+}
+
+func (self *SoloService) mineBlock(count uint32) ([]common.Uint256, error) {
+	if !self.devMode || self.existCh == nil {
+		return nil, fmt.Errorf("solo consensus not running in dev mode")
+	}
+	if count == 0 || count > maxMineBlockCount {
+		return nil, fmt.Errorf("block count should be in [1, %d]", maxMineBlockCount)
+	}
+	hashes := make([]common.Uint256, 0, count)
+	for i := uint32(0); i < count; i++ {
+		hash, err := self.genBlock()
+		if err != nil {
+			return hashes, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+func (self *SoloService) setBlockTimestamp(timestamp uint32) error {
+	if !self.devMode {
+		return fmt.Errorf("solo consensus not running in dev mode")
+	}
+	header, err := ledger.DefLedger.GetHeaderByHeight(ledger.DefLedger.GetCurrentBlockHeight())
+	if err != nil {
+		return fmt.Errorf("get current block header error %s", err)
+	}
+	if timestamp < header.Timestamp {
+		return fmt.Errorf("timestamp %d is earlier than current block %d", timestamp, header.Timestamp)
+	}
+	self.timeOffset = int64(timestamp) - time.Now().Unix()
 	return nil
 }
 
+//blockTimestamp returns timestamp of next block, always later than the previous one
+func (self *SoloService) blockTimestamp(prevHash common.Uint256) uint32 {
+	timestamp := time.Now().Unix() + self.timeOffset
+	if prev, err := ledger.DefLedger.GetHeaderByHash(prevHash); err == nil && timestamp <= int64(prev.Timestamp) {
+		return prev.Timestamp + 1
+	}
+	return uint32(timestamp)
+}
+
 func (self *SoloService) makeBlock() (*types.Block, error) {
 	log.Debug()
-	owner := self.Account.PublicKey
+	owner := self.Signer.PubKey()
 	nextBookkeeper, err := types.AddressFromBookkeepers([]keypair.PublicKey{owner})
 	if err != nil {
 		return nil, fmt.Errorf("GetBookkeeperAddress error:%s", err)
@@ -168,28 +266,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 	prevHash := ledger.DefLedger.GetCurrentBlockHash()
 	height := ledger.DefLedger.GetCurrentBlockHeight()
 
-	validHeight := height
-
-	start, end := self.incrValidator.BlockRange()
-
-	if height+1 == end {
-		validHeight = start
-	} else {
-		self.incrValidator.Clean()
-		log.Infof("increment validator block height %v != ledger block height %v", int(end)-1, height)
-	}
-
-	log.Infof("current block height %v, increment validator block cache range: [%d, %d)", height, start, end)
-
-	txs := self.poolActor.GetTxnPool(true, validHeight)
-
-	transactions := make([]*types.Transaction, 0, len(txs))
-	for _, txEntry := range txs {
-		// TODO optimize to use height in txentry
-		if err := self.incrValidator.Verify(txEntry.Tx, validHeight); err == nil {
-			transactions = append(transactions, txEntry.Tx)
-		}
-	}
+	transactions := self.validTxs(height)
 
 	txHash := []common.Uint256{}
 	for _, t := range transactions {
@@ -203,7 +280,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 		PrevBlockHash:    prevHash,
 		TransactionsRoot: txRoot,
 		BlockRoot:        blockRoot,
-		Timestamp:        uint32(time.Now().Unix()),
+		Timestamp:        self.blockTimestamp(prevHash),
 		Height:           height + 1,
 		ConsensusData:    common.GetNonce(),
 		NextBookkeeper:   nextBookkeeper,
@@ -215,7 +292,7 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 
 	blockHash := block.Hash()
 
-	sig, err := signature.Sign(self.Account, blockHash[:])
+	sig, err := signer.Sign(self.Signer, signer.SIGN_KIND_BLOCK, block.Header.Height, 0, blockHash[:])
 	if err != nil {
 		return nil, fmt.Errorf("[Signature],Sign error:%s.", err)
 	}
@@ -224,3 +301,50 @@ func (self *SoloService) makeBlock() (*types.Block, error) {
 	block.Header.SigData = [][]byte{sig}
 	return block, nil
 }
+
+//readyToGenBlock implements dynamic block interval. Empty block is generated after maxBlockInterval,
+//txs are generated after genBlockInterval, or at once if reaching txThreshold
+func (self *SoloService) readyToGenBlock() bool {
+	if self.maxBlockInterval == 0 {
+		return true
+	}
+	elapsed := time.Since(self.lastBlockTime)
+	if elapsed >= self.maxBlockInterval {
+		return true
+	}
+	txCount := len(self.validTxs(ledger.DefLedger.GetCurrentBlockHeight()))
+	if txCount == 0 {
+		return false
+	}
+	if self.txThreshold > 0 && txCount >= self.txThreshold {
+		return true
+	}
+	return elapsed >= self.genBlockInterval
+}
+
+//validTxs returns txs in pool valid for block height+1
+func (self *SoloService) validTxs(height uint32) []*types.Transaction {
+	validHeight := height
+
+	start, end := self.incrValidator.BlockRange()
+
+	if height+1 == end {
+		validHeight = start
+	} else {
+		self.incrValidator.Clean()
+		log.Infof("increment validator block height %v != ledger block height %v", int(end)-1, height)
+	}
+
+	log.Debugf("current block height %v, increment validator block cache range: [%d, %d)", height, start, end)
+
+	txs := self.poolActor.GetTxnPool(true, validHeight)
+
+	transactions := make([]*types.Transaction, 0, len(txs))
+	for _, txEntry := range txs {
+		// TODO optimize to use height in txentry
+		if err := self.incrValidator.Verify(txEntry.Tx, validHeight); err == nil {
+			transactions = append(transactions, txEntry.Tx)
+		}
+	}
+	return transactions
+}
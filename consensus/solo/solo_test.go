@@ -0,0 +1,172 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package solo
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common/config"
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/consensus/signer"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	"github.com/ontio/ontology/events"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+	txpool "github.com/ontio/ontology/txnpool/common"
+	"github.com/ontio/ontology/validator/increment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	events.Init()
+	os.Exit(m.Run())
+}
+
+//mockTxPool returns the txs not packed yet
+type mockTxPool struct {
+	txs []*types.Transaction
+}
+
+func (self *mockTxPool) Receive(context actor.Context) {
+	if _, ok := context.Message().(*txpool.GetTxnPoolReq); ok {
+		entries := make([]*txpool.TXEntry, 0, len(self.txs))
+		for _, tx := range self.txs {
+			if exist, _ := ledger.DefLedger.IsContainTransaction(tx.Hash()); !exist {
+				entries = append(entries, &txpool.TXEntry{Tx: tx})
+			}
+		}
+		context.Sender().Request(&txpool.GetTxnPoolRsp{TxnPool: entries}, context.Self())
+	}
+}
+
+func newDevSoloService(t *testing.T) (*SoloService, *mockTxPool, func()) {
+	dir, err := ioutil.TempDir("", "solo")
+	assert.Nil(t, err)
+	acc := account.NewAccount("")
+	genesisConfig := config.DefConfig.Genesis
+	config.DefConfig.Genesis = &config.GenesisConfig{
+		ConsensusType: config.CONSENSUS_TYPE_SOLO,
+		SOLO: &config.SOLOConfig{
+			DevMode:     true,
+			Bookkeepers: []string{hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))},
+		},
+	}
+
+	ledger.DefLedger, err = ledger.NewLedger(dir, 0)
+	assert.Nil(t, err)
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	block, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	assert.Nil(t, err)
+	assert.Nil(t, ledger.DefLedger.Init(bookkeepers, block))
+
+	guard, err := signer.NewSignGuard("")
+	assert.Nil(t, err)
+	pool := &mockTxPool{}
+	poolPid := actor.Spawn(actor.FromProducer(func() actor.Actor { return pool }))
+	service := &SoloService{
+		Signer:        signer.NewLocalSigner(acc, guard),
+		poolActor:     &actorTypes.TxPoolActor{Pool: poolPid},
+		incrValidator: increment.NewIncrementValidator(20),
+		devMode:       true,
+		existCh:       make(chan interface{}),
+	}
+	return service, pool, func() {
+		poolPid.Stop()
+		ledger.DefLedger.Close()
+		os.RemoveAll(dir)
+		config.DefConfig.Genesis = genesisConfig
+	}
+}
+
+func TestDevModeGenBlockWithTxs(t *testing.T) {
+	service, pool, clean := newDevSoloService(t)
+	defer clean()
+
+	//no block without txs
+	service.genBlockWithTxs()
+	assert.Equal(t, uint32(0), ledger.DefLedger.GetCurrentBlockHeight())
+
+	mutable := utils.BuildNativeTransaction(nutils.OntContractAddress, "name", []byte{})
+	tx, err := mutable.IntoImmutable()
+	assert.Nil(t, err)
+	pool.txs = append(pool.txs, tx)
+	service.genBlockWithTxs()
+	assert.Equal(t, uint32(1), ledger.DefLedger.GetCurrentBlockHeight())
+	exist, err := ledger.DefLedger.IsContainTransaction(tx.Hash())
+	assert.Nil(t, err)
+	assert.True(t, exist)
+
+	//packed txs are not packed again
+	service.genBlockWithTxs()
+	assert.Equal(t, uint32(1), ledger.DefLedger.GetCurrentBlockHeight())
+}
+
+func TestDevModeMineBlock(t *testing.T) {
+	service, _, clean := newDevSoloService(t)
+	defer clean()
+
+	_, err := service.mineBlock(0)
+	assert.NotNil(t, err)
+	_, err = service.mineBlock(maxMineBlockCount + 1)
+	assert.NotNil(t, err)
+
+	hashes, err := service.mineBlock(3)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(hashes))
+	assert.Equal(t, uint32(3), ledger.DefLedger.GetCurrentBlockHeight())
+	assert.Equal(t, hashes[2], ledger.DefLedger.GetCurrentBlockHash())
+
+	service.devMode = false
+	_, err = service.mineBlock(1)
+	assert.NotNil(t, err)
+}
+
+func TestDevModeSetBlockTimestamp(t *testing.T) {
+	service, _, clean := newDevSoloService(t)
+	defer clean()
+
+	future := uint32(time.Now().Add(24 * time.Hour).Unix())
+	assert.Nil(t, service.setBlockTimestamp(future))
+	_, err := service.mineBlock(1)
+	assert.Nil(t, err)
+	header, err := ledger.DefLedger.GetHeaderByHeight(1)
+	assert.Nil(t, err)
+	assert.True(t, header.Timestamp >= future)
+
+	//timestamp can't go back before the current block
+	assert.NotNil(t, service.setBlockTimestamp(header.Timestamp-1))
+	assert.Nil(t, service.setBlockTimestamp(header.Timestamp))
+	_, err = service.mineBlock(1)
+	assert.Nil(t, err)
+	next, err := ledger.DefLedger.GetHeaderByHeight(2)
+	assert.Nil(t, err)
+	assert.True(t, next.Timestamp > header.Timestamp)
+
+	service.devMode = false
+	assert.NotNil(t, service.setBlockTimestamp(future))
+}
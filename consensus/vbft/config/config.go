@@ -49,6 +49,14 @@ type ChainConfig struct {
 	Peers                []*PeerConfig `json:"peers"`
 	PosTable             []uint32      `json:"pos_table"`
 	MaxBlockChangeView   uint32        `json:"MaxBlockChangeView"`
+	BlockInterval        time.Duration `json:"block_interval,omitempty"`     // max waiting time of txs less than TxThreshold
+	MaxBlockInterval     time.Duration `json:"max_block_interval,omitempty"` // interval of empty blocks, zero for fixed interval
+	TxThreshold          uint32        `json:"tx_threshold,omitempty"`       // txs in pool to propose block at once
+}
+
+// DynamicBlockInterval returns whether block interval adapts to the tx load
+func (cc *ChainConfig) DynamicBlockInterval() bool {
+	return cc.MaxBlockInterval != 0
 }
 
 //
@@ -0,0 +1,72 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vconfig
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-crypto/signature"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
+)
+
+//An honest proposer signs at most two headers at one height, the proposed
+//block and the empty block of the proposal. More distinct headers signed by
+//the proposer at same height prove it has double signed
+const MIN_DOUBLE_SIGN_HEADERS = 3
+
+//VerifyDoubleSign check all of the headers are proposed by the proposer index
+//at same height and signed by its key, return the height if the headers prove
+//the proposer has signed conflicting proposals
+func VerifyDoubleSign(pub keypair.PublicKey, proposer uint32, headers []*types.Header) (uint32, error) {
+	if len(headers) < MIN_DOUBLE_SIGN_HEADERS {
+		return 0, fmt.Errorf("not enough headers: %d", len(headers))
+	}
+	height := headers[0].Height
+	hashes := make(map[common.Uint256]bool)
+	for _, header := range headers {
+		if header.Height != height {
+			return 0, fmt.Errorf("header heights mismatch: %d vs %d", header.Height, height)
+		}
+		info, err := VbftBlock(header)
+		if err != nil {
+			return 0, err
+		}
+		if info.Proposer != proposer {
+			return 0, fmt.Errorf("header of height %d proposed by %d, not %d", height, info.Proposer, proposer)
+		}
+		if len(header.SigData) == 0 {
+			return 0, fmt.Errorf("no sigdata in header of height %d", height)
+		}
+		sig, err := signature.Deserialize(header.SigData[0])
+		if err != nil {
+			return 0, fmt.Errorf("deserialize header sig: %s", err)
+		}
+		hash := header.Hash()
+		if !signature.Verify(pub, hash[:], sig) {
+			return 0, fmt.Errorf("failed to verify sig of header %s", hash.ToHexString())
+		}
+		hashes[hash] = true
+	}
+	if len(hashes) < MIN_DOUBLE_SIGN_HEADERS {
+		return 0, fmt.Errorf("only %d distinct headers at height %d", len(hashes), height)
+	}
+	return height, nil
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedHeader(t *testing.T, acct *account.Account, proposer uint32, height uint32, timestamp uint32) *types.Header {
+	payload, err := json.Marshal(&VbftBlockInfo{Proposer: proposer})
+	assert.Nil(t, err)
+	header := &types.Header{
+		Height:           height,
+		Timestamp:        timestamp,
+		ConsensusPayload: payload,
+	}
+	hash := header.Hash()
+	sig, err := signature.Sign(acct, hash[:])
+	assert.Nil(t, err)
+	header.SigData = [][]byte{sig}
+	return header
+}
+
+func TestVerifyDoubleSign(t *testing.T) {
+	acct := account.NewAccount("")
+	block := signedHeader(t, acct, 1, 10, 100)
+	empty := signedHeader(t, acct, 1, 10, 101)
+	other := signedHeader(t, acct, 1, 10, 102)
+
+	height, err := VerifyDoubleSign(acct.PublicKey, 1, []*types.Header{block, empty, other})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(10), height)
+
+	//the block and empty block of one proposal are not double signing
+	_, err = VerifyDoubleSign(acct.PublicKey, 1, []*types.Header{block, empty, block})
+	assert.NotNil(t, err)
+	//proposed by other peer
+	_, err = VerifyDoubleSign(acct.PublicKey, 2, []*types.Header{block, empty, other})
+	assert.NotNil(t, err)
+	//different heights
+	_, err = VerifyDoubleSign(acct.PublicKey, 1, []*types.Header{block, empty, signedHeader(t, acct, 1, 11, 102)})
+	assert.NotNil(t, err)
+	//signed by other key
+	_, err = VerifyDoubleSign(account.NewAccount("").PublicKey, 1, []*types.Header{block, empty, other})
+	assert.NotNil(t, err)
+}
@@ -190,6 +190,9 @@ func GenesisChainConfig(conf *config.VBFTConfig, peers []*config.VBFTPeerStakeIn
 		Peers:                peerCfgs,
 		PosTable:             posTable,
 		MaxBlockChangeView:   conf.MaxBlockChangeView,
+		BlockInterval:        time.Duration(conf.BlockInterval) * time.Millisecond,
+		MaxBlockInterval:     time.Duration(conf.MaxBlockInterval) * time.Millisecond,
+		TxThreshold:          conf.TxThreshold,
 	}
 	return chainConfig, nil
 }
@@ -19,7 +19,10 @@
 package vconfig
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
@@ -109,3 +112,39 @@ func TestGenesisChainConfig(t *testing.T) {
 	}
 	t.Logf("TestGenesisChainConfig succ: %v", chainconfig.PosTable)
 }
+
+func TestDynamicBlockInterval(t *testing.T) {
+	config, err := constructConfig()
+	if err != nil {
+		t.Fatalf("constructConfig failed:%s", err)
+	}
+	chainconfig, err := GenesisChainConfig(config, config.Peers, common.Uint256{}, 1)
+	if err != nil {
+		t.Fatalf("GenesisChainConfig failed:%s", err)
+	}
+	if chainconfig.DynamicBlockInterval() {
+		t.Fatalf("dynamic block interval should be disabled by default")
+	}
+	data, _ := json.Marshal(chainconfig)
+	if strings.Contains(string(data), "block_interval") || strings.Contains(string(data), "tx_threshold") {
+		t.Fatalf("disabled block interval changes chain config: %s", data)
+	}
+	fixedHash := chainconfig.Hash()
+
+	config.BlockInterval = 2000
+	config.MaxBlockInterval = 60000
+	config.TxThreshold = 100
+	chainconfig, err = GenesisChainConfig(config, config.Peers, common.Uint256{}, 1)
+	if err != nil {
+		t.Fatalf("GenesisChainConfig failed:%s", err)
+	}
+	if !chainconfig.DynamicBlockInterval() {
+		t.Fatalf("dynamic block interval should be enabled")
+	}
+	if chainconfig.BlockInterval != 2*time.Second || chainconfig.MaxBlockInterval != time.Minute || chainconfig.TxThreshold != 100 {
+		t.Fatalf("invalid block interval: %v, %v, %d", chainconfig.BlockInterval, chainconfig.MaxBlockInterval, chainconfig.TxThreshold)
+	}
+	if chainconfig.Hash() == fixedHash {
+		t.Fatalf("block interval not included in chain config hash")
+	}
+}
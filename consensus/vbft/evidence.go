@@ -0,0 +1,270 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/consensus/signer"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/utils"
+	gover "github.com/ontio/ontology/smartcontract/service/native/governance"
+	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const EVIDENCE_FILE_NAME = "vbft.evidence"
+
+//EvidenceStore keeps the double sign evidence detected or received, the
+//evidence is persisted to file so that it survives restarts
+type EvidenceStore struct {
+	lock     sync.RWMutex
+	path     string
+	evidence map[string]*doubleSignEvidenceMsg
+}
+
+func evidenceKey(peerPubkey string, blkNum uint32) string {
+	return fmt.Sprintf("%s:%d", peerPubkey, blkNum)
+}
+
+//NewEvidenceStore load the evidence from file, the store is not persisted if
+//path is empty
+func NewEvidenceStore(path string) *EvidenceStore {
+	store := &EvidenceStore{
+		path:     path,
+		evidence: make(map[string]*doubleSignEvidenceMsg),
+	}
+	if path == "" {
+		return store
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("read evidence file %s error: %s", path, err)
+		}
+		return store
+	}
+	var list []*doubleSignEvidenceMsg
+	if err := json.Unmarshal(buf, &list); err != nil {
+		log.Warnf("parse evidence file %s error: %s", path, err)
+		return store
+	}
+	for _, e := range list {
+		store.evidence[evidenceKey(e.PeerPubkey, e.BlockNum)] = e
+	}
+	return store
+}
+
+//Add store the evidence, return false if the evidence of peer at the block is
+//already known
+func (self *EvidenceStore) Add(evidence *doubleSignEvidenceMsg) bool {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	key := evidenceKey(evidence.PeerPubkey, evidence.BlockNum)
+	if _, present := self.evidence[key]; present {
+		return false
+	}
+	self.evidence[key] = evidence
+	self.saveLocked()
+	return true
+}
+
+func (self *EvidenceStore) Has(peerPubkey string, blkNum uint32) bool {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	_, present := self.evidence[evidenceKey(peerPubkey, blkNum)]
+	return present
+}
+
+//GetEvidence return all of the evidence ordered by block number
+func (self *EvidenceStore) GetEvidence() []*doubleSignEvidenceMsg {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	list := make([]*doubleSignEvidenceMsg, 0, len(self.evidence))
+	for _, e := range self.evidence {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].BlockNum != list[j].BlockNum {
+			return list[i].BlockNum < list[j].BlockNum
+		}
+		return list[i].Proposer < list[j].Proposer
+	})
+	return list
+}
+
+//getFaultyReports return the faulty proposers of block, reported in endorsements
+func (self *EvidenceStore) getFaultyReports(blkNum uint32) []*FaultyReport {
+	var reports []*FaultyReport
+	for _, e := range self.GetEvidence() {
+		if e.BlockNum != blkNum {
+			continue
+		}
+		headers, err := e.headers()
+		if err != nil || len(headers) == 0 {
+			continue
+		}
+		reports = append(reports, &FaultyReport{
+			FaultyID:      e.Proposer,
+			FaultyMsgHash: headers[len(headers)-1].Hash(),
+		})
+	}
+	return reports
+}
+
+func (self *EvidenceStore) saveLocked() {
+	if self.path == "" {
+		return
+	}
+	list := make([]*doubleSignEvidenceMsg, 0, len(self.evidence))
+	for _, e := range self.evidence {
+		list = append(list, e)
+	}
+	buf, err := json.Marshal(list)
+	if err != nil {
+		log.Errorf("marshal evidence error: %s", err)
+		return
+	}
+	if err := ioutil.WriteFile(self.path, buf, 0644); err != nil {
+		log.Errorf("save evidence file %s error: %s", self.path, err)
+	}
+}
+
+//newDoubleSignEvidence build the evidence from two proposals of the proposer
+//at same height, an error is returned if they do not prove double signing,
+//e.g. the proposer only re-signed the same blocks
+func newDoubleSignEvidence(pub keypair.PublicKey, proposer uint32, proposals ...*blockProposalMsg) (*doubleSignEvidenceMsg, error) {
+	headers := make([]*types.Header, 0)
+	hashes := make(map[common.Uint256]bool)
+	for _, p := range proposals {
+		for _, blk := range []*types.Block{p.Block.Block, p.Block.EmptyBlock} {
+			if blk == nil || hashes[blk.Hash()] {
+				continue
+			}
+			hashes[blk.Hash()] = true
+			headers = append(headers, blk.Header)
+		}
+	}
+	blkNum, err := vconfig.VerifyDoubleSign(pub, proposer, headers)
+	if err != nil {
+		return nil, err
+	}
+	evidence := &doubleSignEvidenceMsg{
+		PeerPubkey: vconfig.PubkeyID(pub),
+		Proposer:   proposer,
+		BlockNum:   blkNum,
+	}
+	for _, header := range headers {
+		evidence.Headers = append(evidence.Headers, common.SerializeToBytes(header))
+	}
+	return evidence, nil
+}
+
+//reportDoubleProposal is called when proposer sent another proposal of block,
+//the evidence is persisted, gossiped to peers and reported to the governance
+//contract to penalize the proposer
+func (self *Server) reportDoubleProposal(proposal *blockProposalMsg) {
+	blkNum := proposal.GetBlockNum()
+	proposer := proposal.Block.getProposer()
+	var prev *blockProposalMsg
+	for _, p := range self.blockPool.getBlockProposals(blkNum) {
+		if p.Block.getProposer() == proposer {
+			prev = p
+			break
+		}
+	}
+	pub := self.peerPool.GetPeerPubKey(proposer)
+	if prev == nil || pub == nil {
+		return
+	}
+	evidence, err := newDoubleSignEvidence(pub, proposer, prev, proposal)
+	if err != nil {
+		log.Debugf("server %d dup proposal from %d, blk %d: %s", self.Index, proposer, blkNum, err)
+		return
+	}
+	if !self.evidence.Add(evidence) {
+		return
+	}
+	log.Warnf("server %d detected double proposals from %d, blk %d", self.Index, proposer, blkNum)
+	self.broadcast(evidence)
+	if err := self.submitEvidence(evidence); err != nil {
+		log.Errorf("server %d failed to submit evidence of %d, blk %d: %s", self.Index, proposer, blkNum, err)
+	}
+}
+
+//processEvidenceMsg keep the evidence gossiped by peers and relay it
+func (self *Server) processEvidenceMsg(msg *doubleSignEvidenceMsg) {
+	if self.evidence.Has(msg.PeerPubkey, msg.BlockNum) {
+		return
+	}
+	//the evidence is verified against the index it claims, check the index
+	//belongs to the peer
+	pub := self.peerPool.GetPeerPubKey(msg.Proposer)
+	if pub == nil || vconfig.PubkeyID(pub) != msg.PeerPubkey {
+		log.Errorf("server %d received evidence of unknown peer %d, blk %d", self.Index, msg.Proposer, msg.BlockNum)
+		return
+	}
+	if !self.evidence.Add(msg) {
+		return
+	}
+	log.Warnf("server %d received evidence of double proposals from %d, blk %d", self.Index, msg.Proposer, msg.BlockNum)
+	self.broadcast(msg)
+}
+
+//submitEvidence invoke the governance contract with the evidence
+func (self *Server) submitEvidence(evidence *doubleSignEvidenceMsg) error {
+	headers, err := evidence.headers()
+	if err != nil {
+		return err
+	}
+	param := &gover.DoubleSignEvidenceParam{
+		PeerPubkey: evidence.PeerPubkey,
+		Headers:    headers,
+	}
+	mutable := utils.BuildNativeTransaction(nutils.GovernanceContractAddress, gover.REPORT_DOUBLE_SIGN,
+		common.SerializeToBytes(param))
+	mutable.GasPrice = config.DefConfig.Common.GasPrice
+	mutable.GasLimit = config.DefConfig.Common.GasLimit
+	mutable.Nonce = evidence.BlockNum
+	mutable.Payer = types.AddressFromPubKey(self.signer.PubKey())
+	sig, err := signer.SignTx(self.signer, mutable)
+	if err != nil {
+		return fmt.Errorf("sign tx: %s", err)
+	}
+	mutable.Sigs = []types.Sig{{
+		SigData: [][]byte{sig},
+		PubKeys: []keypair.PublicKey{self.signer.PubKey()},
+		M:       1,
+	}}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return err
+	}
+	self.poolActor.SubmitTx(tx)
+	return nil
+}
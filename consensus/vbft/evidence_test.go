@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+
+package vbft
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/signature"
+	"github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func signedTestBlock(t *testing.T, acc *account.Account, info *vconfig.VbftBlockInfo, timestamp uint32) *types.Block {
+	payload, err := json.Marshal(info)
+	assert.Nil(t, err)
+	header := &types.Header{
+		Timestamp:        timestamp,
+		Height:           20,
+		ConsensusPayload: payload,
+	}
+	hash := header.Hash()
+	sig, err := signature.Sign(acc, hash[:])
+	assert.Nil(t, err)
+	header.SigData = [][]byte{sig}
+	return &types.Block{Header: header}
+}
+
+func constructTestProposal(t *testing.T, acc *account.Account, timestamp uint32) *blockProposalMsg {
+	info := &vconfig.VbftBlockInfo{Proposer: 1}
+	return &blockProposalMsg{
+		Block: &Block{
+			Block:      signedTestBlock(t, acc, info, timestamp),
+			EmptyBlock: signedTestBlock(t, acc, info, timestamp+1),
+			Info:       info,
+		},
+	}
+}
+
+func TestDoubleSignEvidence(t *testing.T) {
+	acc := account.NewAccount("")
+	first := constructTestProposal(t, acc, 100)
+	second := constructTestProposal(t, acc, 200)
+
+	//re-sending the same proposal is not double signing
+	_, err := newDoubleSignEvidence(acc.PublicKey, 1, first, first)
+	assert.NotNil(t, err)
+
+	evidence, err := newDoubleSignEvidence(acc.PublicKey, 1, first, second)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(20), evidence.BlockNum)
+	assert.Equal(t, 4, len(evidence.Headers))
+
+	data, err := SerializeVbftMsg(evidence)
+	assert.Nil(t, err)
+	msg, err := DeserializeVbftMsg(data)
+	assert.Nil(t, err)
+	assert.Nil(t, msg.Verify(nil))
+
+	evidence.Proposer = 2
+	assert.NotNil(t, evidence.Verify(nil))
+}
+
+func TestEvidenceStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vbft-evidence")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, EVIDENCE_FILE_NAME)
+
+	acc := account.NewAccount("")
+	evidence, err := newDoubleSignEvidence(acc.PublicKey, 1,
+		constructTestProposal(t, acc, 100), constructTestProposal(t, acc, 200))
+	assert.Nil(t, err)
+
+	store := NewEvidenceStore(path)
+	assert.True(t, store.Add(evidence))
+	assert.False(t, store.Add(evidence))
+	assert.Equal(t, 1, len(store.getFaultyReports(20)))
+	assert.Nil(t, store.getFaultyReports(21))
+
+	store = NewEvidenceStore(path)
+	assert.True(t, store.Has(evidence.PeerPubkey, 20))
+	assert.Equal(t, evidence.Headers, store.GetEvidence()[0].Headers)
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"sort"
+	"time"
+
+	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/events"
+	"github.com/ontio/ontology/events/message"
+)
+
+//events published on message.TOPIC_CONSENSUS_EVENT
+const (
+	CONSENSUS_EVENT_NEW_ROUND   = "newround"
+	CONSENSUS_EVENT_PROPOSAL    = "proposal"  // proposal received from peer
+	CONSENSUS_EVENT_ENDORSEMENT = "endorse"   // endorsement received from peer
+	CONSENSUS_EVENT_COMMITMENT  = "commit"    // commitment received from peer
+	CONSENSUS_EVENT_ENDORSED    = "endorsed"  // local node endorsed a proposal
+	CONSENSUS_EVENT_COMMITTED   = "committed" // block sealed by local node
+	CONSENSUS_EVENT_TIMEOUT     = "timeout"
+	CONSENSUS_EVENT_VIEW_CHANGE = "viewchange"
+)
+
+var serverStateNames = map[ServerState]string{
+	Init:             "Init",
+	LocalConfigured:  "LocalConfigured",
+	Configured:       "Configured",
+	Syncing:          "Syncing",
+	WaitNetworkReady: "WaitNetworkReady",
+	SyncReady:        "SyncReady",
+	Synced:           "Synced",
+	SyncingCheck:     "SyncingCheck",
+}
+
+func (state ServerState) String() string {
+	if name, present := serverStateNames[state]; present {
+		return name
+	}
+	return "Unknown"
+}
+
+var timerEventNames = map[TimerEventType]string{
+	EventProposeBlockTimeout:      "ProposeBlockTimeout",
+	EventProposalBackoff:          "ProposalBackoff",
+	EventRandomBackoff:            "RandomBackoff",
+	EventPropose2ndBlockTimeout:   "Propose2ndBlockTimeout",
+	EventEndorseBlockTimeout:      "EndorseBlockTimeout",
+	EventEndorseEmptyBlockTimeout: "EndorseEmptyBlockTimeout",
+	EventCommitBlockTimeout:       "CommitBlockTimeout",
+	EventPeerHeartbeat:            "PeerHeartbeat",
+	EventTxPool:                   "TxPool",
+	EventTxBlockTimeout:           "TxBlockTimeout",
+}
+
+func (evtType TimerEventType) String() string {
+	if name, present := timerEventNames[evtType]; present {
+		return name
+	}
+	return "Unknown"
+}
+
+//peerLatency is the delay of the first consensus msg received from a peer in a round
+type peerLatency struct {
+	blockNum uint32
+	latency  time.Duration
+}
+
+//updatePeerLatency records the latency of peer in round blkNum, only the first msg of
+//each round is measured. returns false if the round has been measured
+func (pool *PeerPool) updatePeerLatency(peerIdx uint32, blkNum uint32, latency time.Duration) bool {
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+
+	if l, present := pool.latencies[peerIdx]; present && l.blockNum >= blkNum {
+		return false
+	}
+	pool.latencies[peerIdx] = &peerLatency{
+		blockNum: blkNum,
+		latency:  latency,
+	}
+	return true
+}
+
+func (pool *PeerPool) getPeerStatus() []*actorTypes.PeerStatus {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	peers := make([]*actorTypes.PeerStatus, 0, len(pool.peers))
+	for idx, p := range pool.peers {
+		status := &actorTypes.PeerStatus{
+			Index:          idx,
+			PubKey:         vconfig.PubkeyID(p.PubKey),
+			Connected:      p.connected,
+			LastUpdateTime: p.LastUpdateTime.Unix(),
+		}
+		if p.LatestInfo != nil {
+			status.CommittedBlockNum = p.LatestInfo.CommittedBlockNumber
+		} else if p.handShake != nil {
+			status.CommittedBlockNum = p.handShake.CommittedBlockNumber
+		}
+		if l, present := pool.latencies[idx]; present {
+			status.LatencyBlockNum = l.blockNum
+			status.Latency = int64(l.latency / time.Millisecond)
+		}
+		peers = append(peers, status)
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Index < peers[j].Index })
+	return peers
+}
+
+func (pool *BlockPool) getRoundStatus(blkNum uint32) *actorTypes.RoundStatus {
+	pool.lock.RLock()
+	defer pool.lock.RUnlock()
+
+	status := &actorTypes.RoundStatus{
+		Proposals:    make([]uint32, 0),
+		Endorsements: make(map[uint32]uint32),
+	}
+	candidate, present := pool.candidateBlocks[blkNum]
+	if !present {
+		return status
+	}
+	for _, p := range candidate.Proposals {
+		status.Proposals = append(status.Proposals, p.Block.getProposer())
+	}
+	for _, sigs := range candidate.EndorseSigs {
+		for _, sig := range sigs {
+			if sig.ForEmpty {
+				status.EmptyEndorsements++
+			} else {
+				status.Endorsements[sig.EndorsedProposer]++
+			}
+		}
+	}
+	status.Commits = uint32(len(candidate.CommitMsgs))
+	status.Endorsed = candidate.EndorsedProposal != nil
+	status.EndorsedEmpty = candidate.EndorsedEmptyProposal != nil
+	status.Committed = candidate.CommittedProposal != nil
+	status.CommittedEmpty = candidate.CommittedEmptyProposal != nil
+	return status
+}
+
+//getActiveTimers returns names of the pending per-block timers of blockNum
+func (self *EventTimer) getActiveTimers(blockNum uint32) []string {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	timers := make([]string, 0)
+	for evtType := TimerEventType(0); evtType < EventMax; evtType++ {
+		if _, present := self.eventTimers[evtType][blockNum]; present {
+			timers = append(timers, evtType.String())
+		}
+	}
+	return timers
+}
+
+func (self *Server) getConsensusStatus() *actorTypes.ConsensusStatus {
+	blkNum := self.GetCurrentBlockNo()
+	status := &actorTypes.ConsensusStatus{
+		State:             self.getState().String(),
+		Index:             self.Index,
+		CurrentBlockNum:   blkNum,
+		CommittedBlockNum: self.GetCommittedBlockNo(),
+		Round:             self.blockPool.getRoundStatus(blkNum),
+		Timers:            self.timer.getActiveTimers(blkNum),
+		Peers:             self.peerPool.getPeerStatus(),
+	}
+
+	self.metaLock.RLock()
+	defer self.metaLock.RUnlock()
+	if self.config != nil {
+		status.View = self.config.View
+	}
+	if cfg := self.currentParticipantConfig; cfg != nil && cfg.BlockNum == blkNum {
+		status.Proposers = cfg.Proposers
+		status.Endorsers = cfg.Endorsers
+		status.Committers = cfg.Committers
+		// the first active proposer
+		for _, id := range cfg.Proposers {
+			if self.isPeerAlive(id, blkNum) {
+				status.Proposer = id
+				break
+			}
+		}
+	}
+	return status
+}
+
+//roundLatency returns the time elapsed since the round of blkNum started,
+//false if blkNum is not the current round
+func (self *Server) roundLatency(blkNum uint32) (time.Duration, bool) {
+	self.metaLock.RLock()
+	defer self.metaLock.RUnlock()
+
+	if self.roundBlockNum != blkNum || self.roundStartTime.IsZero() {
+		return 0, false
+	}
+	return time.Since(self.roundStartTime), true
+}
+
+func (self *Server) startRoundClock(blkNum uint32) {
+	self.metaLock.Lock()
+	self.roundBlockNum = blkNum
+	self.roundStartTime = time.Now()
+	self.metaLock.Unlock()
+}
+
+//recordPeerMsg measures peer latency with consensus msg of current round, and
+//publishes the msg receiving event
+func (self *Server) recordPeerMsg(peerIdx uint32, msg ConsensusMsg) {
+	var event string
+	switch msg.Type() {
+	case BlockProposalMessage:
+		event = CONSENSUS_EVENT_PROPOSAL
+	case BlockEndorseMessage:
+		event = CONSENSUS_EVENT_ENDORSEMENT
+	case BlockCommitMessage:
+		event = CONSENSUS_EVENT_COMMITMENT
+	default:
+		return
+	}
+	latency, ok := self.roundLatency(msg.GetBlockNum())
+	if !ok {
+		return
+	}
+	self.peerPool.updatePeerLatency(peerIdx, msg.GetBlockNum(), latency)
+	self.publishConsensusEvent(&message.ConsensusEventMsg{
+		Event:    event,
+		BlockNum: msg.GetBlockNum(),
+		Peer:     peerIdx,
+		Latency:  int64(latency / time.Millisecond),
+	})
+}
+
+func (self *Server) publishConsensusEvent(evt *message.ConsensusEventMsg) {
+	if events.DefActorPublisher == nil {
+		return
+	}
+	self.metaLock.RLock()
+	if self.config != nil {
+		evt.View = self.config.View
+	}
+	self.metaLock.RUnlock()
+	evt.Timestamp = time.Now().Unix()
+	events.DefActorPublisher.Publish(message.TOPIC_CONSENSUS_EVENT, evt)
+}
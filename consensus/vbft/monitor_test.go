@@ -0,0 +1,99 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/account"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+)
+
+func TestPeerLatency(t *testing.T) {
+	pool := NewPeerPool(3, nil)
+	if err := pool.addPeer(&vconfig.PeerConfig{
+		Index: 1,
+		ID:    "120202c924ed1a67fd1719020ce599d723d09d48362376836e04b0be72dfe825e24d81",
+	}); err != nil {
+		t.Fatalf("add peer: %s", err)
+	}
+	pool.peerConnected(1)
+
+	if !pool.updatePeerLatency(1, 10, 150*time.Millisecond) {
+		t.Fatalf("first msg of round should be measured")
+	}
+	if pool.updatePeerLatency(1, 10, 300*time.Millisecond) {
+		t.Fatalf("second msg of round should not be measured")
+	}
+	peers := pool.getPeerStatus()
+	if len(peers) != 1 {
+		t.Fatalf("expected 1 peer, got %d", len(peers))
+	}
+	if !peers[0].Connected || peers[0].LatencyBlockNum != 10 || peers[0].Latency != 150 {
+		t.Fatalf("unexpected peer status: %+v", peers[0])
+	}
+}
+
+func TestRoundStatus(t *testing.T) {
+	acc := account.NewAccount("SHA256withECDSA")
+	proposal := constructProposalMsgTest(acc)
+	blkNum := proposal.GetBlockNum()
+
+	pool := &BlockPool{candidateBlocks: make(map[uint32]*CandidateInfo)}
+	if status := pool.getRoundStatus(blkNum); len(status.Proposals) != 0 || status.Commits != 0 {
+		t.Fatalf("unexpected status of empty round: %+v", status)
+	}
+	if _, present := pool.candidateBlocks[blkNum]; present {
+		t.Fatalf("querying status should not create candidate")
+	}
+
+	candidate := pool.getCandidateInfoLocked(blkNum)
+	candidate.Proposals = append(candidate.Proposals, proposal)
+	candidate.EndorsedProposal = proposal
+	proposer := proposal.Block.getProposer()
+	candidate.EndorseSigs[2] = []*CandidateEndorseSigInfo{{EndorsedProposer: proposer}}
+	candidate.EndorseSigs[3] = []*CandidateEndorseSigInfo{{EndorsedProposer: proposer}, {ForEmpty: true}}
+
+	status := pool.getRoundStatus(blkNum)
+	if len(status.Proposals) != 1 || status.Proposals[0] != proposer {
+		t.Fatalf("unexpected proposals: %v", status.Proposals)
+	}
+	if status.Endorsements[proposer] != 2 || status.EmptyEndorsements != 1 {
+		t.Fatalf("unexpected endorsements: %v, empty %d", status.Endorsements, status.EmptyEndorsements)
+	}
+	if !status.Endorsed || status.EndorsedEmpty || status.Committed {
+		t.Fatalf("unexpected round flags: %+v", status)
+	}
+}
+
+func TestActiveTimers(t *testing.T) {
+	eventtimer := constructEventTimer()
+	if err := eventtimer.StartProposalTimer(1); err != nil {
+		t.Fatalf("start proposal timer: %s", err)
+	}
+	timers := eventtimer.getActiveTimers(1)
+	if len(timers) != 1 || timers[0] != EventProposeBlockTimeout.String() {
+		t.Fatalf("unexpected timers: %v", timers)
+	}
+	eventtimer.CancelProposalTimer(1)
+	if timers := eventtimer.getActiveTimers(1); len(timers) != 0 {
+		t.Fatalf("unexpected timers after cancel: %v", timers)
+	}
+}
@@ -28,7 +28,9 @@ import (
 	"github.com/ontio/ontology-crypto/signature"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/serialization"
+	"github.com/ontio/ontology/consensus/signer"
 	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/types"
 )
 
 type MsgType uint8
@@ -47,6 +49,7 @@ const (
 	BlockFetchMessage
 	BlockFetchRespMessage
 	BlockSubmitMessage
+	DoubleSignEvidenceMessage
 )
 
 type ConsensusMsg interface {
@@ -399,7 +402,8 @@ func (msg *blockSubmitMsg) Verify(pub keypair.PublicKey) error {
 	if err != nil {
 		return fmt.Errorf("deserialize submitmsg sig: %s", err)
 	}
-	if !signature.Verify(pub, hash[:], sig) {
+	//state root signed by sigsvr is prefixed with SUBMIT_DOMAIN
+	if !signature.Verify(pub, hash[:], sig) && !signature.Verify(pub, signer.SubmitData(hash[:]), sig) {
 		return fmt.Errorf("failed to verify submit sig")
 	}
 	return nil
@@ -412,3 +416,56 @@ func (msg *blockSubmitMsg) GetBlockNum() uint32 {
 func (msg *blockSubmitMsg) Serialize() ([]byte, error) {
 	return json.Marshal(msg)
 }
+
+//doubleSignEvidenceMsg carries the headers proving the proposer has signed
+//conflicting proposals at the block
+type doubleSignEvidenceMsg struct {
+	PeerPubkey string   `json:"peer_pubkey"`
+	Proposer   uint32   `json:"proposer"`
+	BlockNum   uint32   `json:"block_num"`
+	Headers    [][]byte `json:"headers"`
+}
+
+func (msg *doubleSignEvidenceMsg) Type() MsgType {
+	return DoubleSignEvidenceMessage
+}
+
+//Verify check the evidence itself, it can be relayed by any peer
+func (msg *doubleSignEvidenceMsg) Verify(pub keypair.PublicKey) error {
+	pk, err := vconfig.Pubkey(msg.PeerPubkey)
+	if err != nil {
+		return fmt.Errorf("invalid evidence peer: %s", err)
+	}
+	headers, err := msg.headers()
+	if err != nil {
+		return err
+	}
+	blkNum, err := vconfig.VerifyDoubleSign(pk, msg.Proposer, headers)
+	if err != nil {
+		return err
+	}
+	if blkNum != msg.BlockNum {
+		return fmt.Errorf("evidence of block %d, not %d", blkNum, msg.BlockNum)
+	}
+	return nil
+}
+
+func (msg *doubleSignEvidenceMsg) GetBlockNum() uint32 {
+	return msg.BlockNum
+}
+
+func (msg *doubleSignEvidenceMsg) Serialize() ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (msg *doubleSignEvidenceMsg) headers() ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, len(msg.Headers))
+	for _, raw := range msg.Headers {
+		header, err := types.HeaderFromRawBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("deserialize evidence header: %s", err)
+		}
+		headers = append(headers, header)
+	}
+	return headers, nil
+}
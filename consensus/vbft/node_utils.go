@@ -24,8 +24,8 @@ import (
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/consensus/signer"
 	"github.com/ontio/ontology/consensus/vbft/config"
-	"github.com/ontio/ontology/core/signature"
 	"github.com/ontio/ontology/p2pserver/message/msg_pack"
 	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
 )
@@ -188,9 +188,7 @@ func (self *Server) getHighestRankProposal(blockNum uint32, proposals []*blockPr
 	return proposal
 }
 
-//
-//  call this method with metaLock locked
-//
+// call this method with metaLock locked
 func (self *Server) buildParticipantConfig(blkNum uint32, block *Block, chainCfg *vconfig.ChainConfig) (*BlockParticipantConfig, error) {
 
 	if blkNum == 0 {
@@ -313,9 +311,9 @@ func calcParticipant(vrf vconfig.VRFValue, dposTable []uint32, k uint32) uint32
 //
 // check if commit msgs has reached consensus
 // return
-//		@ consensused proposer
-//		@ consensused for empty commit
 //
+//	@ consensused proposer
+//	@ consensused for empty commit
 func getCommitConsensus(commitMsgs []*blockCommitMsg, C int, N int) (uint32, bool) {
 	emptyCommitCount := 0
 	emptyCommit := false
@@ -404,12 +402,12 @@ func (self *Server) sendToPeer(peerIdx uint32, data []byte) error {
 	}
 	msg := &p2pmsg.ConsensusPayload{
 		Data:  data,
-		Owner: self.account.PublicKey,
+		Owner: self.signer.PubKey(),
 	}
 
 	sink := common.NewZeroCopySink(nil)
 	msg.SerializationUnsigned(sink)
-	msg.Signature, _ = signature.Sign(self.account, sink.Bytes())
+	msg.Signature, _ = signer.SignMsg(self.signer, sink.Bytes())
 
 	cons := msgpack.NewConsensus(msg)
 	p2pid, present := self.peerPool.getP2pId(peerIdx)
@@ -431,12 +429,12 @@ func (self *Server) broadcast(msg ConsensusMsg) {
 func (self *Server) broadcastToAll(data []byte) error {
 	msg := &p2pmsg.ConsensusPayload{
 		Data:  data,
-		Owner: self.account.PublicKey,
+		Owner: self.signer.PubKey(),
 	}
 
 	sink := common.NewZeroCopySink(nil)
 	msg.SerializationUnsigned(sink)
-	msg.Signature, _ = signature.Sign(self.account, sink.Bytes())
+	msg.Signature, _ = signer.SignMsg(self.signer, sink.Bytes())
 
 	self.p2p.Broadcast(msg)
 	return nil
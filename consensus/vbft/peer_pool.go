@@ -47,6 +47,7 @@ type PeerPool struct {
 
 	peers                  map[uint32]*Peer
 	peerConnectionWaitings map[uint32]chan struct{}
+	latencies              map[uint32]*peerLatency
 }
 
 func NewPeerPool(maxSize int, server *Server) *PeerPool {
@@ -58,6 +59,7 @@ func NewPeerPool(maxSize int, server *Server) *PeerPool {
 		P2pMap:                 make(map[uint32]uint64),
 		peers:                  make(map[uint32]*Peer),
 		peerConnectionWaitings: make(map[uint32]chan struct{}),
+		latencies:              make(map[uint32]*peerLatency),
 	}
 }
 
@@ -69,6 +71,7 @@ func (pool *PeerPool) clean() {
 	pool.IDMap = make(map[string]uint32)
 	pool.P2pMap = make(map[uint32]uint64)
 	pool.peers = make(map[uint32]*Peer)
+	pool.latencies = make(map[uint32]*peerLatency)
 }
 
 // FIXME: should rename to isPeerConnected
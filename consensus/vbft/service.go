@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"time"
@@ -29,10 +30,11 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology-crypto/vrf"
 	"github.com/ontio/ontology-eventbus/actor"
-	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	actorTypes "github.com/ontio/ontology/consensus/actor"
+	"github.com/ontio/ontology/consensus/signer"
 	"github.com/ontio/ontology/consensus/vbft/config"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
@@ -88,12 +90,13 @@ type p2pMsgPayload struct {
 
 type Server struct {
 	Index         uint32
-	account       *account.Account
+	signer        signer.ConsensusSigner
 	poolActor     *actorTypes.TxPoolActor
 	p2p           *actorTypes.P2PActor
 	ledger        *ledger.Ledger
 	incrValidator *increment.IncrementValidator
 	pid           *actor.PID
+	dataDir       string // directory of wal and evidence files
 
 	// some config
 	msgHistoryDuration uint32
@@ -110,11 +113,15 @@ type Server struct {
 	LastConfigBlockNum       uint32
 	config                   *vconfig.ChainConfig
 	currentParticipantConfig *BlockParticipantConfig
-
-	chainStore *ChainStore // block store
-	msgPool    *MsgPool    // consensus msg pool
-	blockPool  *BlockPool  // received block proposals
-	peerPool   *PeerPool   // consensus peers
+	roundBlockNum            uint32    // block num of the round measured by roundStartTime
+	roundStartTime           time.Time // time the current round started, for peer latency
+
+	chainStore *ChainStore    // block store
+	msgPool    *MsgPool       // consensus msg pool
+	blockPool  *BlockPool     // received block proposals
+	peerPool   *PeerPool      // consensus peers
+	evidence   *EvidenceStore // double sign evidence
+	wal        *ConsensusWAL  // messages signed by self
 	syncer     *Syncer
 	stateMgr   *StateMgr
 	timer      *EventTimer
@@ -129,14 +136,26 @@ type Server struct {
 	quitWg     sync.WaitGroup
 }
 
-func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, error) {
+//NewVbftServer create the server of node, the files are kept with the ledger
+//in the data directory of network
+func NewVbftServer(signer signer.ConsensusSigner, txpool, p2p *actor.PID) (*Server, error) {
+	dataDir := filepath.Join(config.DefConfig.Common.DataDir, config.DefConfig.P2PNode.NetworkName)
+	return newVbftServer(signer, txpool, p2p, ledger.DefLedger, "consensus_vbft", dataDir)
+}
+
+//newVbftServer create a server on the ledger, the actor is spawned without
+//name if name is empty, so that several servers can run in one process. The
+//files of server are kept in dataDir
+func newVbftServer(signer signer.ConsensusSigner, txpool, p2p *actor.PID, backend *ledger.Ledger, name, dataDir string) (*Server, error) {
 	server := &Server{
 		msgHistoryDuration: 64,
-		account:            account,
+		signer:             signer,
 		poolActor:          &actorTypes.TxPoolActor{Pool: txpool},
 		p2p:                &actorTypes.P2PActor{P2P: p2p},
-		ledger:             ledger.DefLedger,
+		ledger:             backend,
 		incrValidator:      increment.NewIncrementValidator(20),
+		dataDir:            dataDir,
+		evidence:           NewEvidenceStore(filepath.Join(dataDir, EVIDENCE_FILE_NAME)),
 	}
 	server.stateMgr = newStateMgr(server)
 
@@ -144,7 +163,13 @@ func NewVbftServer(account *account.Account, txpool, p2p *actor.PID) (*Server, e
 		return server
 	})
 
-	pid, err := actor.SpawnNamed(props, "consensus_vbft")
+	var pid *actor.PID
+	var err error
+	if name == "" {
+		pid = actor.Spawn(props)
+	} else {
+		pid, err = actor.SpawnNamed(props, name)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +198,15 @@ func (self *Server) Receive(context actor.Context) {
 		log.Info("vbft actor start consensus")
 	case *actorTypes.StopConsensus:
 		self.stop()
+	case *actorTypes.GetConsensusStatusReq:
+		if sender := context.Sender(); sender != nil {
+			sender.Request(&actorTypes.GetConsensusStatusRsp{Status: self.getConsensusStatus()}, context.Self())
+		}
 	case *message.SaveBlockCompleteMsg:
+		//the event hub is shared by all ledgers of process
+		if self.ledger.GetBlockHash(msg.Block.Header.Height) != msg.Block.Hash() {
+			return
+		}
 		log.Infof("vbft actor SaveBlockCompleteMsg receives block complete event. block height=%d, numtx=%d",
 			msg.Block.Header.Height, len(msg.Block.Transactions))
 		self.handleBlockPersistCompleted(msg.Block)
@@ -212,6 +245,9 @@ func (self *Server) handleBlockPersistCompleted(block *types.Block) {
 	}
 	self.completedBlockNum = block.Header.Height
 	self.incrValidator.AddBlock(block)
+	if err := self.wal.Prune(block.Header.Height); err != nil {
+		log.Errorf("server %d failed to prune wal: %s", self.Index, err)
+	}
 	if self.nonConsensusNode() {
 		self.chainStore.ReloadFromLedger()
 		self.metaLock.Lock()
@@ -310,13 +346,7 @@ func (self *Server) LoadChainConfig(blkNum uint32) error {
 	if self.config.View == 0 || self.config.MaxBlockChangeView == 0 {
 		panic("invalid view or maxblockchangeview ")
 	}
-	// update msg delays
-	makeProposalTimeout = time.Duration(self.config.BlockMsgDelay * 2)
-	make2ndProposalTimeout = time.Duration(self.config.BlockMsgDelay)
-	endorseBlockTimeout = time.Duration(self.config.HashMsgDelay * 2)
-	commitBlockTimeout = time.Duration(self.config.HashMsgDelay * 3)
-	peerHandshakeTimeout = time.Duration(self.config.PeerHandshakeTimeout)
-	zeroTxBlockTimeout = time.Duration(self.config.BlockMsgDelay * 3)
+	self.updateMsgDelays()
 	// TODO: load sealed blocks from chainStore
 
 	// protected by server.metaLock
@@ -338,6 +368,20 @@ func (self *Server) LoadChainConfig(blkNum uint32) error {
 	return nil
 }
 
+//updateMsgDelays resets timeouts with chain config, should call with metaLock held
+func (self *Server) updateMsgDelays() {
+	makeProposalTimeout = time.Duration(self.config.BlockMsgDelay * 2)
+	make2ndProposalTimeout = time.Duration(self.config.BlockMsgDelay)
+	endorseBlockTimeout = time.Duration(self.config.HashMsgDelay * 2)
+	commitBlockTimeout = time.Duration(self.config.HashMsgDelay * 3)
+	peerHandshakeTimeout = time.Duration(self.config.PeerHandshakeTimeout)
+	if self.config.DynamicBlockInterval() {
+		zeroTxBlockTimeout = self.config.MaxBlockInterval
+	} else {
+		zeroTxBlockTimeout = time.Duration(self.config.BlockMsgDelay * 3)
+	}
+}
+
 func (self *Server) nonConsensusNode() bool {
 	return self.Index == math.MaxUint32
 }
@@ -356,16 +400,22 @@ func (self *Server) updateChainConfig() error {
 	self.config = block.Info.NewChainConfig
 	self.LastConfigBlockNum = block.getLastConfigBlockNum()
 	self.metaLock.Unlock()
+	self.publishConsensusEvent(&message.ConsensusEventMsg{
+		Event:    CONSENSUS_EVENT_VIEW_CHANGE,
+		BlockNum: self.completedBlockNum,
+		Peer:     self.Index,
+	})
 
 	self.metaLock.RLock()
 	defer self.metaLock.RUnlock()
 
+	self.updateMsgDelays()
 	// TODO
 	// 1. update peer pool
 	// 2. remove nonparticipation consensus node
 	// 3. update statemgr peers
 	// 4. reset remove peer connections, create new connections with new peers
-	pubkey := vconfig.PubkeyID(self.account.PublicKey)
+	pubkey := vconfig.PubkeyID(self.signer.PubKey())
 	peermap := make(map[uint32]string)
 	for _, p := range self.config.Peers {
 		peermap[p.Index] = p.ID
@@ -426,7 +476,7 @@ func (self *Server) initialize() error {
 	// TODO: load config from chain
 
 	// TODO: configurable log
-	selfNodeId := vconfig.PubkeyID(self.account.PublicKey)
+	selfNodeId := vconfig.PubkeyID(self.signer.PubKey())
 	log.Infof("server: %s starting", selfNodeId)
 
 	store, err := OpenBlockStore(self.ledger, self.pid)
@@ -437,6 +487,15 @@ func (self *Server) initialize() error {
 	self.chainStore = store
 	log.Info("block store opened")
 
+	self.wal, err = OpenConsensusWAL(filepath.Join(self.dataDir, WAL_FILE_NAME))
+	if err != nil {
+		log.Errorf("failed to open wal: %s", err)
+		return fmt.Errorf("failed to open wal: %s", err)
+	}
+	if err := self.wal.Prune(store.GetChainedBlockNum()); err != nil {
+		log.Errorf("failed to prune wal: %s", err)
+	}
+
 	self.blockPool, err = newBlockPool(self, self.msgHistoryDuration, store)
 	if err != nil {
 		log.Errorf("init blockpool: %s", err)
@@ -475,7 +534,7 @@ func (self *Server) initialize() error {
 	}
 
 	//index equal math.MaxUint32  is noconsensus node
-	id := vconfig.PubkeyID(self.account.PublicKey)
+	id := vconfig.PubkeyID(self.signer.PubKey())
 	index, present := self.peerPool.GetPeerIndex(id)
 	if present {
 		self.Index = index
@@ -515,8 +574,8 @@ func (self *Server) initialize() error {
 
 func (self *Server) start() error {
 	// check if server pubkey support VRF
-	if !vrf.ValidatePrivateKey(self.account.PrivateKey) || !vrf.ValidatePublicKey(self.account.PublicKey) {
-		return fmt.Errorf("server %d consensus start failed: invalid account key for VRF", self.Index)
+	if !vrf.ValidatePublicKey(self.signer.PubKey()) {
+		return fmt.Errorf("server %d consensus start failed: invalid consensus key for VRF", self.Index)
 	}
 
 	// start heartbeat ticker
@@ -556,6 +615,7 @@ func (self *Server) stop() {
 	self.blockPool.clean()
 	self.chainStore.close()
 	self.peerPool.clean()
+	self.wal.Close()
 }
 
 //
@@ -669,6 +729,12 @@ func (self *Server) startNewRound() error {
 		log.Errorf("startNewRound error:%s", err)
 		return err
 	}
+	self.startRoundClock(blkNum)
+	self.publishConsensusEvent(&message.ConsensusEventMsg{
+		Event:    CONSENSUS_EVENT_NEW_ROUND,
+		BlockNum: blkNum,
+		Peer:     self.Index,
+	})
 	// check proposals in msgpool
 	var proposal *blockProposalMsg
 	if proposals := self.msgPool.GetProposalMsgs(blkNum); len(proposals) > 0 {
@@ -772,6 +838,7 @@ func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash com
 		log.Debugf("dup msg with msg type %d from %d", msg.Type(), peerIdx)
 		return
 	}
+	self.recordPeerMsg(peerIdx, msg)
 
 	switch msg.Type() {
 	case BlockProposalMessage:
@@ -829,6 +896,14 @@ func (self *Server) onConsensusMsg(peerIdx uint32, msg ConsensusMsg, msgHash com
 			self.processProposalMsg(pMsg)
 		}
 
+	case DoubleSignEvidenceMessage:
+		pMsg, ok := msg.(*doubleSignEvidenceMsg)
+		if !ok {
+			log.Error("invalid msg with evidence msg type")
+			return
+		}
+		self.processEvidenceMsg(pMsg)
+
 	case BlockEndorseMessage:
 		pMsg, ok := msg.(*blockEndorseMsg)
 		if !ok {
@@ -1205,7 +1280,7 @@ func (self *Server) processMsgEvent() error {
 				// add proposal to block-pool
 				if err := self.blockPool.newBlockProposal(pMsg); err != nil {
 					if err == errDupProposal {
-						// TODO: faulty proposer detected
+						self.reportDoubleProposal(pMsg)
 					}
 					log.Errorf("failed to add block proposal (%d): %s", msgBlkNum, err)
 					return nil
@@ -1638,6 +1713,17 @@ func (self *Server) timerLoop() {
 }
 
 func (self *Server) processTimerEvent(evt *TimerEvent) error {
+	switch evt.evtType {
+	case EventProposeBlockTimeout, EventPropose2ndBlockTimeout, EventEndorseBlockTimeout,
+		EventEndorseEmptyBlockTimeout, EventCommitBlockTimeout:
+		self.publishConsensusEvent(&message.ConsensusEventMsg{
+			Event:    CONSENSUS_EVENT_TIMEOUT,
+			BlockNum: evt.blockNum,
+			Peer:     self.Index,
+			Detail:   evt.evtType.String(),
+		})
+	}
+
 	switch evt.evtType {
 	case EventProposalBackoff:
 		// 1. if endorsed, return
@@ -1828,15 +1914,7 @@ func (self *Server) processTimerEvent(evt *TimerEvent) error {
 	case EventTxPool:
 		self.timer.stopTxTicker(evt.blockNum)
 		if self.completedBlockNum+1 == evt.blockNum {
-			validHeight := self.validHeight(evt.blockNum)
-			newProposal := false
-			for _, e := range self.poolActor.GetTxnPool(true, validHeight) {
-				if err := self.incrValidator.Verify(e.Tx, validHeight); err == nil {
-					newProposal = true
-					break
-				}
-			}
-			if newProposal {
+			if self.readyToPropose(evt.blockNum) {
 				self.timer.CancelTxBlockTimeout(evt.blockNum)
 				self.startNewProposal(evt.blockNum)
 			} else {
@@ -1915,6 +1993,12 @@ func (self *Server) endorseBlock(proposal *blockProposalMsg, forEmpty bool) erro
 	if err := self.blockPool.setProposalEndorsed(proposal, forEmpty); err != nil {
 		return fmt.Errorf("failed to set proposal as endorsed: %s", err)
 	}
+	self.publishConsensusEvent(&message.ConsensusEventMsg{
+		Event:    CONSENSUS_EVENT_ENDORSED,
+		BlockNum: blkNum,
+		Peer:     proposal.Block.getProposer(),
+		Detail:   fmt.Sprintf("forEmpty: %t", forEmpty),
+	})
 
 	self.processConsensusMsg(endorseMsg)
 	// if node is endorser of current round
@@ -2072,6 +2156,12 @@ func (self *Server) sealBlock(block *Block, empty bool, sigdata bool) error {
 	prevBlkHash := block.getPrevBlockHash()
 	log.Infof("server %d, sealed block %d, proposer %d, prevhash: %s, hash: %s", self.Index,
 		sealedBlkNum, block.getProposer(), prevBlkHash.ToHexString(), h.ToHexString())
+	self.publishConsensusEvent(&message.ConsensusEventMsg{
+		Event:    CONSENSUS_EVENT_COMMITTED,
+		BlockNum: sealedBlkNum,
+		Peer:     block.getProposer(),
+		Detail:   fmt.Sprintf("empty: %t, hash: %s", empty, h.ToHexString()),
+	})
 
 	// broadcast to other modules
 	// TODO: block committed, update tx pool, notify block-listeners
@@ -2143,7 +2233,7 @@ func (self *Server) checkNeedUpdateChainConfig(blockNum uint32) bool {
 
 //checkUpdateChainConfig query leveldb check is force update
 func (self *Server) checkUpdateChainConfig(blkNum uint32) bool {
-	force, err := isUpdate(self.blockPool.getExecWriteSet(blkNum-1), self.config.View)
+	force, err := isUpdate(self.blockPool.getExecWriteSet(blkNum-1), self.ledger, self.config.View)
 	if err != nil {
 		log.Errorf("checkUpdateChainConfig err:%s", err)
 		return false
@@ -2164,6 +2254,33 @@ func (self *Server) validHeight(blkNum uint32) uint32 {
 	return validHeight
 }
 
+//readyToPropose checks txs in pool to propose block blkNum before zeroTxBlockTimeout
+func (self *Server) readyToPropose(blkNum uint32) bool {
+	self.metaLock.RLock()
+	cfg := self.config
+	self.metaLock.RUnlock()
+
+	threshold := 1
+	if cfg.DynamicBlockInterval() && cfg.TxThreshold > 1 {
+		threshold = int(cfg.TxThreshold)
+	}
+	validHeight := self.validHeight(blkNum)
+	txCount := 0
+	for _, e := range self.poolActor.GetTxnPool(true, validHeight) {
+		if err := self.incrValidator.Verify(e.Tx, validHeight); err == nil {
+			txCount++
+			if txCount >= threshold {
+				break
+			}
+		}
+	}
+	elapsed, ok := self.roundLatency(blkNum)
+	if !ok {
+		elapsed = cfg.BlockInterval
+	}
+	return proposeWithTxs(cfg, txCount, elapsed)
+}
+
 func (self *Server) nonSystxs(sysTxs []*types.Transaction, blkNum uint32) bool {
 	if self.checkNeedUpdateChainConfig(blkNum) && len(sysTxs) == 1 {
 		invoke := sysTxs[0].Payload.(*payload.InvokeCode)
@@ -2192,7 +2309,7 @@ func (self *Server) makeProposal(blkNum uint32, forEmpty bool) error {
 	cfg := &vconfig.ChainConfig{}
 	cfg = nil
 	if self.checkNeedUpdateChainConfig(blkNum) || self.checkUpdateChainConfig(blkNum) {
-		chainconfig, err := getChainConfig(self.blockPool.getExecWriteSet(blkNum-1), blkNum)
+		chainconfig, err := getChainConfig(self.blockPool.getExecWriteSet(blkNum-1), self.ledger, blkNum)
 		if err != nil {
 			return fmt.Errorf("getChainConfig failed:%s", err)
 		}
@@ -0,0 +1,360 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/consensus/signer"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/events"
+	netActor "github.com/ontio/ontology/p2pserver/actor/server"
+	"github.com/ontio/ontology/p2pserver/message/msg_pack"
+	p2pmsg "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/ontio/ontology/p2pserver/net/simnet"
+	txpool "github.com/ontio/ontology/txnpool/common"
+)
+
+const SIM_NODE_NUM = 7
+
+var simEventsOnce sync.Once
+
+//simTxPool answers the requests of consensus with an empty pool
+type simTxPool struct{}
+
+func (this *simTxPool) Receive(ctx actor.Context) {
+	switch ctx.Message().(type) {
+	case *txpool.GetTxnPoolReq:
+		ctx.Sender().Request(&txpool.GetTxnPoolRsp{}, ctx.Self())
+	case *txpool.VerifyBlockReq:
+		ctx.Sender().Request(&txpool.VerifyBlockRsp{}, ctx.Self())
+	}
+}
+
+//simP2P forwards the consensus messages to the simulated node
+type simP2P struct {
+	node *simnet.Node
+}
+
+func (this *simP2P) Receive(ctx actor.Context) {
+	switch msg := ctx.Message().(type) {
+	case *netActor.TransmitConsensusMsgReq:
+		if p := this.node.GetPeer(msg.Target); p != nil {
+			this.node.Send(p, msg.Msg)
+		}
+	case *p2pmsg.ConsensusPayload:
+		this.node.Xmit(msgpack.NewConsensus(msg))
+	}
+}
+
+type simNode struct {
+	node   *simnet.Node
+	server *Server
+	ledger *ledger.Ledger
+}
+
+type simCluster struct {
+	t     *testing.T
+	net   *simnet.Network
+	nodes []*simNode
+	dir   string
+	quit  chan struct{}
+}
+
+func newSimCluster(t *testing.T) *simCluster {
+	if testing.Short() {
+		t.Skip("skip vbft simulation in short mode")
+	}
+	simEventsOnce.Do(func() {
+		log.InitLog(log.FatalLog, log.Stdout)
+		events.Init()
+	})
+	dir, err := ioutil.TempDir("", "vbft-sim")
+	if err != nil {
+		t.Fatalf("create data dir: %s", err)
+	}
+
+	accounts := make([]*account.Account, 0, SIM_NODE_NUM)
+	bookkeepers := make([]keypair.PublicKey, 0, SIM_NODE_NUM)
+	peers := make([]*config.VBFTPeerStakeInfo, 0, SIM_NODE_NUM)
+	for i := 0; i < SIM_NODE_NUM; i++ {
+		acct := account.NewAccount("")
+		accounts = append(accounts, acct)
+		bookkeepers = append(bookkeepers, acct.PublicKey)
+		peers = append(peers, &config.VBFTPeerStakeInfo{
+			Index:      uint32(i + 1),
+			PeerPubkey: vconfig.PubkeyID(acct.PublicKey),
+			Address:    acct.Address.ToBase58(),
+			InitPos:    10000,
+		})
+	}
+	defGenesis := config.DefConfig.Genesis
+	config.DefConfig.Genesis = &config.GenesisConfig{
+		ConsensusType: config.CONSENSUS_TYPE_VBFT,
+		VBFT: &config.VBFTConfig{
+			N:                    SIM_NODE_NUM,
+			C:                    2,
+			K:                    SIM_NODE_NUM,
+			L:                    16 * SIM_NODE_NUM,
+			BlockMsgDelay:        5000,
+			HashMsgDelay:         5000,
+			PeerHandshakeTimeout: 10,
+			MaxBlockChangeView:   10000,
+			MinInitStake:         10000,
+			VrfValue:             config.PolarisConfig.VBFT.VrfValue,
+			VrfProof:             config.PolarisConfig.VBFT.VrfProof,
+			Peers:                peers,
+		},
+		DBFT: &config.DBFTConfig{},
+		SOLO: &config.SOLOConfig{},
+	}
+	defer func() {
+		config.DefConfig.Genesis = defGenesis
+	}()
+	block, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	if err != nil {
+		t.Fatalf("build genesis block: %s", err)
+	}
+
+	cluster := &simCluster{
+		t:    t,
+		net:  simnet.NewNetwork(),
+		dir:  dir,
+		quit: make(chan struct{}),
+	}
+	txPoolPid := actor.Spawn(actor.FromProducer(func() actor.Actor { return &simTxPool{} }))
+	for i, acct := range accounts {
+		nodeDir := filepath.Join(dir, fmt.Sprintf("node%d", i+1))
+		db, err := ledger.NewLedger(nodeDir, 0)
+		if err != nil {
+			t.Fatalf("new ledger: %s", err)
+		}
+		if err = db.Init(bookkeepers, block); err != nil {
+			t.Fatalf("init ledger: %s", err)
+		}
+		node := cluster.net.NewNode(uint64(i + 1))
+		p2pPid := actor.Spawn(actor.FromProducer(func() actor.Actor { return &simP2P{node: node} }))
+		//sign through a guard, so that any double signing is refused
+		guard, err := signer.NewSignGuard(filepath.Join(nodeDir, signer.GUARD_FILE_NAME))
+		if err != nil {
+			t.Fatalf("new sign guard: %s", err)
+		}
+		server, err := newVbftServer(signer.NewLocalSigner(acct, guard), txPoolPid, p2pPid, db, "", nodeDir)
+		if err != nil {
+			t.Fatalf("new vbft server: %s", err)
+		}
+		cluster.nodes = append(cluster.nodes, &simNode{node: node, server: server, ledger: db})
+	}
+
+	//the delays of genesis config are too long for tests, scale all of the
+	//timers down by ten to keep their ratios
+	makeProposalTimeout = time.Second
+	make2ndProposalTimeout = 500 * time.Millisecond
+	endorseBlockTimeout = time.Second
+	commitBlockTimeout = 1500 * time.Millisecond
+	peerHandshakeTimeout = time.Second
+	txPooltimeout = 100 * time.Millisecond
+	zeroTxBlockTimeout = 1500 * time.Millisecond
+	for _, n := range cluster.nodes {
+		n.server.stateMgr.syncReadyTimeout = time.Second
+	}
+
+	for _, n := range cluster.nodes {
+		go cluster.dispatch(n)
+		if err := n.server.Start(); err != nil {
+			t.Fatalf("start vbft server: %s", err)
+		}
+	}
+	return cluster
+}
+
+//dispatch deliver the consensus messages received by node to the server, as
+//the p2p message handler does
+func (this *simCluster) dispatch(n *simNode) {
+	for {
+		select {
+		case data := <-n.node.GetMsgChan():
+			cons, ok := data.Payload.(*p2pmsg.Consensus)
+			if !ok {
+				continue
+			}
+			if err := cons.Cons.Verify(); err != nil {
+				continue
+			}
+			cons.Cons.PeerId = data.Id
+			n.server.GetPID().Tell(&cons.Cons)
+		case <-this.quit:
+			return
+		}
+	}
+}
+
+func (this *simCluster) close() {
+	close(this.quit)
+	for _, n := range this.nodes {
+		n.server.Halt()
+	}
+	this.net.Close()
+	//wait the servers stopping before closing ledgers
+	time.Sleep(time.Second)
+	for _, n := range this.nodes {
+		n.ledger.Close()
+	}
+	os.RemoveAll(this.dir)
+}
+
+func (this *simCluster) height(i int) uint32 {
+	return this.nodes[i].ledger.GetCurrentBlockHeight()
+}
+
+//waitHeight wait until all of the nodes listed reach the height
+func (this *simCluster) waitHeight(height uint32, timeout time.Duration, nodes ...int) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		reached := true
+		for _, i := range nodes {
+			if this.height(i) < height {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
+
+//checkSafety check no two nodes have committed different blocks at same height
+func (this *simCluster) checkSafety() {
+	for h := uint32(1); ; h++ {
+		ref := common.UINT256_EMPTY
+		refNode := 0
+		for i, n := range this.nodes {
+			hash := n.ledger.GetBlockHash(h)
+			if hash == common.UINT256_EMPTY {
+				continue
+			}
+			if ref == common.UINT256_EMPTY {
+				ref, refNode = hash, i
+			} else if hash != ref {
+				this.t.Fatalf("node %d commit block %s at height %d, node %d commit %s",
+					i+1, hash.ToHexString(), h, refNode+1, ref.ToHexString())
+			}
+		}
+		if ref == common.UINT256_EMPTY {
+			return
+		}
+	}
+}
+
+func allNodes(from, to int) []int {
+	nodes := make([]int, 0, to-from)
+	for i := from; i < to; i++ {
+		nodes = append(nodes, i)
+	}
+	return nodes
+}
+
+func TestSimulationLiveness(t *testing.T) {
+	cluster := newSimCluster(t)
+	defer cluster.close()
+	cluster.net.SetLatency(time.Millisecond, 20*time.Millisecond)
+
+	if !cluster.waitHeight(3, time.Minute, allNodes(0, SIM_NODE_NUM)...) {
+		t.Fatalf("chain not advance, height of node 1: %d", cluster.height(0))
+	}
+	cluster.checkSafety()
+}
+
+func TestSimulationCrashFault(t *testing.T) {
+	cluster := newSimCluster(t)
+	defer cluster.close()
+
+	cluster.nodes[6].node.Halt()
+	if !cluster.waitHeight(3, time.Minute, allNodes(0, 6)...) {
+		t.Fatalf("chain not advance with crashed nodes, height of node 1: %d", cluster.height(0))
+	}
+	cluster.checkSafety()
+}
+
+func TestSimulationPartition(t *testing.T) {
+	cluster := newSimCluster(t)
+	defer cluster.close()
+
+	cluster.net.Partition([]uint64{1, 2, 3, 4, 5, 6}, []uint64{7})
+	if !cluster.waitHeight(3, time.Minute, allNodes(0, 6)...) {
+		t.Fatalf("majority not advance in partition, height of node 1: %d", cluster.height(0))
+	}
+	cluster.checkSafety()
+
+	cluster.net.Heal()
+	target := cluster.height(0) + 2
+	if !cluster.waitHeight(target, time.Minute, allNodes(0, SIM_NODE_NUM)...) {
+		t.Fatalf("nodes not catch up after heal, height of node 7: %d, node 1: %d",
+			cluster.height(6), cluster.height(0))
+	}
+	cluster.checkSafety()
+}
+
+func TestSimulationByzantine(t *testing.T) {
+	cluster := newSimCluster(t)
+	defer cluster.close()
+
+	//node 6 tampers its messages and node 7 sends each message twice
+	cluster.net.SetInterceptor(func(from, to uint64, msg p2pmsg.Message) []p2pmsg.Message {
+		cons, ok := msg.(*p2pmsg.Consensus)
+		if !ok {
+			return []p2pmsg.Message{msg}
+		}
+		switch from {
+		case 6:
+			payload := cons.Cons
+			data := make([]byte, len(payload.Data))
+			copy(data, payload.Data)
+			if len(data) > 0 {
+				data[len(data)/2] ^= 0xff
+			}
+			payload.Data = data
+			return []p2pmsg.Message{&p2pmsg.Consensus{Cons: payload}}
+		case 7:
+			return []p2pmsg.Message{msg, msg}
+		}
+		return []p2pmsg.Message{msg}
+	})
+	if !cluster.waitHeight(3, time.Minute, allNodes(0, 5)...) {
+		t.Fatalf("chain not advance with byzantine nodes, height of node 1: %d", cluster.height(0))
+	}
+	cluster.checkSafety()
+}
@@ -23,9 +23,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/consensus/signer"
+	vconfig "github.com/ontio/ontology/consensus/vbft/config"
 )
 
 func HashBlock(blk *Block) (common.Uint256, error) {
@@ -90,7 +93,7 @@ func TestVrf(t *testing.T) {
 	user := account.NewAccount("")
 	prevVrf := []byte("test string")
 	blkNum := uint32(10)
-	v1, p1, err := computeVrf(user.PrivKey(), blkNum, prevVrf)
+	v1, p1, err := computeVrf(signer.NewLocalSigner(user, nil), blkNum, prevVrf)
 	if err != nil {
 		t.Fatalf("compute vrf: %s", err)
 	}
@@ -105,3 +108,29 @@ func TestVrf(t *testing.T) {
 	x := base64.StdEncoding.EncodeToString(prevVrf)
 	fmt.Println(x)
 }
+
+func TestProposeWithTxs(t *testing.T) {
+	cfg := &vconfig.ChainConfig{}
+	if proposeWithTxs(cfg, 0, time.Hour) {
+		t.Fatalf("empty block proposed before zero tx block timeout")
+	}
+	if !proposeWithTxs(cfg, 1, 0) {
+		t.Fatalf("txs not proposed at once with fixed block interval")
+	}
+
+	cfg.BlockInterval = 2 * time.Second
+	cfg.MaxBlockInterval = time.Minute
+	cfg.TxThreshold = 10
+	if proposeWithTxs(cfg, 0, time.Hour) {
+		t.Fatalf("empty block proposed before max block interval")
+	}
+	if proposeWithTxs(cfg, 9, time.Second) {
+		t.Fatalf("txs below threshold proposed before block interval")
+	}
+	if !proposeWithTxs(cfg, 9, 2*time.Second) {
+		t.Fatalf("txs below threshold not proposed after block interval")
+	}
+	if !proposeWithTxs(cfg, 10, 0) {
+		t.Fatalf("txs reaching threshold not proposed at once")
+	}
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/ontio/ontology/common/log"
+)
+
+const WAL_FILE_NAME = "vbft.wal"
+
+type walKey struct {
+	blkNum   uint32
+	msgType  MsgType
+	forEmpty bool
+}
+
+func walKeyOf(msg ConsensusMsg) walKey {
+	key := walKey{blkNum: msg.GetBlockNum(), msgType: msg.Type()}
+	if e, ok := msg.(*blockEndorseMsg); ok {
+		key.forEmpty = e.EndorseForEmpty
+	}
+	return key
+}
+
+//ConsensusWAL write-ahead-logs the proposal, endorse and commit messages
+//signed by the server. The messages are reloaded after restart, so that the
+//server never signs conflicting messages for one block.
+//
+//Each record is one line of serialized vbft msg, the file is appended and
+//synced before the message is sent, and compacted when blocks are persisted.
+type ConsensusWAL struct {
+	lock sync.Mutex
+	path string
+	file *os.File
+	msgs map[walKey]ConsensusMsg
+}
+
+//OpenConsensusWAL load the messages logged in path and open it for appending,
+//messages are only kept in memory if path is empty
+func OpenConsensusWAL(path string) (*ConsensusWAL, error) {
+	wal := &ConsensusWAL{
+		path: path,
+		msgs: make(map[walKey]ConsensusMsg),
+	}
+	if path == "" {
+		return wal, nil
+	}
+	if err := wal.load(); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open wal %s: %s", path, err)
+	}
+	wal.file = file
+	return wal, nil
+}
+
+func (self *ConsensusWAL) load() error {
+	file, err := os.Open(self.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("open wal %s: %s", self.path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			//the last record is incomplete if crashed while writing it,
+			//the message was never sent
+			if len(line) > 0 {
+				log.Warnf("wal %s: drop incomplete record", self.path)
+			}
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("read wal %s: %s", self.path, err)
+		}
+		msg, err := DeserializeVbftMsg(line[:len(line)-1])
+		if err != nil {
+			return fmt.Errorf("wal %s: %s", self.path, err)
+		}
+		self.msgs[walKeyOf(msg)] = msg
+	}
+}
+
+//Get return the message logged for the block, nil if not found
+func (self *ConsensusWAL) Get(blkNum uint32, msgType MsgType, forEmpty bool) ConsensusMsg {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	return self.msgs[walKey{blkNum: blkNum, msgType: msgType, forEmpty: forEmpty}]
+}
+
+//Append log the message, it must succeed before the message is sent. Only the
+//first message of same block and type is kept
+func (self *ConsensusWAL) Append(msg ConsensusMsg) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	key := walKeyOf(msg)
+	if _, present := self.msgs[key]; present {
+		return nil
+	}
+	if self.path != "" {
+		if self.file == nil {
+			return fmt.Errorf("wal %s is closed", self.path)
+		}
+		data, err := SerializeVbftMsg(msg)
+		if err != nil {
+			return err
+		}
+		if _, err := self.file.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("write wal: %s", err)
+		}
+		if err := self.file.Sync(); err != nil {
+			return fmt.Errorf("sync wal: %s", err)
+		}
+	}
+	self.msgs[key] = msg
+	return nil
+}
+
+//Prune remove the messages of blocks up to blkNum, which are persisted and
+//never signed again
+func (self *ConsensusWAL) Prune(blkNum uint32) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	pruned := false
+	for key := range self.msgs {
+		if key.blkNum <= blkNum {
+			delete(self.msgs, key)
+			pruned = true
+		}
+	}
+	if !pruned || self.file == nil {
+		return nil
+	}
+
+	//rewrite the remaining messages, and replace the log atomically
+	tmpPath := self.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("create wal %s: %s", tmpPath, err)
+	}
+	for _, msg := range self.msgs {
+		data, err := SerializeVbftMsg(msg)
+		if err == nil {
+			_, err = tmp.Write(append(data, '\n'))
+		}
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("write wal %s: %s", tmpPath, err)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync wal %s: %s", tmpPath, err)
+	}
+	tmp.Close()
+	self.file.Close()
+	renameErr := os.Rename(tmpPath, self.path)
+	self.file, err = os.OpenFile(self.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open wal %s: %s", self.path, err)
+	}
+	if renameErr != nil {
+		return fmt.Errorf("replace wal %s: %s", self.path, renameErr)
+	}
+	return nil
+}
+
+func (self *ConsensusWAL) Close() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+	if self.file == nil {
+		return nil
+	}
+	err := self.file.Close()
+	self.file = nil
+	return err
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package vbft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/consensus/signer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsensusWAL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vbft-wal")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, WAL_FILE_NAME)
+
+	acc := account.NewAccount("")
+	proposal := constructTestProposal(t, acc, 100)
+	wal, err := OpenConsensusWAL(path)
+	assert.Nil(t, err)
+	assert.Nil(t, wal.Append(proposal))
+	//only the first message of block is kept
+	assert.Nil(t, wal.Append(constructTestProposal(t, acc, 200)))
+	endorse, err := constructEndorseMsg(acc, proposal, proposal.Block.Block.Hash())
+	assert.Nil(t, err)
+	assert.Nil(t, wal.Append(endorse))
+	wal.Close()
+
+	//a record partially written when crashed is dropped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.Nil(t, err)
+	file.Write([]byte(`{"type":2,"len":`))
+	file.Close()
+
+	wal, err = OpenConsensusWAL(path)
+	assert.Nil(t, err)
+	p, ok := wal.Get(20, BlockProposalMessage, false).(*blockProposalMsg)
+	assert.True(t, ok)
+	assert.Equal(t, proposal.Block.Block.Hash(), p.Block.Block.Hash())
+	assert.NotNil(t, wal.Get(endorse.GetBlockNum(), BlockEndorseMessage, endorse.EndorseForEmpty))
+	assert.Nil(t, wal.Get(endorse.GetBlockNum(), BlockEndorseMessage, !endorse.EndorseForEmpty))
+
+	assert.Nil(t, wal.Prune(20))
+	assert.Nil(t, wal.Get(20, BlockProposalMessage, false))
+	wal.Close()
+	wal, err = OpenConsensusWAL(path)
+	assert.Nil(t, err)
+	assert.Nil(t, wal.Get(20, BlockProposalMessage, false))
+	wal.Close()
+}
+
+func TestWALRefuseConflictingMsgs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vbft-wal")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, WAL_FILE_NAME)
+
+	acc := account.NewAccount("")
+	first := constructTestProposal(t, acc, 100)
+	second := constructTestProposal(t, acc, 200)
+	newServer := func() *Server {
+		wal, err := OpenConsensusWAL(path)
+		assert.Nil(t, err)
+		return &Server{signer: signer.NewLocalSigner(acc, nil), Index: 2, wal: wal, evidence: NewEvidenceStore("")}
+	}
+
+	server := newServer()
+	_, err = server.constructEndorseMsg(first, false)
+	assert.Nil(t, err)
+	_, err = server.constructCommitMsg(first, nil, false)
+	assert.Nil(t, err)
+	server.wal.Close()
+
+	//restarted server only signs the blocks signed before
+	server = newServer()
+	defer server.wal.Close()
+	_, err = server.constructEndorseMsg(first, false)
+	assert.Nil(t, err)
+	_, err = server.constructEndorseMsg(second, false)
+	assert.NotNil(t, err)
+	_, err = server.constructEndorseMsg(second, true)
+	assert.Nil(t, err)
+	_, err = server.constructCommitMsg(second, nil, false)
+	assert.NotNil(t, err)
+	_, err = server.constructCommitMsg(first, nil, false)
+	assert.Nil(t, err)
+}
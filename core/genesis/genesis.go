@@ -101,6 +101,10 @@ func BuildGenesisBlock(defaultBookkeeper []keypair.PublicKey, genesisConfig *con
 	oid := deployOntIDContract()
 	auth := deployAuthContract()
 	govConfigTx := newGovConfigTx()
+	prefund, err := parsePrefund(genesisConfig)
+	if err != nil {
+		return nil, fmt.Errorf("prefund accounts init failed: %s", err)
+	}
 
 	genesisBlock := &types.Block{
 		Header: genesisHeader,
@@ -111,8 +115,8 @@ func BuildGenesisBlock(defaultBookkeeper []keypair.PublicKey, genesisConfig *con
 			oid,
 			auth,
 			govConfigTx,
-			newGoverningInit(),
-			newUtilityInit(),
+			newGoverningInit(prefund),
+			newUtilityInit(prefund),
 			newParamInit(),
 			govConfig,
 		},
@@ -200,7 +204,43 @@ func deployOntIDContract() *types.Transaction {
 	return tx
 }
 
-func newGoverningInit() *types.Transaction {
+type prefundAccount struct {
+	addr common.Address
+	ont  uint64
+	ong  uint64
+}
+
+//parsePrefund returns accounts funded in genesis block, only solo chain supports prefund
+func parsePrefund(genesisConfig *config.GenesisConfig) ([]*prefundAccount, error) {
+	if genesisConfig.ConsensusType != config.CONSENSUS_TYPE_SOLO || genesisConfig.SOLO == nil {
+		return nil, nil
+	}
+	var prefund []*prefundAccount
+	var ontSum, ongSum uint64
+	overflow := false
+	for _, acc := range genesisConfig.SOLO.Prefund {
+		addr, err := common.AddressFromBase58(acc.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %s: %s", acc.Address, err)
+		}
+		ontSum, overflow = common.SafeAdd(ontSum, acc.Ont)
+		if overflow || ontSum > constants.ONT_TOTAL_SUPPLY {
+			return nil, fmt.Errorf("prefund ont exceeds total supply")
+		}
+		//prefund ong is minted on top of the total supply
+		ongSum, overflow = common.SafeAdd(ongSum, acc.Ong)
+		if !overflow {
+			_, overflow = common.SafeAdd(ongSum, constants.ONG_TOTAL_SUPPLY)
+		}
+		if overflow {
+			return nil, fmt.Errorf("prefund ong overflow")
+		}
+		prefund = append(prefund, &prefundAccount{addr: addr, ont: acc.Ont, ong: acc.Ong})
+	}
+	return prefund, nil
+}
+
+func newGoverningInit(prefund []*prefundAccount) *types.Transaction {
 	bookkeepers, _ := config.DefConfig.GetBookkeepers()
 
 	var addr common.Address
@@ -215,10 +255,23 @@ func newGoverningInit() *types.Transaction {
 		addr = temp
 	}
 
-	distribute := []struct {
+	type part struct {
 		addr  common.Address
 		value uint64
-	}{{addr, constants.ONT_TOTAL_SUPPLY}}
+	}
+	//the rest after prefund goes to bookkeepers
+	rest := uint64(constants.ONT_TOTAL_SUPPLY)
+	var distribute []part
+	for _, acc := range prefund {
+		if acc.ont == 0 {
+			continue
+		}
+		distribute = append(distribute, part{acc.addr, acc.ont})
+		rest -= acc.ont
+	}
+	if rest > 0 {
+		distribute = append(distribute, part{addr, rest})
+	}
 
 	args := common.NewZeroCopySink(nil)
 	nutils.EncodeVarUint(args, uint64(len(distribute)))
@@ -235,8 +288,25 @@ func newGoverningInit() *types.Transaction {
 	return tx
 }
 
-func newUtilityInit() *types.Transaction {
-	mutable := utils.BuildNativeTransaction(nutils.OngContractAddress, ont.INIT_NAME, []byte{})
+func newUtilityInit(prefund []*prefundAccount) *types.Transaction {
+	//prefund ong is minted, the total supply stays in ont contract to be unbound
+	args := common.NewZeroCopySink(nil)
+	var num uint64
+	for _, acc := range prefund {
+		if acc.ong > 0 {
+			num++
+		}
+	}
+	if num > 0 {
+		nutils.EncodeVarUint(args, num)
+		for _, acc := range prefund {
+			if acc.ong > 0 {
+				nutils.EncodeAddress(args, acc.addr)
+				nutils.EncodeVarUint(args, acc.ong)
+			}
+		}
+	}
+	mutable := utils.BuildNativeTransaction(nutils.OngContractAddress, ont.INIT_NAME, args.Bytes())
 	tx, err := mutable.IntoImmutable()
 	if err != nil {
 		panic("construct genesis utility token transaction error ")
@@ -21,6 +21,7 @@ import (
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/common/log"
 	"github.com/stretchr/testify/assert"
 	"os"
@@ -48,3 +49,31 @@ func TestNewParamDeployAndInit(t *testing.T) {
 	assert.NotNil(t, deployTx)
 	assert.NotNil(t, initTx)
 }
+
+func TestPrefund(t *testing.T) {
+	_, pub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	addr := common.Address{1}
+	conf := &config.GenesisConfig{
+		ConsensusType: config.CONSENSUS_TYPE_SOLO,
+		SOLO: &config.SOLOConfig{
+			Prefund: []*config.PrefundAccount{{Address: addr.ToBase58(), Ont: 1000, Ong: 1000000000}},
+		},
+	}
+	prefund, err := parsePrefund(conf)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(prefund))
+	assert.Equal(t, addr, prefund[0].addr)
+	block, err := BuildGenesisBlock([]keypair.PublicKey{pub}, conf)
+	assert.Nil(t, err)
+	assert.NotNil(t, block)
+
+	conf.SOLO.Prefund[0].Ont = constants.ONT_TOTAL_SUPPLY + 1
+	_, err = BuildGenesisBlock([]keypair.PublicKey{pub}, conf)
+	assert.NotNil(t, err)
+
+	//prefund only works for solo chain
+	conf.ConsensusType = config.CONSENSUS_TYPE_VBFT
+	prefund, err = parsePrefund(conf)
+	assert.Nil(t, err)
+	assert.Nil(t, prefund)
+}
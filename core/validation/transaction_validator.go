@@ -48,6 +48,16 @@ func VerifyTransaction(tx *types.Transaction) ontErrors.ErrCode {
 	return ontErrors.ErrNoError
 }
 
+// VerifyTransactionSignature only verifys the signatures of transaction, used
+// for the transactions of block accepted by consensus already
+func VerifyTransactionSignature(tx *types.Transaction) ontErrors.ErrCode {
+	if err := checkTransactionSignatures(tx); err != nil {
+		log.Info("transaction verify error:", err)
+		return ontErrors.ErrVerifySignature
+	}
+	return ontErrors.ErrNoError
+}
+
 func VerifyTransactionWithLedger(tx *types.Transaction, ledger *ledger.Ledger) ontErrors.ErrCode {
 	//TODO: replay check
 	return ontErrors.ErrNoError
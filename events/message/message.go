@@ -29,6 +29,9 @@ const (
 	TOPIC_NODE_DISCONNECT           = "noddis"
 	TOPIC_NODE_CONSENSUS_DISCONNECT = "nodcnsdis"
 	TOPIC_SMART_CODE_EVENT          = "scevt"
+	TOPIC_TXPOOL_PENDING_TX         = "txpoolpend"
+	TOPIC_TXPOOL_DROP_TX            = "txpooldrop"
+	TOPIC_CONSENSUS_EVENT           = "cnsevt"
 )
 
 type SaveBlockCompleteMsg struct {
@@ -43,6 +46,25 @@ type SmartCodeEventMsg struct {
 	Event *types.SmartCodeEvent
 }
 
+type TxPoolPendingTxMsg struct {
+	Tx *types.Transaction
+}
+
+type TxPoolDropTxMsg struct {
+	Tx     *types.Transaction
+	Reason string
+}
+
 type BlockConsensusComplete struct {
 	Block *types.Block
 }
+
+type ConsensusEventMsg struct {
+	Event     string
+	BlockNum  uint32
+	View      uint32
+	Peer      uint32
+	Latency   int64 // milliseconds since round start
+	Detail    string
+	Timestamp int64
+}
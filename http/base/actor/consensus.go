@@ -19,7 +19,12 @@
 package actor
 
 import (
+	"errors"
+	"time"
+
 	"github.com/ontio/ontology-eventbus/actor"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
 	cactor "github.com/ontio/ontology/consensus/actor"
 )
 
@@ -44,3 +49,59 @@ func ConsensusSrvHalt() error {
 	}
 	return nil
 }
+
+//GetConsensusStatus query running state of consensus from consensus actor
+func GetConsensusStatus() (*cactor.ConsensusStatus, error) {
+	if consensusSrvPid == nil {
+		return nil, errors.New("consensus service not started")
+	}
+	future := consensusSrvPid.RequestFuture(&cactor.GetConsensusStatusReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	rsp, ok := result.(*cactor.GetConsensusStatusRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return rsp.Status, nil
+}
+
+//MineBlock asks dev mode solo consensus to generate count blocks at once
+func MineBlock(count uint32) ([]common.Uint256, error) {
+	if consensusSrvPid == nil {
+		return nil, errors.New("consensus service not started")
+	}
+	//blocks are generated one by one, wait longer for many blocks
+	timeout := time.Duration(REQ_TIMEOUT+count/10) * time.Second
+	future := consensusSrvPid.RequestFuture(&cactor.MineBlockReq{Count: count}, timeout)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	rsp, ok := result.(*cactor.MineBlockRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return rsp.Hashes, rsp.Error
+}
+
+//SetBlockTimestamp sets timestamp of next block generated by dev mode solo consensus
+func SetBlockTimestamp(timestamp uint32) error {
+	if consensusSrvPid == nil {
+		return errors.New("consensus service not started")
+	}
+	future := consensusSrvPid.RequestFuture(&cactor.SetBlockTimestampReq{Timestamp: timestamp}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return err
+	}
+	rsp, ok := result.(*cactor.SetBlockTimestampRsp)
+	if !ok {
+		return errors.New("fail")
+	}
+	return rsp.Error
+}
@@ -27,6 +27,9 @@ import (
 type EventActor struct {
 	blockPersistCompleted func(v interface{})
 	smartCodeEvt          func(v interface{})
+	txPoolPendingTx       func(v interface{})
+	txPoolDropTx          func(v interface{})
+	consensusEvt          func(v interface{})
 }
 
 //receive from subscribed actor
@@ -36,17 +39,29 @@ func (t *EventActor) Receive(c actor.Context) {
 		t.blockPersistCompleted(*msg.Block)
 	case *message.SmartCodeEventMsg:
 		t.smartCodeEvt(*msg.Event)
+	case *message.TxPoolPendingTxMsg:
+		t.txPoolPendingTx(*msg)
+	case *message.TxPoolDropTxMsg:
+		t.txPoolDropTx(*msg)
+	case *message.ConsensusEventMsg:
+		t.consensusEvt(*msg)
 	default:
 	}
 }
 
-//Subscribe save block complete and smartcontract Event
+//Subscribe save block complete, smartcontract, tx pool and consensus Event
 func SubscribeEvent(topic string, handler func(v interface{})) {
 	var props = actor.FromProducer(func() actor.Actor {
 		if topic == message.TOPIC_SAVE_BLOCK_COMPLETE {
 			return &EventActor{blockPersistCompleted: handler}
 		} else if topic == message.TOPIC_SMART_CODE_EVENT {
 			return &EventActor{smartCodeEvt: handler}
+		} else if topic == message.TOPIC_TXPOOL_PENDING_TX {
+			return &EventActor{txPoolPendingTx: handler}
+		} else if topic == message.TOPIC_TXPOOL_DROP_TX {
+			return &EventActor{txPoolDropTx: handler}
+		} else if topic == message.TOPIC_CONSENSUS_EVENT {
+			return &EventActor{consensusEvt: handler}
 		} else {
 			return &EventActor{}
 		}
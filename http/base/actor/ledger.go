@@ -20,6 +20,7 @@ package actor
 
 import (
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/types"
@@ -69,6 +70,10 @@ func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
 
 //GetStorageItem from ledger
 func GetStorageItem(address common.Address, key []byte) ([]byte, error) {
+	if config.DefConfig.P2PNode.LightClient {
+		value, _, err := GetStorageFromPeers(address, key)
+		return value, err
+	}
 	return ledger.DefLedger.GetStorageItem(address, key)
 }
 
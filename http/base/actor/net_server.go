@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/ontio/ontology-eventbus/actor"
+	ocommon "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	ac "github.com/ontio/ontology/p2pserver/actor/server"
 	"github.com/ontio/ontology/p2pserver/common"
@@ -222,3 +223,84 @@ func GetNodeType() (uint64, error) {
 	}
 	return r.NodeType, nil
 }
+
+//GetBannedPeers from netSever actor
+func GetBannedPeers() ([]common.BannedPeer, error) {
+	if netServerPid == nil {
+		return []common.BannedPeer{}, nil
+	}
+	future := netServerPid.RequestFuture(&ac.GetBannedPeersReq{}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, err
+	}
+	r, ok := result.(*ac.GetBannedPeersRsp)
+	if !ok {
+		return nil, errors.New("fail")
+	}
+	return r.Peers, nil
+}
+
+//BanPeer ban peer id or ip by netSever actor
+func BanPeer(id uint64, ip string, duration time.Duration) error {
+	if netServerPid == nil {
+		return nil
+	}
+	req := &ac.BanPeerReq{
+		ID:       id,
+		IP:       ip,
+		Duration: duration,
+	}
+	future := netServerPid.RequestFuture(req, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return err
+	}
+	if _, ok := result.(*ac.BanPeerRsp); !ok {
+		return errors.New("fail")
+	}
+	return nil
+}
+
+//UnbanPeer unban peer id or ip by netSever actor
+func UnbanPeer(id uint64, ip string) (bool, error) {
+	if netServerPid == nil {
+		return false, nil
+	}
+	future := netServerPid.RequestFuture(&ac.UnbanPeerReq{ID: id, IP: ip}, REQ_TIMEOUT*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return false, err
+	}
+	r, ok := result.(*ac.UnbanPeerRsp)
+	if !ok {
+		return false, errors.New("fail")
+	}
+	return r.Ok, nil
+}
+
+//GetStorageFromPeers query the contract storage and the height it was read at
+//from full peers by netSever actor in light client mode
+func GetStorageFromPeers(contract ocommon.Address, key []byte) ([]byte, uint32, error) {
+	if netServerPid == nil {
+		return nil, 0, errors.New("net server is not started")
+	}
+	req := &ac.GetStorageReq{
+		Contract: contract,
+		Key:      key,
+	}
+	future := netServerPid.RequestFuture(req, (REQ_TIMEOUT+common.LIGHT_QUERY_TIMEOUT)*time.Second)
+	result, err := future.Result()
+	if err != nil {
+		log.Errorf(ERR_ACTOR_COMM, err)
+		return nil, 0, err
+	}
+	r, ok := result.(*ac.GetStorageRsp)
+	if !ok {
+		return nil, 0, errors.New("fail")
+	}
+	return r.Value, r.Height, r.Error
+}
@@ -22,19 +22,23 @@ package common
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/ontio/ontology-crypto/keypair"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/payload"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
 	cutils "github.com/ontio/ontology/core/utils"
 	ontErrors "github.com/ontio/ontology/errors"
 	bactor "github.com/ontio/ontology/http/base/actor"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	cstate "github.com/ontio/ontology/smartcontract/states"
 	"github.com/ontio/ontology/vm/neovm"
@@ -120,6 +124,11 @@ type Transactions struct {
 	Height     uint32
 }
 
+type DroppedTxInfo struct {
+	TxHash string
+	Reason string
+}
+
 type BlockHead struct {
 	Version          uint32
 	PrevBlockHash    string
@@ -189,6 +198,46 @@ func GetExecuteNotify(obj *event.ExecuteNotify) (map[string]bool, ExecuteNotify)
 	return contractAddrs, ExecuteNotify{txhash, obj.State, obj.GasConsumed, evts}
 }
 
+//GetTxContractAddrs returns the addresses of the contracts a transaction invokes directly,
+//contracts called indirectly during execution can not be known before the tx is executed
+func GetTxContractAddrs(txn *types.Transaction) map[string]bool {
+	var contractAddrs = make(map[string]bool)
+	invokeCode, ok := txn.Payload.(*payload.InvokeCode)
+	if !ok {
+		return contractAddrs
+	}
+	code := invokeCode.Code
+	switch txn.TxType {
+	case types.InvokeWasm:
+		param := &cstate.WasmContractParam{}
+		if err := param.Deserialization(common.NewZeroCopySource(code)); err == nil {
+			contractAddrs[param.Address.ToHexString()] = true
+		}
+	case types.InvokeNeo:
+		//native invoke: PUSHBYTES20 address, PUSH version, SYSCALL "Ontology.Native.Invoke"
+		nativeTail := append([]byte{byte(neovm.SYSCALL), byte(len(cutils.NATIVE_INVOKE_NAME))},
+			[]byte(cutils.NATIVE_INVOKE_NAME)...)
+		if bytes.HasSuffix(code, nativeTail) {
+			rest := code[:len(code)-len(nativeTail)]
+			if len(rest) > common.ADDR_LEN+1 && rest[len(rest)-common.ADDR_LEN-2] == common.ADDR_LEN {
+				addr, err := common.AddressParseFromBytes(rest[len(rest)-common.ADDR_LEN-1 : len(rest)-1])
+				if err == nil {
+					contractAddrs[addr.ToHexString()] = true
+				}
+			}
+			return contractAddrs
+		}
+		//neovm invoke: APPCALL address
+		if len(code) > common.ADDR_LEN && code[len(code)-common.ADDR_LEN-1] == byte(neovm.APPCALL) {
+			addr, err := common.AddressParseFromBytes(code[len(code)-common.ADDR_LEN:])
+			if err == nil {
+				contractAddrs[addr.ToHexString()] = true
+			}
+		}
+	}
+	return contractAddrs
+}
+
 func ConvertPreExecuteResult(obj *cstate.PreExecResult) PreExecuteResult {
 	evts := []NotifyEventInfo{}
 	for _, v := range obj.Notify {
@@ -280,6 +329,9 @@ func GetBlockInfo(block *types.Block) BlockInfo {
 }
 
 func GetBalance(address common.Address) (*BalanceOfRsp, error) {
+	if config.DefConfig.P2PNode.LightClient {
+		return getBalanceFromPeers(address)
+	}
 	balances, height, err := GetContractBalance(0, []common.Address{utils.OntContractAddress, utils.OngContractAddress}, address, true)
 	if err != nil {
 		return nil, fmt.Errorf("get ont balance error:%s", err)
@@ -291,6 +343,37 @@ func GetBalance(address common.Address) (*BalanceOfRsp, error) {
 	}, nil
 }
 
+//getBalanceFromPeers read the balance storage of native token from full peers
+//in light client mode, the contracts can not be executed without state
+func getBalanceFromPeers(address common.Address) (*BalanceOfRsp, error) {
+	balances := make([]uint64, 0, 2)
+	var height uint32
+	for _, contract := range []common.Address{utils.OntContractAddress, utils.OngContractAddress} {
+		value, h, err := bactor.GetStorageFromPeers(contract, address[:])
+		if err != nil && err != scom.ErrNotFound {
+			return nil, fmt.Errorf("get balance from peers error:%s", err)
+		}
+		var balance uint64
+		if len(value) != 0 {
+			source := common.NewZeroCopySource(value)
+			v, eof := source.NextUint64()
+			if eof {
+				return nil, io.ErrUnexpectedEOF
+			}
+			balance = v
+		}
+		balances = append(balances, balance)
+		if h > height {
+			height = h
+		}
+	}
+	return &BalanceOfRsp{
+		Ont:    fmt.Sprintf("%d", balances[0]),
+		Ong:    fmt.Sprintf("%d", balances[1]),
+		Height: fmt.Sprintf("%d", height),
+	}, nil
+}
+
 func GetGrantOng(addr common.Address) (string, error) {
 	key := append([]byte(ont.UNBOUND_TIME_OFFSET), addr[:]...)
 	value, err := ledger.DefLedger.GetStorageItem(utils.OntContractAddress, key)
@@ -363,6 +446,85 @@ func GetContractBalance(cVersion byte, contractAddres []common.Address, accAddr
 	return balances, height, nil
 }
 
+//GetGovernanceInfo pre-execute a read-only method of governance contract, which returns json
+func GetGovernanceInfo(method string, param interface{}) (json.RawMessage, error) {
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.GovernanceContractAddress, 0, method, []interface{}{param})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if result.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	data, err := hex.DecodeString(result.Result.(string))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	return json.RawMessage(data), nil
+}
+
+//GetDIDDocument resolve ONT ID to W3C DID document, with the revocation state of keys.
+//Return nil document if the ONT ID is not registered
+func GetDIDDocument(ontId string) (*ontid.DIDDocument, error) {
+	ddo, err := preExecuteOntId("getDDO", []byte(ontId))
+	if err != nil {
+		return nil, fmt.Errorf("get DDO error:%s", err)
+	}
+	if len(ddo) == 0 {
+		return nil, nil
+	}
+	doc, err := ontid.ParseDDO(ontId, ddo)
+	if err != nil {
+		return nil, err
+	}
+	type keyStateParam struct {
+		Id    []byte
+		Index uint32
+	}
+	for index := uint32(1); ; index++ {
+		state, err := preExecuteOntId("getKeyState", &keyStateParam{[]byte(ontId), index})
+		if err != nil {
+			return nil, fmt.Errorf("get key state error:%s", err)
+		}
+		switch string(state) {
+		case "not exist":
+			return doc, nil
+		case "revoked":
+			doc.AddRevokedKey(index)
+		}
+	}
+}
+
+func preExecuteOntId(method string, param interface{}) ([]byte, error) {
+	mutable, err := NewNativeInvokeTransaction(0, 0, utils.OntIDContractAddress, 0, method, []interface{}{param})
+	if err != nil {
+		return nil, fmt.Errorf("NewNativeInvokeTransaction error:%s", err)
+	}
+	tx, err := mutable.IntoImmutable()
+	if err != nil {
+		return nil, err
+	}
+	result, err := bactor.PreExecuteContract(tx)
+	if err != nil {
+		return nil, fmt.Errorf("PrepareInvokeContract error:%s", err)
+	}
+	if result.State == 0 {
+		return nil, fmt.Errorf("prepare invoke failed")
+	}
+	data, err := hex.DecodeString(result.Result.(string))
+	if err != nil {
+		return nil, fmt.Errorf("hex.DecodeString error:%s", err)
+	}
+	return data, nil
+}
+
 func GetContractAllowance(cVersion byte, contractAddr, fromAddr, toAddr common.Address) (uint64, error) {
 	type allowanceStruct struct {
 		From common.Address
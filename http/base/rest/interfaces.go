@@ -19,6 +19,7 @@
 package rest
 
 import (
+	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
@@ -30,6 +31,7 @@ import (
 	berr "github.com/ontio/ontology/http/base/error"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 	"strconv"
+	"strings"
 )
 
 const TLS_PORT int = 443
@@ -554,6 +556,34 @@ func GetGrantOng(cmd map[string]interface{}) map[string]interface{} {
 	return resp
 }
 
+//resolve ONT ID to W3C DID document
+func GetDIDDocument(cmd map[string]interface{}) map[string]interface{} {
+	resp := ResponsePack(berr.SUCCESS)
+	ontId, ok := cmd["OntId"].(string)
+	if !ok {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	if !strings.HasPrefix(ontId, "did:ont:") {
+		ontId = "did:ont:" + ontId
+	}
+	if !account.VerifyID(ontId) {
+		return ResponsePack(berr.INVALID_PARAMS)
+	}
+	doc, err := bcomn.GetDIDDocument(ontId)
+	if err != nil {
+		resp = ResponsePack(berr.INTERNAL_ERROR)
+		resp["Result"] = err.Error()
+		return resp
+	}
+	if doc == nil {
+		resp = ResponsePack(berr.INVALID_PARAMS)
+		resp["Result"] = "ONT ID not exist"
+		return resp
+	}
+	resp["Result"] = doc
+	return resp
+}
+
 //get memory pool transaction count
 func GetMemPoolTxCount(cmd map[string]interface{}) map[string]interface{} {
 	resp := ResponsePack(berr.SUCCESS)
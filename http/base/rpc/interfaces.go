@@ -30,7 +30,9 @@ import (
 	bactor "github.com/ontio/ontology/http/base/actor"
 	bcomn "github.com/ontio/ontology/http/base/common"
 	berr "github.com/ontio/ontology/http/base/error"
+	"github.com/ontio/ontology/smartcontract/service/native/governance"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"math"
 )
 
 //get best block hash
@@ -41,8 +43,9 @@ func GetBestBlockHash(params []interface{}) map[string]interface{} {
 
 // get block by height or hash
 // Input JSON string examples for getblock method as following:
-//   {"jsonrpc": "2.0", "method": "getblock", "params": [1], "id": 0}
-//   {"jsonrpc": "2.0", "method": "getblock", "params": ["aabbcc.."], "id": 0}
+//
+//	{"jsonrpc": "2.0", "method": "getblock", "params": [1], "id": 0}
+//	{"jsonrpc": "2.0", "method": "getblock", "params": ["aabbcc.."], "id": 0}
 func GetBlock(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -93,7 +96,8 @@ func GetBlockCount(params []interface{}) map[string]interface{} {
 
 //get block hash
 // A JSON example for getblockhash method as following:
-//   {"jsonrpc": "2.0", "method": "getblockhash", "params": [1], "id": 0}
+//
+//	{"jsonrpc": "2.0", "method": "getblockhash", "params": [1], "id": 0}
 func GetBlockHash(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -182,7 +186,8 @@ func GetMemPoolTxState(params []interface{}) map[string]interface{} {
 
 // get raw transaction in raw or json
 // A JSON example for getrawtransaction method as following:
-//   {"jsonrpc": "2.0", "method": "getrawtransaction", "params": ["transactioin hash in hex"], "id": 0}
+//
+//	{"jsonrpc": "2.0", "method": "getrawtransaction", "params": ["transactioin hash in hex"], "id": 0}
 func GetRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -223,8 +228,9 @@ func GetRawTransaction(params []interface{}) map[string]interface{} {
 	return responseSuccess(common.ToHexString(common.SerializeToBytes(tx)))
 }
 
-//get storage from contract
-//   {"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key"], "id": 0}
+// get storage from contract
+//
+//	{"jsonrpc": "2.0", "method": "getstorage", "params": ["code hash", "key"], "id": 0}
 func GetStorage(params []interface{}) map[string]interface{} {
 	if len(params) < 2 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -267,7 +273,8 @@ func GetStorage(params []interface{}) map[string]interface{} {
 
 //send raw transaction
 // A JSON example for sendrawtransaction method as following:
-//   {"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
+//
+//	{"jsonrpc": "2.0", "method": "sendrawtransaction", "params": ["raw transactioin in hex"], "id": 0}
 func SendRawTransaction(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, nil)
@@ -594,3 +601,140 @@ func GetGrantOng(params []interface{}) map[string]interface{} {
 	}
 	return responseSuccess(rsp)
 }
+
+//get peers of current governance view
+func GetPeerPoolList(params []interface{}) map[string]interface{} {
+	rsp, err := bcomn.GetGovernanceInfo(governance.GET_PEER_POOL_LIST, []byte{})
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get authorize info of address for all peers
+func GetAuthorizeInfo(params []interface{}) map[string]interface{} {
+	return getGovernanceInfoByAddress(governance.GET_AUTHORIZE_INFO_BY_ADDRESS, params)
+}
+
+//get ong motivation of address
+func GetSplitFeeAddress(params []interface{}) map[string]interface{} {
+	return getGovernanceInfoByAddress(governance.GET_SPLIT_FEE_ADDRESS, params)
+}
+
+//get total stake of address
+func GetTotalStake(params []interface{}) map[string]interface{} {
+	return getGovernanceInfoByAddress(governance.GET_TOTAL_STAKE, params)
+}
+
+func getGovernanceInfoByAddress(method string, params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	addr, err := common.AddressFromBase58(str)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetGovernanceInfo(method, addr[:])
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get current governance view
+func GetGovernanceView(params []interface{}) map[string]interface{} {
+	rsp, err := bcomn.GetGovernanceInfo(governance.GET_GOVERNANCE_VIEW, []byte{})
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get attributes of peer by public key
+func GetPeerAttributes(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	peerPubkey, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	if _, err := hex.DecodeString(peerPubkey); err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetGovernanceInfo(governance.GET_PEER_ATTRIBUTES, peerPubkey)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get governance proposal by id
+func GetProposal(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ok := params[0].(float64)
+	if !ok || id < 0 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	rsp, err := bcomn.GetGovernanceInfo(governance.GET_PROPOSAL, uint64(id))
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+//get governance proposals in voting
+func GetProposalList(params []interface{}) map[string]interface{} {
+	rsp, err := bcomn.GetGovernanceInfo(governance.GET_PROPOSAL_LIST, []byte{})
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, "")
+	}
+	return responseSuccess(rsp)
+}
+
+// estimate staking reward of authorizing pos to peer
+//
+//	{"jsonrpc": "2.0", "method": "estimatestakingreward", "params": ["peerPubkey", pos, rounds, feePerRound, roundSeconds], "id": 0}
+//
+// feePerRound and roundSeconds are optional
+func EstimateStakingReward(params []interface{}) map[string]interface{} {
+	if len(params) < 3 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	peerPubkey, ok := params[0].(string)
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	if _, err := hex.DecodeString(peerPubkey); err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	nums := make([]uint64, 4)
+	for i := 1; i < len(params) && i <= len(nums); i++ {
+		num, ok := params[i].(float64)
+		if !ok || num < 0 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		nums[i-1] = uint64(num)
+	}
+	if nums[1] > math.MaxUint32 || nums[3] > math.MaxUint32 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	param := governance.EstimateStakingRewardParam{
+		PeerPubkey:   peerPubkey,
+		Pos:          nums[0],
+		Rounds:       uint32(nums[1]),
+		FeePerRound:  nums[2],
+		RoundSeconds: uint32(nums[3]),
+	}
+	rsp, err := bcomn.GetGovernanceInfo(governance.ESTIMATE_STAKING_REWARD, param)
+	if err != nil {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	return responseSuccess(rsp)
+}
@@ -19,9 +19,14 @@
 package rpc
 
 import (
+	"math"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	bactor "github.com/ontio/ontology/http/base/actor"
 	"github.com/ontio/ontology/http/base/common"
@@ -107,6 +112,62 @@ func StopConsensus(params []interface{}) map[string]interface{} {
 	return responsePack(berr.SUCCESS, true)
 }
 
+func GetConsensusStatus(params []interface{}) map[string]interface{} {
+	status, err := bactor.GetConsensusStatus()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	return responseSuccess(status)
+}
+
+//generate blocks at once in dev mode, params: [count], count is 1 by default
+func MineBlock(params []interface{}) map[string]interface{} {
+	count := uint32(1)
+	if len(params) > 0 {
+		switch params[0].(type) {
+		case float64:
+			n := params[0].(float64)
+			if n < 1 || n > math.MaxUint32 {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			count = uint32(n)
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	hashes, err := bactor.MineBlock(count)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	result := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		result = append(result, hash.ToHexString())
+	}
+	return responseSuccess(result)
+}
+
+//set timestamp of next block in dev mode, params: [timestamp]
+func SetBlockTimestamp(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	var timestamp uint32
+	switch params[0].(type) {
+	case float64:
+		ts := params[0].(float64)
+		if ts < 0 || ts > math.MaxUint32 {
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+		timestamp = uint32(ts)
+	default:
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	if err := bactor.SetBlockTimestamp(timestamp); err != nil {
+		return responsePack(berr.INTERNAL_ERROR, err.Error())
+	}
+	return responsePack(berr.SUCCESS, true)
+}
+
 func SetDebugInfo(params []interface{}) map[string]interface{} {
 	if len(params) < 1 {
 		return responsePack(berr.INVALID_PARAMS, "")
@@ -122,3 +183,76 @@ func SetDebugInfo(params []interface{}) map[string]interface{} {
 	}
 	return responsePack(berr.SUCCESS, true)
 }
+
+func GetBannedPeers(params []interface{}) map[string]interface{} {
+	peers, err := bactor.GetBannedPeers()
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	return responseSuccess(peers)
+}
+
+//parseBanTarget parse the peer id, ip or node address(ip:port) to ban
+func parseBanTarget(param interface{}) (uint64, string, bool) {
+	str, ok := param.(string)
+	if !ok {
+		return 0, "", false
+	}
+	if ip := net.ParseIP(str); ip != nil {
+		return 0, ip.String(), true
+	}
+	if host, port, err := net.SplitHostPort(str); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			if _, err := strconv.ParseUint(port, 10, 16); err == nil {
+				return 0, net.JoinHostPort(ip.String(), port), true
+			}
+		}
+	}
+	id, err := strconv.ParseUint(str, 10, 64)
+	if err != nil || id == 0 {
+		return 0, "", false
+	}
+	return id, "", true
+}
+
+func BanPeer(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ip, ok := parseBanTarget(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	duration := time.Duration(config.DefConfig.P2PNode.BanDuration) * time.Second
+	if len(params) > 1 {
+		switch params[1].(type) {
+		case float64:
+			secs := params[1].(float64)
+			if secs <= 0 {
+				return responsePack(berr.INVALID_PARAMS, "")
+			}
+			duration = time.Duration(secs) * time.Second
+		default:
+			return responsePack(berr.INVALID_PARAMS, "")
+		}
+	}
+	if err := bactor.BanPeer(id, ip, duration); err != nil {
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	return responsePack(berr.SUCCESS, true)
+}
+
+func UnbanPeer(params []interface{}) map[string]interface{} {
+	if len(params) < 1 {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	id, ip, ok := parseBanTarget(params[0])
+	if !ok {
+		return responsePack(berr.INVALID_PARAMS, "")
+	}
+	unbanned, err := bactor.UnbanPeer(id, ip)
+	if err != nil {
+		return responsePack(berr.INTERNAL_ERROR, false)
+	}
+	return responsePack(berr.SUCCESS, unbanned)
+}
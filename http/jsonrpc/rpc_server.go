@@ -61,6 +61,16 @@ func StartRPCServer() error {
 	rpc.HandleFunc("getunboundong", rpc.GetUnboundOng)
 	rpc.HandleFunc("getgrantong", rpc.GetGrantOng)
 
+	rpc.HandleFunc("getpeerpoollist", rpc.GetPeerPoolList)
+	rpc.HandleFunc("getauthorizeinfo", rpc.GetAuthorizeInfo)
+	rpc.HandleFunc("getsplitfeeaddress", rpc.GetSplitFeeAddress)
+	rpc.HandleFunc("gettotalstake", rpc.GetTotalStake)
+	rpc.HandleFunc("getgovernanceview", rpc.GetGovernanceView)
+	rpc.HandleFunc("getpeerattributes", rpc.GetPeerAttributes)
+	rpc.HandleFunc("getproposal", rpc.GetProposal)
+	rpc.HandleFunc("getproposallist", rpc.GetProposalList)
+	rpc.HandleFunc("estimatestakingreward", rpc.EstimateStakingReward)
+
 	err := http.ListenAndServe(":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpJsonPort)), nil)
 	if err != nil {
 		return fmt.Errorf("ListenAndServe error:%s", err)
@@ -42,7 +42,13 @@ func StartLocalServer() error {
 	rpc.HandleFunc("getnodestate", rpc.GetNodeState)
 	rpc.HandleFunc("startconsensus", rpc.StartConsensus)
 	rpc.HandleFunc("stopconsensus", rpc.StopConsensus)
+	rpc.HandleFunc("getconsensusstatus", rpc.GetConsensusStatus)
+	rpc.HandleFunc("mineblock", rpc.MineBlock)
+	rpc.HandleFunc("setblocktimestamp", rpc.SetBlockTimestamp)
 	rpc.HandleFunc("setdebuginfo", rpc.SetDebugInfo)
+	rpc.HandleFunc("getbannedpeers", rpc.GetBannedPeers)
+	rpc.HandleFunc("banpeer", rpc.BanPeer)
+	rpc.HandleFunc("unbanpeer", rpc.UnbanPeer)
 
 	// TODO: only listen to local host
 	err := http.ListenAndServe(LOCAL_HOST+":"+strconv.Itoa(int(cfg.DefConfig.Rpc.HttpLocalPort)), nil)
@@ -28,6 +28,11 @@ import (
 
 type paramsMap map[string]string
 
+//patterns of route params which are not a single word, other params match (\w+)
+var paramPatterns = map[string]string{
+	"ontid": `([\w:]+)`, //ONT ID in the form of did:ont:xxx
+}
+
 //http router
 type Route struct {
 	Method  string
@@ -74,7 +79,11 @@ func (this *Router) add(method string, path string, handler http.HandlerFunc) {
 		if matches != nil {
 			for _, v := range matches {
 				route.Params = append(route.Params, v[1])
-				path = strings.Replace(path, v[0], `(\w+)`, 1)
+				pattern, ok := paramPatterns[v[1]]
+				if !ok {
+					pattern = `(\w+)`
+				}
+				path = strings.Replace(path, v[0], pattern, 1)
 			}
 		}
 	}
@@ -38,6 +38,9 @@ var ws *websocket.WsServer
 func StartServer() {
 	bactor.SubscribeEvent(message.TOPIC_SAVE_BLOCK_COMPLETE, sendBlock2WSclient)
 	bactor.SubscribeEvent(message.TOPIC_SMART_CODE_EVENT, pushSmartCodeEvent)
+	bactor.SubscribeEvent(message.TOPIC_TXPOOL_PENDING_TX, pushPendingTx)
+	bactor.SubscribeEvent(message.TOPIC_TXPOOL_DROP_TX, pushDroppedTx)
+	bactor.SubscribeEvent(message.TOPIC_CONSENSUS_EVENT, pushConsensusEvent)
 	go func() {
 		ws = websocket.InitWsServer()
 		ws.Start()
@@ -126,3 +129,58 @@ func pushBlockTransactions(v interface{}) {
 		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_TXHASHS, resp)
 	}
 }
+
+func pushPendingTx(v interface{}) {
+	if ws == nil {
+		return
+	}
+	msg, ok := v.(message.TxPoolPendingTxMsg)
+	if !ok {
+		log.Errorf("[pushPendingTx] TxPoolPendingTxMsg err")
+		return
+	}
+	go func() {
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Action"] = "sendpendingtx"
+		resp["Result"] = bcomn.TransArryByteToHexString(msg.Tx)
+		ws.BroadcastToSubscribers(bcomn.GetTxContractAddrs(msg.Tx), websocket.WSTOPIC_PENDING_TX, resp)
+	}()
+}
+
+func pushDroppedTx(v interface{}) {
+	if ws == nil {
+		return
+	}
+	msg, ok := v.(message.TxPoolDropTxMsg)
+	if !ok {
+		log.Errorf("[pushDroppedTx] TxPoolDropTxMsg err")
+		return
+	}
+	go func() {
+		txHash := msg.Tx.Hash()
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Action"] = "senddroppedtx"
+		resp["Result"] = bcomn.DroppedTxInfo{
+			TxHash: txHash.ToHexString(),
+			Reason: msg.Reason,
+		}
+		ws.BroadcastToSubscribers(bcomn.GetTxContractAddrs(msg.Tx), websocket.WSTOPIC_PENDING_TX, resp)
+	}()
+}
+
+func pushConsensusEvent(v interface{}) {
+	if ws == nil {
+		return
+	}
+	msg, ok := v.(message.ConsensusEventMsg)
+	if !ok {
+		log.Errorf("[pushConsensusEvent] ConsensusEventMsg err")
+		return
+	}
+	go func() {
+		resp := rest.ResponsePack(Err.SUCCESS)
+		resp["Action"] = "sendconsensusevent"
+		resp["Result"] = msg
+		ws.BroadcastToSubscribers(nil, websocket.WSTOPIC_CONSENSUS, resp)
+	}()
+}
@@ -43,6 +43,8 @@ const (
 	WSTOPIC_JSON_BLOCK = 2
 	WSTOPIC_RAW_BLOCK  = 3
 	WSTOPIC_TXHASHS    = 4
+	WSTOPIC_PENDING_TX = 5
+	WSTOPIC_CONSENSUS  = 6
 )
 
 type handler func(map[string]interface{}) map[string]interface{}
@@ -58,6 +60,8 @@ type subscribe struct {
 	SubscribeJsonBlock    bool     `json:"SubscribeJsonBlock"`
 	SubscribeRawBlock     bool     `json:"SubscribeRawBlock"`
 	SubscribeBlockTxHashs bool     `json:"SubscribeBlockTxHashs"`
+	SubscribePendingTx    bool     `json:"SubscribePendingTx"`
+	SubscribeConsensus    bool     `json:"SubscribeConsensus"`
 }
 type WsServer struct {
 	sync.RWMutex
@@ -157,6 +161,12 @@ func (self *WsServer) registryMethod() {
 		if b, ok := cmd["SubscribeBlockTxHashs"].(bool); ok {
 			sub.SubscribeBlockTxHashs = b
 		}
+		if b, ok := cmd["SubscribePendingTx"].(bool); ok {
+			sub.SubscribePendingTx = b
+		}
+		if b, ok := cmd["SubscribeConsensus"].(bool); ok {
+			sub.SubscribeConsensus = b
+		}
 		if ctsf, ok := cmd["ContractsFilter"].([]interface{}); ok {
 			sub.ContractsFilter = []string{}
 			for _, v := range ctsf {
@@ -429,7 +439,10 @@ func (self *WsServer) BroadcastToSubscribers(contractAddrs map[string]bool, sub
 			s.Send(data)
 		} else if sub == WSTOPIC_TXHASHS && v.SubscribeBlockTxHashs {
 			s.Send(data)
-		} else if sub == WSTOPIC_EVENT && v.SubscribeEvent {
+		} else if sub == WSTOPIC_CONSENSUS && v.SubscribeConsensus {
+			s.Send(data)
+		} else if (sub == WSTOPIC_EVENT && v.SubscribeEvent) ||
+			(sub == WSTOPIC_PENDING_TX && v.SubscribePendingTx) {
 			if len(v.ContractsFilter) == 0 {
 				s.Send(data)
 				continue
@@ -40,6 +40,7 @@ import (
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/consensus"
+	"github.com/ontio/ontology/consensus/signer"
 	"github.com/ontio/ontology/core/genesis"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/events"
@@ -95,6 +96,7 @@ func setupAPP() *cli.App {
 		//consensus setting
 		utils.EnableConsensusFlag,
 		utils.MaxTxInBlockFlag,
+		utils.ConsensusSignerFlag,
 		//txpool setting
 		utils.GasPriceFlag,
 		utils.GasLimitFlag,
@@ -110,9 +112,19 @@ func setupAPP() *cli.App {
 		utils.MaxConnInBoundFlag,
 		utils.MaxConnOutBoundFlag,
 		utils.MaxConnInBoundForSingleIPFlag,
+		utils.EnableSecureLinkFlag,
+		utils.RequireSecureLinkFlag,
+		utils.NodeKeyFileFlag,
+		utils.BanDurationFlag,
+		utils.BanWholeIPFlag,
+		utils.LightClientFlag,
 		//test mode setting
 		utils.EnableTestModeFlag,
 		utils.TestModeGenBlockTimeFlag,
+		utils.TestModeMaxBlockTimeFlag,
+		utils.TestModeTxThresholdFlag,
+		utils.DevModeFlag,
+		utils.DevModePrefundFlag,
 		//rpc setting
 		utils.RPCDisabledFlag,
 		utils.RPCPortFlag,
@@ -152,9 +164,9 @@ func startOntology(ctx *cli.Context) {
 		log.Errorf("initConfig error: %s", err)
 		return
 	}
-	acc, err := initAccount(ctx)
+	consensusSigner, err := initSigner(ctx)
 	if err != nil {
-		log.Errorf("initWallet error: %s", err)
+		log.Errorf("initSigner error: %s", err)
 		return
 	}
 	stateHashHeight := config.GetStateHashCheckHeight(cfg.P2PNode.NetworkId)
@@ -173,7 +185,7 @@ func startOntology(ctx *cli.Context) {
 		log.Errorf("initP2PNode error: %s", err)
 		return
 	}
-	_, err = initConsensus(ctx, p2pPid, txpool, acc)
+	_, err = initConsensus(ctx, p2pPid, txpool, consensusSigner)
 	if err != nil {
 		log.Errorf("initConsensus error: %s", err)
 		return
@@ -219,10 +231,36 @@ func initConfig(ctx *cli.Context) (*config.OntologyConfig, error) {
 	return cfg, nil
 }
 
-func initAccount(ctx *cli.Context) (*account.Account, error) {
+func initSigner(ctx *cli.Context) (signer.ConsensusSigner, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
+	var consensusSigner signer.ConsensusSigner
+	if addr := config.DefConfig.Consensus.RemoteSigner; addr != "" {
+		remote, err := signer.NewRemoteSigner(addr)
+		if err != nil {
+			return nil, fmt.Errorf("connect remote signer error: %s", err)
+		}
+		log.Infof("Using remote signer: %s", addr)
+		consensusSigner = remote
+	} else {
+		acc, err := initAccount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		consensusSigner = signer.NewLocalSigner(acc, nil)
+	}
+
+	if config.DefConfig.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
+		curPk := hex.EncodeToString(keypair.SerializePublicKey(consensusSigner.PubKey()))
+		config.DefConfig.Genesis.SOLO.Bookkeepers = []string{curPk}
+	}
+
+	log.Infof("Signer init success")
+	return consensusSigner, nil
+}
+
+func initAccount(ctx *cli.Context) (*account.Account, error) {
 	walletFile := ctx.GlobalString(utils.GetFlagName(utils.WalletFileFlag))
 	if walletFile == "" {
 		return nil, fmt.Errorf("Please config wallet file using --wallet flag")
@@ -236,12 +274,6 @@ func initAccount(ctx *cli.Context) (*account.Account, error) {
 		return nil, fmt.Errorf("get account error: %s", err)
 	}
 	log.Infof("Using account: %s", acc.Address.ToBase58())
-
-	if config.DefConfig.Genesis.ConsensusType == config.CONSENSUS_TYPE_SOLO {
-		curPk := hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))
-		config.DefConfig.Genesis.SOLO.Bookkeepers = []string{curPk}
-	}
-
 	log.Infof("Account init success")
 	return acc, nil
 }
@@ -319,14 +351,14 @@ func initP2PNode(ctx *cli.Context, txpoolSvr *proc.TXPoolServer) (*p2pserver.P2P
 	return p2p, p2pPID, nil
 }
 
-func initConsensus(ctx *cli.Context, p2pPid *actor.PID, txpoolSvr *proc.TXPoolServer, acc *account.Account) (consensus.ConsensusService, error) {
+func initConsensus(ctx *cli.Context, p2pPid *actor.PID, txpoolSvr *proc.TXPoolServer, consensusSigner signer.ConsensusSigner) (consensus.ConsensusService, error) {
 	if !config.DefConfig.Consensus.EnableConsensus {
 		return nil, nil
 	}
 	pool := txpoolSvr.GetPID(tc.TxPoolActor)
 
 	consensusType := strings.ToLower(config.DefConfig.Genesis.ConsensusType)
-	consensusService, err := consensus.NewConsensusService(consensusType, acc, pool, nil, p2pPid)
+	consensusService, err := consensus.NewConsensusService(consensusType, consensusSigner, pool, nil, p2pPid)
 	if err != nil {
 		return nil, fmt.Errorf("NewConsensusService %s error: %s", consensusType, err)
 	}
@@ -364,7 +396,7 @@ func initRpc(ctx *cli.Context) error {
 }
 
 func initLocalRpc(ctx *cli.Context) error {
-	if !ctx.GlobalBool(utils.GetFlagName(utils.RPCLocalEnableFlag)) {
+	if !ctx.GlobalBool(utils.GetFlagName(utils.RPCLocalEnableFlag)) && !config.DefConfig.Genesis.SOLO.DevMode {
 		return nil
 	}
 	var err error
@@ -34,6 +34,11 @@ const txnPoolReqTimeout = p2pcommon.ACTOR_TIMEOUT * time.Second
 
 var txnPoolPid *actor.PID
 
+//txResultCh receive the verify results of txs from network
+var txResultCh = make(chan *tc.TxResult, txResultChanSize)
+
+const txResultChanSize = 1024
+
 func SetTxnPoolPid(txnPid *actor.PID) {
 	txnPoolPid = txnPid
 }
@@ -47,11 +52,16 @@ func AddTransaction(transaction *types.Transaction) {
 	txReq := &tc.TxReq{
 		Tx:         transaction,
 		Sender:     tc.NetSender,
-		TxResultCh: nil,
+		TxResultCh: txResultCh,
 	}
 	txnPoolPid.Tell(txReq)
 }
 
+//TxResultChan return the channel of verify results of txs from network
+func TxResultChan() <-chan *tc.TxResult {
+	return txResultCh
+}
+
 //get txn according to hash
 func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
 	if txnPoolPid == nil {
@@ -64,5 +74,20 @@ func GetTransaction(hash common.Uint256) (*types.Transaction, error) {
 		log.Warnf("[p2p]net_server GetTransaction error: %v\n", err)
 		return nil, err
 	}
-	return result.(tc.GetTxnRsp).Txn, nil
+	return result.(*tc.GetTxnRsp).Txn, nil
+}
+
+//get all txns in txnpool
+func GetTxnList() ([]*types.Transaction, error) {
+	if txnPoolPid == nil {
+		log.Warn("[p2p]net_server tx pool pid is nil")
+		return nil, errors.NewErr("[p2p]net_server tx pool pid is nil")
+	}
+	future := txnPoolPid.RequestFuture(&tc.GetTxnListReq{}, txnPoolReqTimeout)
+	result, err := future.Result()
+	if err != nil {
+		log.Warnf("[p2p]net_server GetTxnList error: %v\n", err)
+		return nil, err
+	}
+	return result.(*tc.GetTxnListRsp).Txs, nil
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/p2pserver"
 	"github.com/ontio/ontology/p2pserver/common"
+	ptypes "github.com/ontio/ontology/p2pserver/message/types"
 )
 
 type P2PActor struct {
@@ -82,12 +83,24 @@ func (this *P2PActor) Receive(ctx actor.Context) {
 		this.handleGetRelayStateReq(ctx, msg)
 	case *GetNodeTypeReq:
 		this.handleGetNodeTypeReq(ctx, msg)
+	case *GetBannedPeersReq:
+		this.handleGetBannedPeersReq(ctx, msg)
+	case *BanPeerReq:
+		this.handleBanPeerReq(ctx, msg)
+	case *UnbanPeerReq:
+		this.handleUnbanPeerReq(ctx, msg)
+	case *GetStorageReq:
+		this.handleGetStorageReq(ctx, msg)
 	case *TransmitConsensusMsgReq:
 		this.handleTransmitConsensusMsgReq(ctx, msg)
+	case *ptypes.StateValueFrom:
+		this.server.OnStateValueReceive(msg.FromID, msg.State)
 	case *common.AppendPeerID:
 		this.server.OnAddNode(msg.ID)
 	case *common.RemovePeerID:
 		this.server.OnDelNode(msg.ID)
+	case *common.AppendAddrs:
+		this.server.OnAddrReceive(msg.FromAddr, msg.Addrs)
 	case *common.AppendHeaders:
 		this.server.OnHeaderReceive(msg.FromID, msg.Headers)
 	case *common.AppendBlock:
@@ -219,6 +232,54 @@ func (this *P2PActor) handleGetNodeTypeReq(ctx actor.Context, req *GetNodeTypeRe
 	}
 }
 
+//banned peers handler
+func (this *P2PActor) handleGetBannedPeersReq(ctx actor.Context, req *GetBannedPeersReq) {
+	peers := this.server.GetNetWork().GetBannedPeers()
+	if ctx.Sender() != nil {
+		resp := &GetBannedPeersRsp{
+			Peers: peers,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
+
+//ban peer handler
+func (this *P2PActor) handleBanPeerReq(ctx actor.Context, req *BanPeerReq) {
+	this.server.GetNetWork().BanPeer(req.ID, req.IP, req.Duration)
+	if ctx.Sender() != nil {
+		ctx.Sender().Request(&BanPeerRsp{}, ctx.Self())
+	}
+}
+
+//unban peer handler
+func (this *P2PActor) handleUnbanPeerReq(ctx actor.Context, req *UnbanPeerReq) {
+	ok := this.server.GetNetWork().UnbanPeer(req.ID, req.IP)
+	if ctx.Sender() != nil {
+		resp := &UnbanPeerRsp{
+			Ok: ok,
+		}
+		ctx.Sender().Request(resp, ctx.Self())
+	}
+}
+
+//light client storage query handler, waiting for the values must not block
+//the actor which dispatches the values
+func (this *P2PActor) handleGetStorageReq(ctx actor.Context, req *GetStorageReq) {
+	sender := ctx.Sender()
+	self := ctx.Self()
+	go func() {
+		value, height, err := this.server.GetStorage(req.Contract, req.Key)
+		if sender != nil {
+			resp := &GetStorageRsp{
+				Value:  value,
+				Height: height,
+				Error:  err,
+			}
+			sender.Request(resp, self)
+		}
+	}()
+}
+
 func (this *P2PActor) handleTransmitConsensusMsgReq(ctx actor.Context, req *TransmitConsensusMsgReq) {
 	peer := this.server.GetNetWork().GetPeer(req.Target)
 	if peer != nil {
@@ -19,6 +19,9 @@
 package server
 
 import (
+	"time"
+
+	comm "github.com/ontio/ontology/common"
 	types "github.com/ontio/ontology/p2pserver/common"
 	ptypes "github.com/ontio/ontology/p2pserver/message/types"
 )
@@ -118,6 +121,50 @@ type GetNeighborAddrsRsp struct {
 	Addrs []types.PeerAddr
 }
 
+//get banned peers request
+type GetBannedPeersReq struct {
+}
+
+//response of banned peers
+type GetBannedPeersRsp struct {
+	Peers []types.BannedPeer
+}
+
+//ban peer id or ip request
+type BanPeerReq struct {
+	ID       uint64
+	IP       string
+	Duration time.Duration
+}
+
+//response of ban peer request
+type BanPeerRsp struct {
+}
+
+//unban peer id or ip request
+type UnbanPeerReq struct {
+	ID uint64
+	IP string
+}
+
+//response of unban peer request
+type UnbanPeerRsp struct {
+	Ok bool
+}
+
+//light client storage query request
+type GetStorageReq struct {
+	Contract comm.Address
+	Key      []byte
+}
+
+//response of light client storage query
+type GetStorageRsp struct {
+	Value  []byte
+	Height uint32
+	Error  error
+}
+
 type TransmitConsensusMsgReq struct {
 	Target uint64
 	Msg    ptypes.Message
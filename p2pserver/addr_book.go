@@ -0,0 +1,489 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	mrand "math/rand"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+//KnownAddress is a peer address in the address book with its connection
+//statistics
+type KnownAddress struct {
+	Addr        string //ip:port of the peer
+	Src         string //ip of the peer which told the address, empty if local
+	LastSeen    int64  //unix time the address was last advertised or connected
+	LastAttempt int64  //unix time of the last connection attempt
+	LastSuccess int64  //unix time of the last successful connection
+	Attempts    int    //failed attempts since the last success
+	Tried       bool   //whether the address is in tried buckets
+	bucket      int
+}
+
+//addrBookFile is the persisted form of address book
+type addrBookFile struct {
+	NetworkMagic uint32
+	Key          string
+	Addrs        []*KnownAddress
+}
+
+//AddrBook keep the known peer addresses. Addresses learned from peers are
+//placed in new buckets, and moved to tried buckets once connected. The bucket
+//of an address is decided by its subnet and the subnet of its source, and the
+//addresses of one /16 subnet are limited, so that an attacker controlling a
+//few subnets can not fill the book and eclipse the node
+type AddrBook struct {
+	sync.Mutex
+	path         string
+	netID        uint32
+	key          [32]byte //random key to place addresses in buckets
+	addrs        map[string]*KnownAddress
+	newBuckets   [common.NEW_BUCKET_COUNT]map[string]*KnownAddress
+	triedBuckets [common.TRIED_BUCKET_COUNT]map[string]*KnownAddress
+	groups       map[string]int //address count of each subnet
+	dirty        bool
+	rand         *mrand.Rand
+}
+
+//NewAddrBook load the address book of the network from file, an empty book
+//is returned if the file not exist
+func NewAddrBook(path string, netID uint32) *AddrBook {
+	this := &AddrBook{
+		path:   path,
+		netID:  netID,
+		addrs:  make(map[string]*KnownAddress),
+		groups: make(map[string]int),
+		rand:   mrand.New(mrand.NewSource(time.Now().UnixNano())),
+	}
+	for i := range this.newBuckets {
+		this.newBuckets[i] = make(map[string]*KnownAddress)
+	}
+	for i := range this.triedBuckets {
+		this.triedBuckets[i] = make(map[string]*KnownAddress)
+	}
+	if !this.load() {
+		rand.Read(this.key[:])
+	}
+	return this
+}
+
+//load read the address book from file, return false if nothing loaded
+func (this *AddrBook) load() bool {
+	if this.path == "" {
+		return false
+	}
+	buf, err := ioutil.ReadFile(this.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("[p2p]read address book %s error: %s", this.path, err)
+		}
+		return false
+	}
+	var file addrBookFile
+	if err = json.Unmarshal(buf, &file); err != nil {
+		log.Warnf("[p2p]parse address book %s error: %s", this.path, err)
+		return false
+	}
+	if file.NetworkMagic != this.netID {
+		log.Infof("[p2p]address book %s is of network %d, ignored", this.path, file.NetworkMagic)
+		return false
+	}
+	key, err := hex.DecodeString(file.Key)
+	if err != nil || len(key) != len(this.key) {
+		log.Warnf("[p2p]invalid key of address book %s", this.path)
+		return false
+	}
+	copy(this.key[:], key)
+
+	now := time.Now().Unix()
+	for _, ka := range file.Addrs {
+		addr, group, err := parseAddr(ka.Addr)
+		if err != nil || this.addrs[addr] != nil || this.isTerrible(ka, now) ||
+			this.groups[group] >= common.MAX_ADDR_PER_GROUP {
+			continue
+		}
+		ka.Addr = addr
+		if ka.Tried {
+			b := this.triedBucket(addr)
+			if len(this.triedBuckets[b]) < common.BUCKET_SIZE {
+				ka.bucket = b
+				this.triedBuckets[b][addr] = ka
+				this.track(ka, group)
+				continue
+			}
+			ka.Tried = false
+		}
+		this.insertNew(ka, group)
+	}
+	log.Infof("[p2p]load %d addresses from address book", len(this.addrs))
+	return true
+}
+
+//AddAddress add the address told by the peer of src ip to new buckets,
+//return false if the address is invalid or known, or its subnet is full
+func (this *AddrBook) AddAddress(addr string, src string) bool {
+	addr, group, err := parseAddr(addr)
+	if err != nil {
+		return false
+	}
+	now := time.Now().Unix()
+	this.Lock()
+	defer this.Unlock()
+	if ka, ok := this.addrs[addr]; ok {
+		ka.LastSeen = now
+		this.dirty = true
+		return false
+	}
+	if this.groups[group] >= common.MAX_ADDR_PER_GROUP {
+		return false
+	}
+	ka := &KnownAddress{
+		Addr:     addr,
+		Src:      src,
+		LastSeen: now,
+	}
+	this.insertNew(ka, group)
+	this.dirty = true
+	return true
+}
+
+//Attempt record a connection attempt to the address, the failures are
+//counted until Good is called
+func (this *AddrBook) Attempt(addr string) {
+	addr, _, err := parseAddr(addr)
+	if err != nil {
+		return
+	}
+	this.Lock()
+	defer this.Unlock()
+	ka, ok := this.addrs[addr]
+	if !ok {
+		return
+	}
+	ka.LastAttempt = time.Now().Unix()
+	ka.Attempts++
+	this.dirty = true
+}
+
+//Good mark the address connected successfully and move it to tried buckets
+func (this *AddrBook) Good(addr string) {
+	addr, group, err := parseAddr(addr)
+	if err != nil {
+		return
+	}
+	now := time.Now().Unix()
+	this.Lock()
+	defer this.Unlock()
+	ka, ok := this.addrs[addr]
+	if !ok {
+		if this.groups[group] >= common.MAX_ADDR_PER_GROUP {
+			return
+		}
+		ka = &KnownAddress{Addr: addr}
+		this.insertNew(ka, group)
+	}
+	ka.LastSeen = now
+	ka.LastSuccess = now
+	ka.Attempts = 0
+	if !ka.Tried {
+		this.makeTried(ka, group)
+	}
+	this.dirty = true
+}
+
+//RemoveAddress remove the address from the book
+func (this *AddrBook) RemoveAddress(addr string) {
+	addr, _, err := parseAddr(addr)
+	if err != nil {
+		return
+	}
+	this.Lock()
+	defer this.Unlock()
+	if ka, ok := this.addrs[addr]; ok {
+		this.remove(ka)
+		this.dirty = true
+	}
+}
+
+//Size return the address count of the book
+func (this *AddrBook) Size() int {
+	this.Lock()
+	defer this.Unlock()
+	return len(this.addrs)
+}
+
+//PickAddresses pick at most n addresses of different subnets to connect,
+//tried and new addresses are picked evenly, and addresses failed recently
+//are less likely to be picked. Addresses that skip returns true are ignored
+func (this *AddrBook) PickAddresses(n int, skip func(addr string) bool) []string {
+	this.Lock()
+	defer this.Unlock()
+	now := time.Now().Unix()
+	var tried, fresh []*KnownAddress
+	for _, ka := range this.addrs {
+		if this.isTerrible(ka, now) || (skip != nil && skip(ka.Addr)) {
+			continue
+		}
+		if ka.Tried {
+			tried = append(tried, ka)
+		} else {
+			fresh = append(fresh, ka)
+		}
+	}
+
+	picked := make([]string, 0, n)
+	groups := make(map[string]bool)
+	for len(picked) < n && len(tried)+len(fresh) > 0 {
+		list := &fresh
+		if len(fresh) == 0 || (len(tried) > 0 && this.rand.Intn(2) == 0) {
+			list = &tried
+		}
+		i := this.pickWeighted(*list, now)
+		ka := (*list)[i]
+		*list = append((*list)[:i], (*list)[i+1:]...)
+		_, group, _ := parseAddr(ka.Addr)
+		if groups[group] {
+			continue
+		}
+		groups[group] = true
+		picked = append(picked, ka.Addr)
+	}
+	return picked
+}
+
+//Save write the address book to file if changed, terrible addresses are
+//dropped
+func (this *AddrBook) Save() {
+	this.Lock()
+	defer this.Unlock()
+	if !this.dirty || this.path == "" {
+		return
+	}
+	now := time.Now().Unix()
+	file := &addrBookFile{
+		NetworkMagic: this.netID,
+		Key:          hex.EncodeToString(this.key[:]),
+		Addrs:        make([]*KnownAddress, 0, len(this.addrs)),
+	}
+	for _, ka := range this.addrs {
+		if this.isTerrible(ka, now) {
+			this.remove(ka)
+			continue
+		}
+		file.Addrs = append(file.Addrs, ka)
+	}
+	buf, err := json.Marshal(file)
+	if err != nil {
+		log.Warnf("[p2p]marshal address book error: %s", err)
+		return
+	}
+	if err = ioutil.WriteFile(this.path, buf, 0644); err != nil {
+		log.Warnf("[p2p]save address book %s error: %s", this.path, err)
+		return
+	}
+	this.dirty = false
+}
+
+//insertNew place the address in new bucket, the worst address is evicted if
+//the bucket is full. Called with lock held
+func (this *AddrBook) insertNew(ka *KnownAddress, group string) {
+	b := this.newBucket(ka.Addr, ka.Src)
+	bucket := this.newBuckets[b]
+	if len(bucket) >= common.BUCKET_SIZE {
+		this.remove(this.worstOf(bucket))
+	}
+	ka.Tried = false
+	ka.bucket = b
+	bucket[ka.Addr] = ka
+	this.track(ka, group)
+}
+
+//makeTried move the address from new bucket to tried bucket, the oldest
+//address of a full tried bucket is moved back to new bucket. Called with
+//lock held
+func (this *AddrBook) makeTried(ka *KnownAddress, group string) {
+	delete(this.newBuckets[ka.bucket], ka.Addr)
+	b := this.triedBucket(ka.Addr)
+	bucket := this.triedBuckets[b]
+	if len(bucket) >= common.BUCKET_SIZE {
+		var oldest *KnownAddress
+		for _, v := range bucket {
+			if oldest == nil || v.LastSuccess < oldest.LastSuccess {
+				oldest = v
+			}
+		}
+		delete(bucket, oldest.Addr)
+		nb := this.newBucket(oldest.Addr, oldest.Src)
+		if len(this.newBuckets[nb]) >= common.BUCKET_SIZE {
+			this.remove(this.worstOf(this.newBuckets[nb]))
+		}
+		oldest.Tried = false
+		oldest.bucket = nb
+		this.newBuckets[nb][oldest.Addr] = oldest
+	}
+	ka.Tried = true
+	ka.bucket = b
+	bucket[ka.Addr] = ka
+}
+
+//worstOf return the terrible or least recently seen address of the bucket
+func (this *AddrBook) worstOf(bucket map[string]*KnownAddress) *KnownAddress {
+	now := time.Now().Unix()
+	var worst *KnownAddress
+	for _, ka := range bucket {
+		if this.isTerrible(ka, now) {
+			return ka
+		}
+		if worst == nil || ka.LastSeen < worst.LastSeen {
+			worst = ka
+		}
+	}
+	return worst
+}
+
+//track record the address in book. Called with lock held
+func (this *AddrBook) track(ka *KnownAddress, group string) {
+	this.addrs[ka.Addr] = ka
+	this.groups[group]++
+}
+
+//remove delete the address from book. Called with lock held
+func (this *AddrBook) remove(ka *KnownAddress) {
+	if ka.Tried {
+		delete(this.triedBuckets[ka.bucket], ka.Addr)
+	} else {
+		delete(this.newBuckets[ka.bucket], ka.Addr)
+	}
+	delete(this.addrs, ka.Addr)
+	_, group, _ := parseAddr(ka.Addr)
+	if this.groups[group]--; this.groups[group] <= 0 {
+		delete(this.groups, group)
+	}
+}
+
+//isTerrible return whether the address is not worth keeping
+func (this *AddrBook) isTerrible(ka *KnownAddress, now int64) bool {
+	if ka.LastAttempt > now-60 {
+		//never drop the address attempted just now
+		return false
+	}
+	if ka.LastSeen < now-common.ADDR_HORIZON_DAYS*24*60*60 {
+		return true
+	}
+	if ka.LastSuccess == 0 && ka.Attempts >= common.ADDR_MAX_RETRIES {
+		return true
+	}
+	if ka.LastSuccess < now-7*24*60*60 && ka.Attempts >= common.ADDR_MAX_FAILURES {
+		return true
+	}
+	return false
+}
+
+//pickWeighted pick an address randomly, weighted by the chance of success
+func (this *AddrBook) pickWeighted(list []*KnownAddress, now int64) int {
+	chances := make([]float64, len(list))
+	total := 0.0
+	for i, ka := range list {
+		chance := math.Pow(0.66, math.Min(float64(ka.Attempts), 8))
+		if now-ka.LastAttempt < common.ADDR_RETRY_INTERVAL {
+			chance *= 0.01
+		}
+		chances[i] = chance
+		total += chance
+	}
+	r := this.rand.Float64() * total
+	for i, chance := range chances {
+		if r < chance {
+			return i
+		}
+		r -= chance
+	}
+	return len(list) - 1
+}
+
+//newBucket return the new bucket of the address told by src, addresses from
+//one source subnet can only be placed in a few buckets
+func (this *AddrBook) newBucket(addr string, src string) int {
+	_, group, _ := parseAddr(addr)
+	srcGroup := ""
+	if ip := net.ParseIP(src); ip != nil {
+		srcGroup = ipGroup(ip)
+	}
+	n := this.hash(group, srcGroup) % common.ADDR_NEW_SOURCE_CNT
+	return int(this.hash(srcGroup, strconv.FormatUint(n, 10)) % common.NEW_BUCKET_COUNT)
+}
+
+//triedBucket return the tried bucket of the address, addresses of one subnet
+//can only be placed in a few buckets
+func (this *AddrBook) triedBucket(addr string) int {
+	_, group, _ := parseAddr(addr)
+	n := this.hash(addr) % common.ADDR_TRIED_GROUP_CNT
+	return int(this.hash(group, strconv.FormatUint(n, 10)) % common.TRIED_BUCKET_COUNT)
+}
+
+//hash return the keyed hash of the data
+func (this *AddrBook) hash(data ...string) uint64 {
+	h := sha256.New()
+	h.Write(this.key[:])
+	for _, d := range data {
+		h.Write([]byte(d))
+		h.Write([]byte{0})
+	}
+	return binary.LittleEndian.Uint64(h.Sum(nil)[:8])
+}
+
+//parseAddr return the normalized ip:port address and its subnet
+func parseAddr(addr string) (string, string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", "", err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", "", fmt.Errorf("invalid ip of address %s", addr)
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return "", "", errors.New("invalid port of address " + addr)
+	}
+	return net.JoinHostPort(ip.String(), port), ipGroup(ip), nil
+}
+
+//ipGroup return the /16 subnet of ipv4 or the /32 subnet of ipv6
+func ipGroup(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d", ip4[0], ip4[1])
+	}
+	return hex.EncodeToString(ip.To16()[:4])
+}
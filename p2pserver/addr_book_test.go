@@ -0,0 +1,106 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddrBookGroupLimit(t *testing.T) {
+	book := NewAddrBook("", 1)
+	for i := 0; i < 2*common.MAX_ADDR_PER_GROUP; i++ {
+		book.AddAddress(fmt.Sprintf("10.1.%d.1:20338", i), "192.168.0.1")
+	}
+	assert.Equal(t, common.MAX_ADDR_PER_GROUP, book.Size())
+
+	assert.True(t, book.AddAddress("10.2.0.1:20338", "192.168.0.1"))
+	assert.False(t, book.AddAddress("10.2.0.1:20338", "192.168.0.2"))
+	assert.False(t, book.AddAddress("10.2.0.1", "192.168.0.1"))
+	assert.False(t, book.AddAddress("10.2.0.2:0", "192.168.0.1"))
+	assert.Equal(t, common.MAX_ADDR_PER_GROUP+1, book.Size())
+}
+
+func TestAddrBookGoodAndAttempt(t *testing.T) {
+	book := NewAddrBook("", 1)
+	book.AddAddress("10.1.0.1:20338", "")
+	book.Good("10.1.0.1:20338")
+	book.Good("10.2.0.1:20338")
+	assert.True(t, book.addrs["10.1.0.1:20338"].Tried)
+	assert.True(t, book.addrs["10.2.0.1:20338"].Tried)
+
+	book.AddAddress("10.3.0.1:20338", "")
+	for i := 0; i < common.ADDR_MAX_RETRIES; i++ {
+		book.Attempt("10.3.0.1:20338")
+	}
+	ka := book.addrs["10.3.0.1:20338"]
+	assert.Equal(t, common.ADDR_MAX_RETRIES, ka.Attempts)
+	ka.LastAttempt -= common.ADDR_RETRY_INTERVAL
+	assert.True(t, book.isTerrible(ka, ka.LastAttempt+common.ADDR_RETRY_INTERVAL))
+	addrs := book.PickAddresses(10, nil)
+	assert.Equal(t, 2, len(addrs))
+	assert.NotContains(t, addrs, "10.3.0.1:20338")
+
+	book.RemoveAddress("10.1.0.1:20338")
+	assert.Equal(t, 2, book.Size())
+}
+
+func TestAddrBookPickDistinctGroups(t *testing.T) {
+	book := NewAddrBook("", 1)
+	for i := 0; i < 4; i++ {
+		book.AddAddress(fmt.Sprintf("10.1.0.%d:20338", i+1), "")
+		book.AddAddress(fmt.Sprintf("10.2.0.%d:20338", i+1), "")
+	}
+	addrs := book.PickAddresses(8, nil)
+	assert.Equal(t, 2, len(addrs))
+
+	addrs = book.PickAddresses(8, func(addr string) bool {
+		_, group, _ := parseAddr(addr)
+		return group == "10.1"
+	})
+	assert.Equal(t, 1, len(addrs))
+}
+
+func TestAddrBookPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addrbook")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "peers.book")
+
+	book := NewAddrBook(path, 1)
+	book.AddAddress("10.1.0.1:20338", "192.168.0.1")
+	book.Good("10.2.0.1:20338")
+	book.Save()
+
+	loaded := NewAddrBook(path, 1)
+	assert.Equal(t, book.key, loaded.key)
+	assert.Equal(t, 2, loaded.Size())
+	assert.False(t, loaded.addrs["10.1.0.1:20338"].Tried)
+	assert.True(t, loaded.addrs["10.2.0.1:20338"].Tried)
+	assert.Equal(t, book.addrs["10.1.0.1:20338"].bucket, loaded.addrs["10.1.0.1:20338"].bucket)
+
+	other := NewAddrBook(path, 2)
+	assert.Equal(t, 0, other.Size())
+}
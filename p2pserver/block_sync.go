@@ -19,15 +19,21 @@
 package p2pserver
 
 import (
+	"errors"
+	"fmt"
 	"math"
+	"runtime"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/core/validation"
+	ontErrors "github.com/ontio/ontology/errors"
 	p2pComm "github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/message/msg_pack"
 	"github.com/ontio/ontology/p2pserver/peer"
@@ -36,7 +42,7 @@ import (
 const (
 	SYNC_MAX_HEADER_FORWARD_SIZE = 5000       //keep CurrentHeaderHeight - CurrentBlockHeight <= SYNC_MAX_HEADER_FORWARD_SIZE
 	SYNC_MAX_FLIGHT_HEADER_SIZE  = 1          //Number of headers on flight
-	SYNC_MAX_FLIGHT_BLOCK_SIZE   = 50         //Number of blocks on flight
+	SYNC_MAX_FLIGHT_BLOCK_SIZE   = 256        //Number of blocks on flight
 	SYNC_MAX_BLOCK_CACHE_SIZE    = 500        //Cache size of block wait to commit to ledger
 	SYNC_HEADER_REQUEST_TIMEOUT  = 2          //s, Request header timeout time. If header haven't receive after SYNC_HEADER_REQUEST_TIMEOUT second, retry
 	SYNC_BLOCK_REQUEST_TIMEOUT   = 2          //s, Request block timeout time. If block haven't received after SYNC_BLOCK_REQUEST_TIMEOUT second, retry
@@ -47,6 +53,10 @@ const (
 	SYNC_NODE_SPEED_INIT         = 100 * 1024 //Init a big speed (100MB/s) for every node in first round
 	SYNC_MAX_ERROR_RESP_TIMES    = 5          //Max error headers/blocks response times, if reaches, delete it
 	SYNC_MAX_HEIGHT_OFFSET       = 5          //Offset of the max height and current height
+	SYNC_PEER_WINDOW_MIN         = 2          //Min number of blocks on flight to one node
+	SYNC_PEER_WINDOW_MAX         = 64         //Max number of blocks on flight to one node
+	SYNC_BLOCK_SIZE_INIT         = 32         //kB, Init avg block size for every node, using for calc the window
+	SYNC_VERIFY_QUEUE_SIZE       = SYNC_MAX_BLOCK_CACHE_SIZE + SYNC_MAX_FLIGHT_BLOCK_SIZE
 )
 
 //NodeWeight record some params of node, using for sort
@@ -56,6 +66,7 @@ type NodeWeight struct {
 	timeoutCnt   int       //Node response timeout count
 	errorRespCnt int       //Node response error data count
 	reqTime      []int64   //Record request time, using for calc the avg req time interval, unit millisecond
+	blockSize    float32   //Avg size of blocks received from node, unit kB
 }
 
 //NewNodeWeight new a nodeweight
@@ -75,12 +86,15 @@ func NewNodeWeight(id uint64) *NodeWeight {
 		timeoutCnt:   0,
 		errorRespCnt: 0,
 		reqTime:      r,
+		blockSize:    SYNC_BLOCK_SIZE_INIT,
 	}
 }
 
-//AddTimeoutCnt incre timeout count
+//AddTimeoutCnt incre timeout count, the timeout is also recorded as zero
+//speed to shrink the window of node
 func (this *NodeWeight) AddTimeoutCnt() {
 	this.timeoutCnt++
+	this.AppendNewSpeed(0)
 }
 
 //AddErrorRespCnt incre receive error header/block count
@@ -105,13 +119,41 @@ func (this *NodeWeight) AppendNewSpeed(s float32) {
 	this.speed[SYNC_NODE_RECORD_SPEED_CNT-1] = s
 }
 
-//Weight calculate node's weight for sort. Highest weight node will be accessed first for next request.
-func (this *NodeWeight) Weight() float32 {
+//AppendBlockSize update the avg block size with the new received block
+func (this *NodeWeight) AppendBlockSize(size uint32) {
+	this.blockSize = this.blockSize*0.8 + float32(size)/1024.0*0.2
+}
+
+func (this *NodeWeight) avgSpeed() float32 {
 	avgSpeed := float32(0.0)
 	for _, s := range this.speed {
 		avgSpeed += s
 	}
-	avgSpeed = avgSpeed / float32(len(this.speed))
+	return avgSpeed / float32(len(this.speed))
+}
+
+//Window calculate the number of blocks can be on flight to the node, the
+//blocks should be responded within half of the request timeout at the node's
+//avg speed. As the speed is measured from request time, a growing queue on
+//the node lowers the speed and the window shrinks accordingly
+func (this *NodeWeight) Window() int {
+	blockSize := this.blockSize
+	if blockSize < 1 {
+		blockSize = 1
+	}
+	w := int(this.avgSpeed() * SYNC_BLOCK_REQUEST_TIMEOUT / 2 / blockSize)
+	if w < SYNC_PEER_WINDOW_MIN {
+		return SYNC_PEER_WINDOW_MIN
+	}
+	if w > SYNC_PEER_WINDOW_MAX {
+		return SYNC_PEER_WINDOW_MAX
+	}
+	return w
+}
+
+//Weight calculate node's weight for sort. Highest weight node will be accessed first for next request.
+func (this *NodeWeight) Weight() float32 {
+	avgSpeed := this.avgSpeed()
 
 	avgInterval := float32(0.0)
 	now := time.Now().UnixNano() / int64(time.Millisecond)
@@ -218,6 +260,7 @@ type BlockInfo struct {
 	nodeID     uint64
 	block      *types.Block
 	merkleRoot common.Uint256
+	verified   bool //Block is verified and ready to commit to ledger
 }
 
 //BlockSyncMgr is the manager class to deal with block sync
@@ -229,10 +272,13 @@ type BlockSyncMgr struct {
 	syncBlockLock  bool                                 //Help to avoid send block sync request duplicate
 	syncHeaderLock bool                                 //Help to avoid send header sync request duplicate
 	saveBlockLock  bool                                 //Help to avoid saving block concurrently
+	saveBlockAgain bool                                 //Save block requested while saving
+	verifyCh       chan *BlockInfo                      //Queue of the blocks waiting for verification
 	exitCh         chan interface{}                     //ExitCh to receive exit signal
 	ledger         *ledger.Ledger                       //ledger
 	lock           sync.RWMutex                         //lock
 	nodeWeights    map[uint64]*NodeWeight               //Map NodeID => NodeStatus, using for getNextNode
+	lightMode      bool                                 //Only sync headers in light client mode
 }
 
 //NewBlockSyncMgr return a BlockSyncMgr instance
@@ -244,7 +290,9 @@ func NewBlockSyncMgr(server *P2PServer) *BlockSyncMgr {
 		server:        server,
 		ledger:        server.ledger,
 		exitCh:        make(chan interface{}, 1),
+		verifyCh:      make(chan *BlockInfo, SYNC_VERIFY_QUEUE_SIZE),
 		nodeWeights:   make(map[uint64]*NodeWeight, 0),
+		lightMode:     config.DefConfig.P2PNode.LightClient,
 	}
 }
 
@@ -261,7 +309,7 @@ func NewBlockCache() *BlockCache {
 }
 
 func (this *BlockCache) addBlock(nodeID uint64, block *types.Block,
-	merkleRoot common.Uint256) bool {
+	merkleRoot common.Uint256) *BlockInfo {
 	this.delBlockLocked(block.Header.Height)
 	blockInfo := &BlockInfo{
 		nodeID:     nodeID,
@@ -272,7 +320,7 @@ func (this *BlockCache) addBlock(nodeID uint64, block *types.Block,
 	if block.Header.TransactionsRoot == common.UINT256_EMPTY {
 		this.emptyBlockAmount += 1
 	}
-	return true
+	return blockInfo
 }
 
 func (this *BlockSyncMgr) clearBlocks(curBlockHeight uint32) {
@@ -292,7 +340,7 @@ func (this *BlockCache) clearBlocks(curBlockHeight uint32) {
 func (this *BlockCache) getBlock(blockHeight uint32) (uint64, *types.Block,
 	common.Uint256) {
 	blockInfo, ok := this.blocksCache[blockHeight]
-	if !ok {
+	if !ok || !blockInfo.verified {
 		return 0, nil, common.UINT256_EMPTY
 	}
 	return blockInfo.nodeID, blockInfo.block, blockInfo.merkleRoot
@@ -324,6 +372,9 @@ func (this *BlockSyncMgr) getNonEmptyBlockCount() int {
 
 //Start to sync
 func (this *BlockSyncMgr) Start() {
+	for i := 0; i < runtime.NumCPU(); i++ {
+		go this.verifyService()
+	}
 	go this.sync()
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
@@ -415,6 +466,9 @@ func (this *BlockSyncMgr) checkTimeout() {
 
 func (this *BlockSyncMgr) sync() {
 	this.syncHeader()
+	if this.lightMode {
+		return
+	}
 	this.syncBlock()
 }
 
@@ -434,7 +488,7 @@ func (this *BlockSyncMgr) syncHeader() {
 
 	curHeaderHeight := this.ledger.GetCurrentHeaderHeight()
 	//Waiting for block catch up header
-	if curHeaderHeight-curBlockHeight >= SYNC_MAX_HEADER_FORWARD_SIZE {
+	if !this.lightMode && curHeaderHeight-curBlockHeight >= SYNC_MAX_HEADER_FORWARD_SIZE {
 		return
 	}
 	NextHeaderId := curHeaderHeight + 1
@@ -480,6 +534,7 @@ func (this *BlockSyncMgr) syncBlock() {
 		count = cacheCap
 	}
 
+	flightCnt := this.getNodeFlightBlockCount()
 	counter := 1
 	i := uint32(0)
 	reqTimes := 1
@@ -508,10 +563,11 @@ func (this *BlockSyncMgr) syncBlock() {
 			reqTimes = SYNC_NEXT_BLOCK_TIMES
 		}
 		for t := 0; t < reqTimes; t++ {
-			reqNode := this.getNextNode(nextBlockHeight)
+			reqNode := this.getNextNodeInWindow(nextBlockHeight, flightCnt)
 			if reqNode == nil {
 				return
 			}
+			flightCnt[reqNode.GetID()]++
 			this.addFlightBlock(reqNode.GetID(), nextBlockHeight, nextBlockHash)
 			msg := msgpack.NewBlkDataReq(nextBlockHash)
 			err := this.server.Send(reqNode, msg, false)
@@ -552,6 +608,11 @@ func (this *BlockSyncMgr) OnHeaderReceive(fromID uint64, headers []*types.Header
 			this.delNode(fromID)
 		}
 		log.Warnf("[p2p]OnHeaderReceive AddHeaders error:%s", err)
+		this.misbehave(fromID, p2pComm.INVALID_HEADER_SCORE, "invalid headers")
+		return
+	}
+	if this.lightMode {
+		this.syncHeader()
 		return
 	}
 	sort.Slice(headers, func(i, j int) bool {
@@ -576,7 +637,8 @@ func (this *BlockSyncMgr) OnHeaderReceive(fromID uint64, headers []*types.Header
 			block := &types.Block{
 				Header: header,
 			}
-			this.addBlockCache(fromID, block, common.UINT256_EMPTY)
+			//header is verified already
+			this.markBlockVerified(this.addBlockCache(fromID, block, common.UINT256_EMPTY))
 		}
 	}
 	go this.saveBlock()
@@ -586,6 +648,9 @@ func (this *BlockSyncMgr) OnHeaderReceive(fromID uint64, headers []*types.Header
 // OnBlockReceive receive block from net
 func (this *BlockSyncMgr) OnBlockReceive(fromID uint64, blockSize uint32, block *types.Block,
 	merkleRoot common.Uint256) {
+	if this.lightMode {
+		return
+	}
 	height := block.Header.Height
 	blockHash := block.Hash()
 	log.Tracef("[p2p]OnBlockReceive Height:%d", height)
@@ -594,6 +659,7 @@ func (this *BlockSyncMgr) OnBlockReceive(fromID uint64, blockSize uint32, block
 		t := (time.Now().UnixNano() - flightInfo.GetStartTime().UnixNano()) / int64(time.Millisecond)
 		s := float32(blockSize) / float32(t) * 1000.0 / 1024.0
 		this.addNewSpeed(fromID, s)
+		this.addBlockSize(fromID, blockSize)
 	}
 
 	this.delFlightBlock(blockHash)
@@ -606,12 +672,94 @@ func (this *BlockSyncMgr) OnBlockReceive(fromID uint64, blockSize uint32, block
 	if height <= curBlockHeight {
 		return
 	}
+	if this.isInBlockCache(height) {
+		return
+	}
 
-	this.addBlockCache(fromID, block, merkleRoot)
-	go this.saveBlock()
+	blockInfo := this.addBlockCache(fromID, block, merkleRoot)
+	select {
+	case this.verifyCh <- blockInfo:
+	default:
+		//block will be requested again
+		log.Warnf("[p2p]OnBlockReceive verify queue full, drop block Height:%d", height)
+		this.delBlockInfo(blockInfo)
+	}
 	this.syncBlock()
 }
 
+//verifyService verify the received blocks in parallel ahead of committing to
+//ledger, so the ledger only executes the verified blocks in order
+func (this *BlockSyncMgr) verifyService() {
+	for {
+		select {
+		case <-this.exitCh:
+			return
+		case blockInfo := <-this.verifyCh:
+			this.verifyBlockInfo(blockInfo)
+		}
+	}
+}
+
+//verifyBlockInfo verify the received block and save it if verified. The block
+//ahead of header chain is dropped without penalty and will be requested again
+func (this *BlockSyncMgr) verifyBlockInfo(blockInfo *BlockInfo) {
+	err := this.verifyBlock(blockInfo.block)
+	if err == errBlockAhead {
+		log.Debugf("[p2p]drop block Height:%d from %d ahead of header chain", blockInfo.block.Header.Height,
+			blockInfo.nodeID)
+		this.delBlockInfo(blockInfo)
+		return
+	}
+	if err != nil {
+		log.Warnf("[p2p]verify block Height:%d from %d error:%s", blockInfo.block.Header.Height,
+			blockInfo.nodeID, err)
+		this.delBlockInfo(blockInfo)
+		this.addErrorRespCnt(blockInfo.nodeID)
+		n := this.getNodeWeight(blockInfo.nodeID)
+		if n != nil && n.GetErrorRespCnt() >= SYNC_MAX_ERROR_RESP_TIMES {
+			this.delNode(blockInfo.nodeID)
+		}
+		this.misbehave(blockInfo.nodeID, p2pComm.INVALID_BLOCK_SCORE, "invalid block")
+		return
+	}
+	this.markBlockVerified(blockInfo)
+	this.saveBlock()
+}
+
+//errBlockAhead the block can not be linked to the header chain yet
+var errBlockAhead = errors.New("block ahead of header chain")
+
+//verifyBlock check the block is the one of the verified header chain, and the
+//transactions are bound to the header and signed correctly
+func (this *BlockSyncMgr) verifyBlock(block *types.Block) error {
+	height := block.Header.Height
+	blockHash := block.Hash()
+	headerHash := this.ledger.GetBlockHash(height)
+	if headerHash == common.UINT256_EMPTY {
+		//new block ahead of header chain, its header is verified by ledger. The
+		//previous header may not be synced yet, which is not the peer's fault
+		if block.Header.PrevBlockHash != this.ledger.GetBlockHash(height-1) {
+			return errBlockAhead
+		}
+	} else if headerHash != blockHash {
+		return fmt.Errorf("block hash %s not in header chain", blockHash.ToHexString())
+	}
+	hashes := make([]common.Uint256, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		hashes = append(hashes, tx.Hash())
+	}
+	if common.ComputeMerkleRoot(hashes) != block.Header.TransactionsRoot {
+		return errors.New("transactions root mismatch")
+	}
+	for _, tx := range block.Transactions {
+		if errCode := validation.VerifyTransactionSignature(tx); errCode != ontErrors.ErrNoError {
+			txHash := tx.Hash()
+			return fmt.Errorf("transaction %s verify error:%s", txHash.ToHexString(), errCode.Error())
+		}
+	}
+	return nil
+}
+
 //OnAddNode to node list when a new node added
 func (this *BlockSyncMgr) OnAddNode(nodeId uint64) {
 	log.Debugf("[p2p]OnAddNode:%d", nodeId)
@@ -672,12 +820,28 @@ func (this *BlockSyncMgr) releaseSyncBlockLock() {
 }
 
 func (this *BlockSyncMgr) addBlockCache(nodeID uint64, block *types.Block,
-	merkleRoot common.Uint256) bool {
+	merkleRoot common.Uint256) *BlockInfo {
 	this.lock.Lock()
 	defer this.lock.Unlock()
 	return this.blocksCache.addBlock(nodeID, block, merkleRoot)
 }
 
+func (this *BlockSyncMgr) markBlockVerified(blockInfo *BlockInfo) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	blockInfo.verified = true
+}
+
+//delBlockInfo delete the block from cache if it is not replaced
+func (this *BlockSyncMgr) delBlockInfo(blockInfo *BlockInfo) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	height := blockInfo.block.Header.Height
+	if this.blocksCache.blocksCache[height] == blockInfo {
+		this.blocksCache.delBlockLocked(height)
+	}
+}
+
 func (this *BlockSyncMgr) getBlockCache(blockHeight uint32) (uint64, *types.Block,
 	common.Uint256) {
 	this.lock.RLock()
@@ -695,23 +859,40 @@ func (this *BlockSyncMgr) tryGetSaveBlockLock() bool {
 	this.lock.Lock()
 	defer this.lock.Unlock()
 	if this.saveBlockLock {
+		this.saveBlockAgain = true
 		return true
 	}
 	this.saveBlockLock = true
 	return false
 }
 
-func (this *BlockSyncMgr) releaseSaveBlockLock() {
+//releaseSaveBlockLock return true and keep the lock if save block was
+//requested while saving, the newly verified block may be missed otherwise
+func (this *BlockSyncMgr) releaseSaveBlockLock() bool {
 	this.lock.Lock()
 	defer this.lock.Unlock()
+	if this.saveBlockAgain {
+		this.saveBlockAgain = false
+		return true
+	}
 	this.saveBlockLock = false
+	return false
 }
 
 func (this *BlockSyncMgr) saveBlock() {
 	if this.tryGetSaveBlockLock() {
 		return
 	}
-	defer this.releaseSaveBlockLock()
+	for {
+		this.saveVerifiedBlocks()
+		if !this.releaseSaveBlockLock() {
+			return
+		}
+	}
+}
+
+//saveVerifiedBlocks commit the verified blocks to ledger in order
+func (this *BlockSyncMgr) saveVerifiedBlocks() {
 	curBlockHeight := this.ledger.GetCurrentBlockHeight()
 	nextBlockHeight := curBlockHeight + 1
 	this.clearBlocks(curBlockHeight)
@@ -729,6 +910,7 @@ func (this *BlockSyncMgr) saveBlock() {
 				this.delNode(fromID)
 			}
 			log.Warnf("[p2p]saveBlock Height:%d AddBlock error:%s", nextBlockHeight, err)
+			this.misbehave(fromID, p2pComm.INVALID_BLOCK_SCORE, "invalid block")
 			reqNode := this.getNextNode(nextBlockHeight)
 			if reqNode == nil {
 				return
@@ -749,6 +931,11 @@ func (this *BlockSyncMgr) saveBlock() {
 	}
 }
 
+//misbehave penalize the node which provided invalid data
+func (this *BlockSyncMgr) misbehave(nodeID uint64, score uint32, reason string) {
+	this.server.GetNetWork().Misbehave(this.server.getNode(nodeID), score, reason)
+}
+
 func (this *BlockSyncMgr) isInBlockCache(blockHeight uint32) bool {
 	this.lock.RLock()
 	defer this.lock.RUnlock()
@@ -845,6 +1032,19 @@ func (this *BlockSyncMgr) getFlightBlockCount() int {
 	return cnt
 }
 
+//getNodeFlightBlockCount return the number of blocks on flight of every node
+func (this *BlockSyncMgr) getNodeFlightBlockCount() map[uint64]int {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	cnt := make(map[uint64]int)
+	for _, infos := range this.flightBlocks {
+		for _, info := range infos {
+			cnt[info.nodeId]++
+		}
+	}
+	return cnt
+}
+
 func (this *BlockSyncMgr) isBlockOnFlight(blockHash common.Uint256) bool {
 	flightInfos := this.getFlightBlocks(blockHash)
 	return len(flightInfos) != 0
@@ -884,6 +1084,26 @@ func (this *BlockSyncMgr) getNextNode(nextBlockHeight uint32) *peer.Peer {
 	}
 }
 
+//getNextNodeInWindow get the next node which has block height and free space
+//in its window, flightCnt is the number of blocks on flight of every node
+func (this *BlockSyncMgr) getNextNodeInWindow(nextBlockHeight uint32, flightCnt map[uint64]int) *peer.Peer {
+	weights := this.getAllNodeWeights()
+	sort.Sort(sort.Reverse(weights))
+	for _, w := range weights {
+		if flightCnt[w.id] >= w.Window() {
+			continue
+		}
+		n := this.server.getNode(w.id)
+		if n == nil || n.GetState() != p2pComm.ESTABLISH {
+			continue
+		}
+		if nextBlockHeight <= uint32(n.GetHeight()) {
+			return n
+		}
+	}
+	return nil
+}
+
 func (this *BlockSyncMgr) getNodeWithMinFailedTimes(flightInfo *SyncFlightInfo, curBlockHeight uint32) *peer.Peer {
 	var minFailedTimes = math.MaxInt64
 	var minFailedTimesNode *peer.Peer
@@ -964,6 +1184,14 @@ func (this *BlockSyncMgr) addNewSpeed(nodeId uint64, speed float32) {
 	}
 }
 
+//addBlockSize update the avg block size of a node
+func (this *BlockSyncMgr) addBlockSize(nodeId uint64, size uint32) {
+	n := this.getNodeWeight(nodeId)
+	if n != nil {
+		n.AppendBlockSize(size)
+	}
+}
+
 //pingOutsyncNodes send ping msg to lower height nodes for syncing
 func (this *BlockSyncMgr) pingOutsyncNodes(curHeight uint32) {
 	peers := make([]*peer.Peer, 0)
@@ -0,0 +1,126 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/core/genesis"
+	"github.com/ontio/ontology/core/ledger"
+	"github.com/ontio/ontology/core/types"
+	"github.com/ontio/ontology/p2pserver/net/netserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeWeightWindow(t *testing.T) {
+	n := NewNodeWeight(1)
+	assert.Equal(t, SYNC_PEER_WINDOW_MAX, n.Window())
+
+	for i := 0; i < SYNC_NODE_RECORD_SPEED_CNT; i++ {
+		n.AppendNewSpeed(10 * SYNC_BLOCK_SIZE_INIT)
+	}
+	assert.Equal(t, 10*SYNC_BLOCK_REQUEST_TIMEOUT/2, n.Window())
+
+	for i := 0; i < 10; i++ {
+		n.AppendBlockSize(10 * SYNC_BLOCK_SIZE_INIT * 1024)
+	}
+	assert.Equal(t, SYNC_PEER_WINDOW_MIN, n.Window())
+
+	m := NewNodeWeight(2)
+	for i := 0; i < SYNC_NODE_RECORD_SPEED_CNT; i++ {
+		m.AddTimeoutCnt()
+	}
+	assert.Equal(t, SYNC_PEER_WINDOW_MIN, m.Window())
+}
+
+func newTestBlockSyncMgr(t *testing.T) (*BlockSyncMgr, func()) {
+	dir, err := ioutil.TempDir("", "blocksync")
+	assert.Nil(t, err)
+	acc := account.NewAccount("")
+	genesisConfig := config.DefConfig.Genesis
+	config.DefConfig.Genesis = &config.GenesisConfig{
+		ConsensusType: config.CONSENSUS_TYPE_SOLO,
+		SOLO: &config.SOLOConfig{
+			Bookkeepers: []string{hex.EncodeToString(keypair.SerializePublicKey(acc.PublicKey))},
+		},
+	}
+	lgr, err := ledger.NewLedger(dir, 0)
+	assert.Nil(t, err)
+	bookkeepers := []keypair.PublicKey{acc.PublicKey}
+	block, err := genesis.BuildGenesisBlock(bookkeepers, config.DefConfig.Genesis)
+	assert.Nil(t, err)
+	assert.Nil(t, lgr.Init(bookkeepers, block))
+
+	server := &P2PServer{network: netserver.NewNetServer(), ledger: lgr}
+	return NewBlockSyncMgr(server), func() {
+		lgr.Close()
+		os.RemoveAll(dir)
+		config.DefConfig.Genesis = genesisConfig
+	}
+}
+
+func TestVerifyBlockInfo(t *testing.T) {
+	mgr, clean := newTestBlockSyncMgr(t)
+	defer clean()
+	mgr.OnAddNode(1)
+
+	//block ahead of header chain is dropped without penalty
+	ahead := &types.Block{Header: &types.Header{Height: 2, PrevBlockHash: common.Uint256{1}}}
+	info := mgr.addBlockCache(1, ahead, common.UINT256_EMPTY)
+	mgr.verifyBlockInfo(info)
+	assert.False(t, mgr.isInBlockCache(2))
+	assert.Equal(t, 0, mgr.getNodeWeight(1).GetErrorRespCnt())
+
+	//transactions not bound to the header
+	badRoot := &types.Block{Header: &types.Header{Height: 1, PrevBlockHash: mgr.ledger.GetBlockHash(0),
+		TransactionsRoot: common.Uint256{1}}}
+	info = mgr.addBlockCache(1, badRoot, common.UINT256_EMPTY)
+	mgr.verifyBlockInfo(info)
+	assert.False(t, mgr.isInBlockCache(1))
+	assert.Equal(t, 1, mgr.getNodeWeight(1).GetErrorRespCnt())
+
+	//block replaced in the header chain
+	forked := &types.Block{Header: &types.Header{Height: 0, Timestamp: 1}}
+	info = mgr.addBlockCache(1, forked, common.UINT256_EMPTY)
+	mgr.verifyBlockInfo(info)
+	assert.False(t, mgr.isInBlockCache(0))
+	assert.Equal(t, 2, mgr.getNodeWeight(1).GetErrorRespCnt())
+}
+
+func TestSaveBlockAgain(t *testing.T) {
+	mgr := &BlockSyncMgr{}
+	assert.False(t, mgr.tryGetSaveBlockLock())
+
+	//save requested while saving, the lock is kept for another round
+	assert.True(t, mgr.tryGetSaveBlockLock())
+	assert.True(t, mgr.tryGetSaveBlockLock())
+	assert.True(t, mgr.releaseSaveBlockLock())
+	assert.True(t, mgr.saveBlockLock)
+
+	assert.False(t, mgr.releaseSaveBlockLock())
+	assert.False(t, mgr.saveBlockLock)
+	assert.False(t, mgr.tryGetSaveBlockLock())
+}
@@ -29,8 +29,10 @@ import (
 
 //peer capability
 const (
-	VERIFY_NODE  = 1 //peer involved in consensus
-	SERVICE_NODE = 2 //peer only sync with consensus peer
+	VERIFY_NODE        = 1 //peer involved in consensus
+	SERVICE_NODE       = 2 //peer only sync with consensus peer
+	COMPACT_BLOCK_NODE = 4 //peer supports compact block relay
+	LIGHT_NODE         = 8 //peer only syncs headers, can not serve blocks or state
 )
 
 //link and concurrent const
@@ -42,6 +44,11 @@ const (
 	MAX_REQ_RECORD_SIZE = 1000       //the maximum request record size
 	MAX_RESP_CACHE_SIZE = 50         //the maximum response cache
 	MAX_TX_CACHE_SIZE   = 100000     //the maximum txHash cache size
+	MAX_KNOWN_TX_CNT    = 32768      //the maximum txHash count a peer is known to have
+	MAX_CMPCT_BLK_CNT   = 16         //the maximum compact block count waiting for missing txs
+	TX_REQ_TIMEOUT      = 5          //time to wait for a requested tx before asking another peer in sec
+	REQ_RATE_WINDOW     = 10         //window to count requests of a peer in sec
+	MAX_REQ_PER_WINDOW  = 3000       //the maximum requests of a peer in REQ_RATE_WINDOW
 )
 
 //msg cmd const
@@ -76,6 +83,16 @@ const (
 	SYNC_BLK_WAIT         = 2     //timespan for blk sync check
 )
 
+//peer misbehavior score, peer is banned when the score reach MISBEHAVIOR_BAN_SCORE
+const (
+	MISBEHAVIOR_BAN_SCORE = 100
+	INVALID_BLOCK_SCORE   = 50 //block failed to add to ledger
+	INVALID_HEADER_SCORE  = 20 //headers failed to add to ledger
+	INVALID_TX_SCORE      = 10 //tx with invalid signature or payload
+	MALFORMED_MSG_SCORE   = 50 //msg failed to decode or with invalid content
+	EXCESSIVE_REQ_SCORE   = 20 //requests exceed MAX_REQ_PER_WINDOW in REQ_RATE_WINDOW
+)
+
 // The peer state
 const (
 	INIT        = 0 //initial
@@ -84,11 +101,20 @@ const (
 	HAND_SHAKED = 3 //send verion to peer and receive peer`s version
 	ESTABLISH   = 4 //receive peer`s verack
 	INACTIVITY  = 5 //link broken
+	SECURE_HAND = 6 //send key exchange to peer and wait for peer`s reply
 )
 
 //cap flag
 const (
-	HTTP_INFO_FLAG = 0 //peer`s http info bit in cap field
+	HTTP_INFO_FLAG   = 0 //peer`s http info bit in cap field
+	SECURE_LINK_FLAG = 1 //peer`s encrypted link bit in cap field
+)
+
+//light client const
+const (
+	LIGHT_QUERY_PEER_CNT = 3 //full peers asked in one state query
+	LIGHT_QUERY_QUORUM   = 2 //same values needed to accept a state
+	LIGHT_QUERY_TIMEOUT  = 5 //time to wait for values in sec
 )
 
 //actor const
@@ -99,8 +125,23 @@ const (
 //recent contact const
 const (
 	RECENT_TIMEOUT   = 60
-	RECENT_FILE_NAME = "peers.recent"
-	RECENT_LIMIT     = 10 //recent contact list limit
+	RECENT_FILE_NAME = "peers.recent" //legacy recent contact list, imported to address book
+)
+
+//address book const
+const (
+	ADDR_BOOK_FILE_NAME  = "peers.book"
+	NEW_BUCKET_COUNT     = 256 //buckets of addresses never connected
+	TRIED_BUCKET_COUNT   = 64  //buckets of addresses connected successfully
+	BUCKET_SIZE          = 64  //the maximum address count in one bucket
+	MAX_ADDR_PER_GROUP   = 16  //the maximum address count of one /16 subnet
+	ADDR_HORIZON_DAYS    = 30  //address not seen in the days is dropped
+	ADDR_MAX_RETRIES     = 3   //address never connected is dropped after the failures
+	ADDR_MAX_FAILURES    = 10  //address is dropped after the failures in a week since last success
+	ADDR_RETRY_INTERVAL  = 600 //address attempted in the secs is less likely to be picked
+	ADDR_TRIED_GROUP_CNT = 4   //tried buckets one /16 subnet can be placed in
+	ADDR_NEW_SOURCE_CNT  = 32  //new buckets addresses from one source subnet can be placed in
+	ADDR_PICK_CNT        = 64  //the maximum addresses picked from address book to connect once
 )
 
 //PeerAddr represent peer`s net information
@@ -114,24 +155,38 @@ type PeerAddr struct {
 	ID            uint64 //Unique ID
 }
 
+//BannedPeer represent a banned peer id or ip
+type BannedPeer struct {
+	ID     uint64 //banned peer id, 0 if ip banned
+	IP     string //banned ip or ip:port, empty if peer id banned
+	Expire int64  //unix timestamp the ban expires
+}
+
 //const channel msg id and type
 const (
-	VERSION_TYPE     = "version"    //peer`s information
-	VERACK_TYPE      = "verack"     //ack msg after version recv
-	GetADDR_TYPE     = "getaddr"    //req nbr address from peer
-	ADDR_TYPE        = "addr"       //nbr address
-	PING_TYPE        = "ping"       //ping  sync height
-	PONG_TYPE        = "pong"       //pong  recv nbr height
-	GET_HEADERS_TYPE = "getheaders" //req blk hdr
-	HEADERS_TYPE     = "headers"    //blk hdr
-	INV_TYPE         = "inv"        //inv payload
-	GET_DATA_TYPE    = "getdata"    //req data from peer
-	BLOCK_TYPE       = "block"      //blk payload
-	TX_TYPE          = "tx"         //transaction
-	CONSENSUS_TYPE   = "consensus"  //consensus payload
-	GET_BLOCKS_TYPE  = "getblocks"  //req blks from peer
-	NOT_FOUND_TYPE   = "notfound"   //peer can`t find blk according to the hash
-	DISCONNECT_TYPE  = "disconnect" //peer disconnect info raise by link
+	VERSION_TYPE       = "version"     //peer`s information
+	VERACK_TYPE        = "verack"      //ack msg after version recv
+	GetADDR_TYPE       = "getaddr"     //req nbr address from peer
+	ADDR_TYPE          = "addr"        //nbr address
+	PING_TYPE          = "ping"        //ping  sync height
+	PONG_TYPE          = "pong"        //pong  recv nbr height
+	GET_HEADERS_TYPE   = "getheaders"  //req blk hdr
+	HEADERS_TYPE       = "headers"     //blk hdr
+	INV_TYPE           = "inv"         //inv payload
+	GET_DATA_TYPE      = "getdata"     //req data from peer
+	BLOCK_TYPE         = "block"       //blk payload
+	TX_TYPE            = "tx"          //transaction
+	CONSENSUS_TYPE     = "consensus"   //consensus payload
+	GET_BLOCKS_TYPE    = "getblocks"   //req blks from peer
+	NOT_FOUND_TYPE     = "notfound"    //peer can`t find blk according to the hash
+	DISCONNECT_TYPE    = "disconnect"  //peer disconnect info raise by link
+	CMPCT_BLOCK_TYPE   = "cmpctblock"  //blk hdr and short tx ids
+	GET_BLOCK_TXN_TYPE = "getblocktxn" //req missing txs of compact blk
+	BLOCK_TXN_TYPE     = "blocktxn"    //missing txs of compact blk
+	KEY_EXCHANGE_TYPE  = "keyexchange" //key exchange for encrypted link
+	MISBEHAVIOR_TYPE   = "misbehavior" //peer misbehavior raise by link
+	GET_STATE_TYPE     = "getstate"    //req state value from full peer
+	STATE_TYPE         = "state"       //state value
 )
 
 type AppendPeerID struct {
@@ -142,6 +197,11 @@ type RemovePeerID struct {
 	ID uint64 // The peer id
 }
 
+type AppendAddrs struct {
+	FromAddr string   // The address of peer sending the addresses
+	Addrs    []string // Addresses to be added to the address book
+}
+
 type AppendHeaders struct {
 	FromID  uint64          // The peer id
 	Headers []*types.Header // Headers to be added to the ledger
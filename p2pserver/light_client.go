@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	comm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	scom "github.com/ontio/ontology/core/store/common"
+	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/message/msg_pack"
+	msgtypes "github.com/ontio/ontology/p2pserver/message/types"
+)
+
+//LightClient queries contract storage from full peers. The state root is not
+//committed in block header, so the query is trust based rather than proven:
+//a value is only accepted when a quorum of peers return the same answer at
+//heights known by the local header chain, and a quorum of dishonest peers
+//could still return a forged value
+type LightClient struct {
+	server  *P2PServer
+	lock    sync.Mutex
+	nextID  uint64
+	pending map[uint64]*stateRequest
+}
+
+//stateRequest is a state query waiting for the reply of the peer asked
+type stateRequest struct {
+	peerID uint64
+	ch     chan *msgtypes.StateValue
+}
+
+//NewLightClient return a LightClient instance
+func NewLightClient(server *P2PServer) *LightClient {
+	return &LightClient{
+		server:  server,
+		pending: make(map[uint64]*stateRequest),
+	}
+}
+
+//GetStorage return the storage value of contract key and the height it was
+//read at, scom.ErrNotFound is returned if the quorum agree it not exist. The
+//query fails if less than LIGHT_QUERY_QUORUM full peers could be asked
+func (this *LightClient) GetStorage(contract comm.Address, key []byte) ([]byte, uint32, error) {
+	peers := this.server.network.GetNeighbors()
+	ch := make(chan *msgtypes.StateValue, common.LIGHT_QUERY_PEER_CNT)
+	ids := make([]uint64, 0, common.LIGHT_QUERY_PEER_CNT)
+	defer func() {
+		this.lock.Lock()
+		for _, id := range ids {
+			delete(this.pending, id)
+		}
+		this.lock.Unlock()
+	}()
+
+	for _, p := range peers {
+		if len(ids) >= common.LIGHT_QUERY_PEER_CNT {
+			break
+		}
+		if p.GetServices()&common.LIGHT_NODE != 0 {
+			continue
+		}
+		this.lock.Lock()
+		this.nextID++
+		id := this.nextID
+		this.pending[id] = &stateRequest{peerID: p.GetID(), ch: ch}
+		this.lock.Unlock()
+
+		err := this.server.Send(p, msgpack.NewGetStateValue(id, contract, key), false)
+		if err != nil {
+			log.Warnf("[p2p]failed to send get state value to %d: %s", p.GetID(), err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) < common.LIGHT_QUERY_QUORUM {
+		return nil, 0, errors.New("not enough full peers to query")
+	}
+	quorum := common.LIGHT_QUERY_QUORUM
+
+	type vote struct {
+		count  int
+		height uint32
+		state  *msgtypes.StateValue
+	}
+	votes := make(map[string]*vote)
+	timer := time.NewTimer(common.LIGHT_QUERY_TIMEOUT * time.Second)
+	defer timer.Stop()
+	for received := 0; received < len(ids); received++ {
+		select {
+		case state := <-ch:
+			if !this.verifyState(state, contract, key) {
+				continue
+			}
+			k := string(state.Value)
+			if !state.Exist {
+				k = "\x00"
+			} else {
+				k = "\x01" + k
+			}
+			v, ok := votes[k]
+			if !ok {
+				v = &vote{state: state}
+				votes[k] = v
+			}
+			v.count++
+			if state.Height > v.height {
+				v.height = state.Height
+			}
+			if v.count >= quorum {
+				if !v.state.Exist {
+					return nil, v.height, scom.ErrNotFound
+				}
+				return v.state.Value, v.height, nil
+			}
+		case <-timer.C:
+			return nil, 0, errors.New("wait state value timeout")
+		}
+	}
+	return nil, 0, errors.New("state value quorum not reached")
+}
+
+//verifyState check the value answers the request and is bound to a block of
+//the local header chain
+func (this *LightClient) verifyState(state *msgtypes.StateValue, contract comm.Address, key []byte) bool {
+	if state.Contract != contract || string(state.Key) != string(key) {
+		return false
+	}
+	hash := this.server.ledger.GetBlockHash(state.Height)
+	if hash == comm.UINT256_EMPTY || hash != state.BlockHash {
+		log.Debugf("[p2p]state value at unknown block %d %s", state.Height, state.BlockHash.ToHexString())
+		return false
+	}
+	return true
+}
+
+//OnStateValueReceive dispatch the value to the waiting request, the value
+//not from the peer asked is dropped
+func (this *LightClient) OnStateValueReceive(fromID uint64, state *msgtypes.StateValue) {
+	this.lock.Lock()
+	req, ok := this.pending[state.ReqID]
+	if ok && req.peerID != fromID {
+		this.lock.Unlock()
+		log.Debugf("[p2p]state value %d from %d not the peer asked", state.ReqID, fromID)
+		return
+	}
+	delete(this.pending, state.ReqID)
+	this.lock.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case req.ch <- state:
+	default:
+	}
+}
@@ -0,0 +1,41 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package p2pserver
+
+import (
+	"testing"
+
+	msgtypes "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLightClientStateFromAskedPeer(t *testing.T) {
+	client := NewLightClient(nil)
+	ch := make(chan *msgtypes.StateValue, 1)
+	client.pending[1] = &stateRequest{peerID: 100, ch: ch}
+
+	client.OnStateValueReceive(200, &msgtypes.StateValue{ReqID: 1})
+	assert.Equal(t, 0, len(ch))
+	assert.NotNil(t, client.pending[1])
+
+	client.OnStateValueReceive(100, &msgtypes.StateValue{ReqID: 1, Height: 10})
+	assert.Equal(t, 1, len(ch))
+	assert.Equal(t, uint32(10), (<-ch).Height)
+	assert.Nil(t, client.pending[1])
+}
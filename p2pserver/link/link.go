@@ -20,9 +20,12 @@ package link
 
 import (
 	"bufio"
+	"crypto/cipher"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"sync"
 	"time"
 
 	comm "github.com/ontio/ontology/common"
@@ -40,6 +43,18 @@ type Link struct {
 	time      time.Time              // The latest time the node activity
 	recvChan  chan *types.MsgPayload //msgpayload channel
 	reqRecord map[string]int64       //Map RequestId to Timestamp, using for rejecting duplicate request in specific time
+	reqWindow int64                  //start time of current request rate window
+	reqCount  uint32                 //requests received in current request rate window
+
+	secureLock   sync.Mutex  //protect the encrypted link state and sending
+	nodeKey      *NodeKey    //local node key, nil if encrypted link disabled
+	ephPriv      [32]byte    //ephemeral key sent by StartSecure
+	ephPub       [32]byte    //ephemeral public key sent by StartSecure
+	keySent      bool        //key exchange sent and waiting for reply
+	sendAead     cipher.AEAD //cipher of outgoing frames
+	sendCounter  uint64      //nonce counter of outgoing frames
+	remotePubKey []byte      //authenticated node key of peer
+	required     bool        //plain messages other than handshake are refused
 }
 
 func NewLink() *Link {
@@ -59,6 +74,121 @@ func (this *Link) GetID() uint64 {
 	return this.id
 }
 
+//SetNodeKey set local node key to enable encrypted link
+func (this *Link) SetNodeKey(key *NodeKey) {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	this.nodeKey = key
+}
+
+//SecureSupported return whether the encrypted link is enabled locally
+func (this *Link) SecureSupported() bool {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	return this.nodeKey != nil
+}
+
+//SetSecureRequired set whether the link must be encrypted, once required
+//only the handshake messages could be received before key exchange
+func (this *Link) SetSecureRequired(required bool) {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	this.required = required
+}
+
+//plainAllowed return whether the message could be received from the link
+//not encrypted yet
+func (this *Link) plainAllowed(msg types.Message) bool {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	if !this.required || this.sendAead != nil {
+		return true
+	}
+	cmd := msg.CmdType()
+	return cmd == common.VERACK_TYPE || cmd == common.KEY_EXCHANGE_TYPE
+}
+
+//IsSecure return whether the link is encrypted
+func (this *Link) IsSecure() bool {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	return this.sendAead != nil
+}
+
+//GetRemotePubKey return the authenticated node key of peer, nil if the link
+//is not encrypted
+func (this *Link) GetRemotePubKey() []byte {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	return this.remotePubKey
+}
+
+//StartSecure send key exchange to peer, nothing could be sent until the
+//reply of peer received
+func (this *Link) StartSecure() error {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	if this.nodeKey == nil {
+		return errors.New("[p2p]encrypted link not enabled")
+	}
+	if this.keySent || this.sendAead != nil {
+		return errors.New("[p2p]key exchange already started")
+	}
+	msg, priv, err := newKeyExchange(this.nodeKey)
+	if err != nil {
+		return err
+	}
+	sink := comm.NewZeroCopySink(nil)
+	types.WriteMessage(sink, msg)
+	if err = this.write(sink.Bytes()); err != nil {
+		return err
+	}
+	this.ephPriv = priv
+	this.ephPub = msg.Ephemeral
+	this.keySent = true
+	return nil
+}
+
+//onKeyExchange verify the key exchange of peer and switch the link to
+//encrypted, replying own key exchange if peer started it
+func (this *Link) onKeyExchange(msg *types.KeyExchange, reader io.Reader) (io.Reader, error) {
+	this.secureLock.Lock()
+	defer this.secureLock.Unlock()
+	if this.nodeKey == nil {
+		return nil, errors.New("encrypted link not enabled")
+	}
+	if this.sendAead != nil {
+		return nil, errors.New("duplicated key exchange")
+	}
+	if err := verifyKeyExchange(msg, this.id); err != nil {
+		return nil, err
+	}
+
+	initiator := this.keySent
+	if !initiator {
+		reply, priv, err := newKeyExchange(this.nodeKey)
+		if err != nil {
+			return nil, err
+		}
+		sink := comm.NewZeroCopySink(nil)
+		types.WriteMessage(sink, reply)
+		if err = this.write(sink.Bytes()); err != nil {
+			return nil, err
+		}
+		this.ephPriv = priv
+		this.ephPub = reply.Ephemeral
+	}
+	send, recv, err := deriveSessionKeys(this.ephPriv, this.ephPub, msg.Ephemeral, initiator)
+	if err != nil {
+		return nil, err
+	}
+	this.sendAead = send
+	this.keySent = false
+	this.ephPriv = [32]byte{}
+	this.remotePubKey = append([]byte{}, msg.PubKey[:]...)
+	return &secureReader{reader: reader, aead: recv}, nil
+}
+
 //If there is connection return true
 func (this *Link) Valid() bool {
 	return this.conn != nil
@@ -115,18 +245,39 @@ func (this *Link) Rx() {
 		return
 	}
 
-	reader := bufio.NewReaderSize(conn, common.MAX_BUF_LEN)
+	var reader io.Reader = bufio.NewReaderSize(conn, common.MAX_BUF_LEN)
 
 	for {
 		msg, payloadSize, err := types.ReadMessage(reader)
 		if err != nil {
 			log.Infof("[p2p]error read from %s :%s", this.GetAddr(), err.Error())
+			if _, ok := err.(*types.MalformedMsgError); ok {
+				this.misbehaviorNotify(common.MALFORMED_MSG_SCORE, err.Error())
+			}
 			break
 		}
+		if !this.plainAllowed(msg) {
+			log.Warnf("[p2p]receive %s from %s before link encrypted", msg.CmdType(), this.GetAddr())
+			break
+		}
+		if keyExchange, ok := msg.(*types.KeyExchange); ok {
+			reader, err = this.onKeyExchange(keyExchange, reader)
+			if err != nil {
+				log.Warnf("[p2p]key exchange with %s failed: %s", this.GetAddr(), err)
+				break
+			}
+		}
 
 		t := time.Now()
 		this.UpdateRXTime(t)
 
+		if count := this.countReq(msg, t.Unix()); count > common.MAX_REQ_PER_WINDOW {
+			if count == common.MAX_REQ_PER_WINDOW+1 {
+				this.misbehaviorNotify(common.EXCESSIVE_REQ_SCORE, "excessive requests")
+			}
+			log.Debugf("skip handle msgType:%s from:%d, too many requests", msg.CmdType(), this.id)
+			continue
+		}
 		if !this.needSendMsg(msg) {
 			log.Debugf("skip handle msgType:%s from:%d", msg.CmdType(), this.id)
 			continue
@@ -159,6 +310,18 @@ func (this *Link) disconnectNotify() {
 	this.recvChan <- discMsg
 }
 
+//misbehaviorNotify push misbehavior msg of peer to channel
+func (this *Link) misbehaviorNotify(score uint32, reason string) {
+	this.recvChan <- &types.MsgPayload{
+		Id:   this.id,
+		Addr: this.addr,
+		Payload: &types.Misbehavior{
+			Score:  score,
+			Reason: reason,
+		},
+	}
+}
+
 //close connection
 func (this *Link) CloseConn() {
 	if this.conn != nil {
@@ -175,6 +338,31 @@ func (this *Link) Send(msg types.Message) error {
 }
 
 func (this *Link) SendRaw(rawPacket []byte) error {
+	if this.conn == nil {
+		return errors.New("[p2p]tx link invalid")
+	}
+	this.secureLock.Lock()
+	if this.keySent {
+		this.secureLock.Unlock()
+		return errors.New("[p2p]waiting for key exchange reply")
+	}
+	if this.sendAead != nil {
+		rawPacket = sealFrame(this.sendAead, this.sendCounter, rawPacket)
+		this.sendCounter++
+	}
+	err := this.write(rawPacket)
+	this.secureLock.Unlock()
+	if err != nil {
+		log.Infof("[p2p]error sending messge to %s :%s", this.GetAddr(), err.Error())
+		this.disconnectNotify()
+		return err
+	}
+
+	return nil
+}
+
+//write send the packet to connection, called with secureLock held
+func (this *Link) write(rawPacket []byte) error {
 	conn := this.conn
 	if conn == nil {
 		return errors.New("[p2p]tx link invalid")
@@ -189,13 +377,7 @@ func (this *Link) SendRaw(rawPacket []byte) error {
 	}
 	conn.SetWriteDeadline(time.Now().Add(time.Duration(nCount*common.WRITE_DEADLINE) * time.Second))
 	_, err := conn.Write(rawPacket)
-	if err != nil {
-		log.Infof("[p2p]error sending messge to %s :%s", this.GetAddr(), err.Error())
-		this.disconnectNotify()
-		return err
-	}
-
-	return nil
+	return err
 }
 
 //needSendMsg check whether the msg is needed to push to channel
@@ -215,6 +397,23 @@ func (this *Link) needSendMsg(msg types.Message) bool {
 	return true
 }
 
+//countReq count the requests received in current request rate window,
+//return the count including msg, 0 if msg is not a request
+func (this *Link) countReq(msg types.Message, now int64) uint32 {
+	switch msg.CmdType() {
+	case common.GET_DATA_TYPE, common.GET_HEADERS_TYPE, common.GET_BLOCKS_TYPE, common.GetADDR_TYPE,
+		common.GET_BLOCK_TXN_TYPE, common.GET_STATE_TYPE:
+	default:
+		return 0
+	}
+	if now-this.reqWindow >= common.REQ_RATE_WINDOW {
+		this.reqWindow = now
+		this.reqCount = 0
+	}
+	this.reqCount++
+	return this.reqCount
+}
+
 //addReqRecord add request record by removing outdated request records
 func (this *Link) addReqRecord(msg types.Message) {
 	if msg.CmdType() != common.GET_DATA_TYPE {
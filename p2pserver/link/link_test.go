@@ -20,6 +20,7 @@ package link
 
 import (
 	"math/rand"
+	"net"
 	"testing"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	ct "github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/p2pserver/common"
 	mt "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -196,3 +198,54 @@ func TestUnpackBufNode(t *testing.T) {
 	sink := comm.NewZeroCopySink(nil)
 	mt.WriteMessage(sink, msg)
 }
+
+func TestDuplicateDataReqNotScored(t *testing.T) {
+	conn1, conn2 := net.Pipe()
+	link1, link2 := NewLink(), NewLink()
+	link1.SetConn(conn1)
+	link2.SetConn(conn2)
+	ch2 := make(chan *mt.MsgPayload, 10)
+	link2.SetChan(ch2)
+	go link2.Rx()
+
+	req := &mt.DataReq{DataType: comm.BLOCK}
+	link1.Send(req)
+	link1.Send(req)
+	link1.Send(&mt.Ping{Height: 100})
+	assert.Equal(t, common.GET_DATA_TYPE, recvMsg(t, ch2).CmdType())
+	assert.Equal(t, common.PING_TYPE, recvMsg(t, ch2).CmdType())
+}
+
+func TestExcessiveReqScored(t *testing.T) {
+	conn1, conn2 := net.Pipe()
+	link1, link2 := NewLink(), NewLink()
+	link1.SetConn(conn1)
+	link2.SetConn(conn2)
+	ch2 := make(chan *mt.MsgPayload, 10)
+	link2.SetChan(ch2)
+	go link2.Rx()
+
+	go func() {
+		req := &mt.DataReq{DataType: comm.BLOCK}
+		for i := 0; i <= common.MAX_REQ_PER_WINDOW+1; i++ {
+			link1.Send(req)
+		}
+		link1.Send(&mt.Ping{Height: 100})
+	}()
+	assert.Equal(t, common.GET_DATA_TYPE, recvMsg(t, ch2).CmdType())
+	misbehavior, ok := recvMsg(t, ch2).(*mt.Misbehavior)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(common.EXCESSIVE_REQ_SCORE), misbehavior.Score)
+	//scored once in a window, other messages are still handled
+	assert.Equal(t, common.PING_TYPE, recvMsg(t, ch2).CmdType())
+}
+
+func TestCountReq(t *testing.T) {
+	link := NewLink()
+	now := time.Now().Unix()
+	assert.Equal(t, uint32(0), link.countReq(&mt.Ping{}, now))
+	assert.Equal(t, uint32(1), link.countReq(&mt.DataReq{}, now))
+	assert.Equal(t, uint32(2), link.countReq(&mt.HeadersReq{}, now+common.REQ_RATE_WINDOW-1))
+	//a new window starts
+	assert.Equal(t, uint32(1), link.countReq(&mt.DataReq{}, now+common.REQ_RATE_WINDOW))
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package link
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/message/types"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/ed25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	keyExchangeDomain = "ontology p2p key exchange"
+	sessionKeyInfo    = "ontology p2p session key"
+	frameLenSize      = 4
+)
+
+//NodeKey is the long term identity key of a node, the peer ID of node with
+//encrypted link enabled is derived from its public key
+type NodeKey struct {
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+//GenerateNodeKey create a random node key
+func GenerateNodeKey() (*NodeKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeKey{priv: priv, pub: pub}, nil
+}
+
+//LoadOrCreateNodeKey load the node key from file, a new key is generated and
+//saved if the file not exist
+func LoadOrCreateNodeKey(path string) (*NodeKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid node key file %s", path)
+		}
+		priv := ed25519.NewKeyFromSeed(seed)
+		return &NodeKey{priv: priv, pub: priv.Public().(ed25519.PublicKey)}, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := GenerateNodeKey()
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(path, []byte(hex.EncodeToString(key.priv.Seed())), 0600)
+	if err != nil {
+		return nil, fmt.Errorf("save node key to %s error: %s", path, err)
+	}
+	return key, nil
+}
+
+//PublicKey return the public key of node
+func (this *NodeKey) PublicKey() []byte {
+	return this.pub
+}
+
+//PeerID return the peer ID bound to the node key
+func (this *NodeKey) PeerID() uint64 {
+	return PeerIDFromPubKey(this.pub)
+}
+
+//PeerIDFromPubKey derive peer ID from node public key
+func PeerIDFromPubKey(pubKey []byte) uint64 {
+	sum := sha256.Sum256(pubKey)
+	return binary.LittleEndian.Uint64(sum[:8])
+}
+
+func keyExchangeSignData(ephemeral []byte) []byte {
+	return append([]byte(keyExchangeDomain), ephemeral...)
+}
+
+//newKeyExchange create the key exchange message with a new ephemeral key
+func newKeyExchange(key *NodeKey) (*types.KeyExchange, [32]byte, error) {
+	var priv, pub [32]byte
+	if _, err := io.ReadFull(rand.Reader, priv[:]); err != nil {
+		return nil, priv, err
+	}
+	curve25519.ScalarBaseMult(&pub, &priv)
+
+	msg := &types.KeyExchange{}
+	copy(msg.PubKey[:], key.pub)
+	msg.Ephemeral = pub
+	copy(msg.Signature[:], ed25519.Sign(key.priv, keyExchangeSignData(pub[:])))
+	return msg, priv, nil
+}
+
+//verifyKeyExchange check the signature of the ephemeral key and that the
+//peer ID is bound to the identity key
+func verifyKeyExchange(msg *types.KeyExchange, peerID uint64) error {
+	if !ed25519.Verify(msg.PubKey[:], keyExchangeSignData(msg.Ephemeral[:]), msg.Signature[:]) {
+		return errors.New("invalid key exchange signature")
+	}
+	if PeerIDFromPubKey(msg.PubKey[:]) != peerID {
+		return fmt.Errorf("peer id %d not bound to the public key", peerID)
+	}
+	return nil
+}
+
+//deriveSessionKeys compute the send and receive cipher from the ephemeral
+//keys, the initiator is the side sending key exchange first
+func deriveSessionKeys(ephPriv [32]byte, localEph, remoteEph [32]byte,
+	initiator bool) (send cipher.AEAD, recv cipher.AEAD, err error) {
+	var shared [32]byte
+	curve25519.ScalarMult(&shared, &ephPriv, &remoteEph)
+	if shared == [32]byte{} {
+		return nil, nil, errors.New("invalid ephemeral key")
+	}
+
+	var salt []byte
+	if initiator {
+		salt = append(localEph[:], remoteEph[:]...)
+	} else {
+		salt = append(remoteEph[:], localEph[:]...)
+	}
+	keys := make([]byte, 2*chacha20poly1305.KeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, shared[:], salt, []byte(sessionKeyInfo)), keys); err != nil {
+		return nil, nil, err
+	}
+	initKey, respKey := keys[:chacha20poly1305.KeySize], keys[chacha20poly1305.KeySize:]
+	if !initiator {
+		initKey, respKey = respKey, initKey
+	}
+	if send, err = chacha20poly1305.New(initKey); err != nil {
+		return nil, nil, err
+	}
+	if recv, err = chacha20poly1305.New(respKey); err != nil {
+		return nil, nil, err
+	}
+	return send, recv, nil
+}
+
+func frameNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.LittleEndian.PutUint64(nonce, counter)
+	return nonce
+}
+
+//sealFrame encrypt the raw packet into a length prefixed frame
+func sealFrame(aead cipher.AEAD, counter uint64, plain []byte) []byte {
+	frame := make([]byte, frameLenSize, frameLenSize+len(plain)+aead.Overhead())
+	frame = aead.Seal(frame, frameNonce(aead, counter), plain, nil)
+	binary.LittleEndian.PutUint32(frame, uint32(len(frame)-frameLenSize))
+	return frame
+}
+
+//secureReader decrypt the frames from underlying reader
+type secureReader struct {
+	reader  io.Reader
+	aead    cipher.AEAD
+	counter uint64
+	buf     []byte
+}
+
+func (this *secureReader) Read(p []byte) (int, error) {
+	if len(this.buf) == 0 {
+		if err := this.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, this.buf)
+	this.buf = this.buf[n:]
+	return n, nil
+}
+
+func (this *secureReader) readFrame() error {
+	var lenBuf [frameLenSize]byte
+	if _, err := io.ReadFull(this.reader, lenBuf[:]); err != nil {
+		return err
+	}
+	length := binary.LittleEndian.Uint32(lenBuf[:])
+	if length > common.MAX_MSG_LEN+uint32(this.aead.Overhead()) {
+		return &types.MalformedMsgError{Err: fmt.Errorf("secure frame length %d exceed max size", length)}
+	}
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(this.reader, frame); err != nil {
+		return err
+	}
+	plain, err := this.aead.Open(frame[:0], frameNonce(this.aead, this.counter), frame, nil)
+	if err != nil {
+		return &types.MalformedMsgError{Err: errors.New("decrypt secure frame failed")}
+	}
+	this.counter++
+	this.buf = plain
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package link
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/p2pserver/common"
+	mt "github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecureLinkPair(t *testing.T) (*Link, *Link, chan *mt.MsgPayload, chan *mt.MsgPayload) {
+	key1, err := GenerateNodeKey()
+	assert.Nil(t, err)
+	key2, err := GenerateNodeKey()
+	assert.Nil(t, err)
+
+	conn1, conn2 := net.Pipe()
+	link1, link2 := NewLink(), NewLink()
+	ch1, ch2 := make(chan *mt.MsgPayload, 10), make(chan *mt.MsgPayload, 10)
+	link1.SetConn(conn1)
+	link1.SetChan(ch1)
+	link1.SetNodeKey(key1)
+	link1.SetID(key2.PeerID())
+	link2.SetConn(conn2)
+	link2.SetChan(ch2)
+	link2.SetNodeKey(key2)
+	link2.SetID(key1.PeerID())
+	return link1, link2, ch1, ch2
+}
+
+func recvMsg(t *testing.T, ch chan *mt.MsgPayload) mt.Message {
+	select {
+	case msg := <-ch:
+		return msg.Payload
+	case <-time.After(time.Second):
+		t.Fatal("can`t read data from link channel")
+	}
+	return nil
+}
+
+func TestSecureLink(t *testing.T) {
+	link1, link2, ch1, ch2 := newSecureLinkPair(t)
+	go link1.Rx()
+	go link2.Rx()
+
+	assert.Nil(t, link1.StartSecure())
+	assert.NotNil(t, link1.SendRaw([]byte{1}))
+
+	assert.Equal(t, common.KEY_EXCHANGE_TYPE, recvMsg(t, ch2).CmdType())
+	assert.Equal(t, common.KEY_EXCHANGE_TYPE, recvMsg(t, ch1).CmdType())
+	assert.True(t, link1.IsSecure())
+	assert.True(t, link2.IsSecure())
+	assert.True(t, bytes.Equal(link1.GetRemotePubKey(), link2.nodeKey.PublicKey()))
+	assert.True(t, bytes.Equal(link2.GetRemotePubKey(), link1.nodeKey.PublicKey()))
+
+	go link1.Send(&mt.Ping{Height: 100})
+	ping := recvMsg(t, ch2).(*mt.Ping)
+	assert.Equal(t, uint64(100), ping.Height)
+
+	go link2.Send(&mt.Pong{Height: 200})
+	pong := recvMsg(t, ch1).(*mt.Pong)
+	assert.Equal(t, uint64(200), pong.Height)
+}
+
+func TestSecureLinkUnboundID(t *testing.T) {
+	link1, link2, _, ch2 := newSecureLinkPair(t)
+	link2.SetID(12345)
+	go link2.Rx()
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			if _, err := link1.conn.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	assert.Nil(t, link1.StartSecure())
+	assert.Equal(t, common.DISCONNECT_TYPE, recvMsg(t, ch2).CmdType())
+	assert.False(t, link2.IsSecure())
+}
+
+func TestSecureLinkRequired(t *testing.T) {
+	link1, link2, _, ch2 := newSecureLinkPair(t)
+	link2.SetSecureRequired(true)
+	go link2.Rx()
+
+	go link1.Send(&mt.VerACK{})
+	assert.Equal(t, common.VERACK_TYPE, recvMsg(t, ch2).CmdType())
+
+	go link1.Send(&mt.Ping{Height: 100})
+	assert.Equal(t, common.DISCONNECT_TYPE, recvMsg(t, ch2).CmdType())
+	assert.False(t, link2.IsSecure())
+}
@@ -173,6 +173,9 @@ func NewVersion(n p2pnet.P2P, height uint32) mt.Message {
 	} else {
 		version.P.Cap[msgCommon.HTTP_INFO_FLAG] = 0x00
 	}
+	if n.GetNodeKey() != nil {
+		version.P.Cap[msgCommon.SECURE_LINK_FLAG] = 0x01
+	}
 	return &version
 }
 
@@ -196,6 +199,43 @@ func NewBlkDataReq(hash common.Uint256) mt.Message {
 	return &dataReq
 }
 
+//compact block request package
+func NewCmpctBlkDataReq(hash common.Uint256) mt.Message {
+	log.Trace()
+	var dataReq mt.DataReq
+	dataReq.DataType = common.COMPACT_BLOCK
+	dataReq.Hash = hash
+
+	return &dataReq
+}
+
+//compact block package
+func NewCompactBlock(bk *ct.Block, merkleRoot common.Uint256) mt.Message {
+	log.Trace()
+	return mt.NewCompactBlock(bk, merkleRoot)
+}
+
+//missing txs of compact block request package
+func NewGetBlockTxn(hash common.Uint256, indexes []uint32) mt.Message {
+	log.Trace()
+	var req mt.GetBlockTxn
+	req.BlockHash = hash
+	req.Indexes = indexes
+
+	return &req
+}
+
+//missing txs of compact block package
+func NewBlockTxn(hash common.Uint256, indexes []uint32, txs []*ct.Transaction) mt.Message {
+	log.Trace()
+	var blkTxn mt.BlockTxn
+	blkTxn.BlockHash = hash
+	blkTxn.Indexes = indexes
+	blkTxn.Txs = txs
+
+	return &blkTxn
+}
+
 //consensus request package
 func NewConsensusDataReq(hash common.Uint256) mt.Message {
 	log.Trace()
@@ -205,3 +245,30 @@ func NewConsensusDataReq(hash common.Uint256) mt.Message {
 
 	return &dataReq
 }
+
+//state value request package
+func NewGetStateValue(reqID uint64, contract common.Address, key []byte) mt.Message {
+	log.Trace()
+	var req mt.GetStateValue
+	req.ReqID = reqID
+	req.Contract = contract
+	req.Key = key
+
+	return &req
+}
+
+//state value package
+func NewStateValue(req *mt.GetStateValue, height uint32, blockHash common.Uint256,
+	exist bool, value []byte) mt.Message {
+	log.Trace()
+	var state mt.StateValue
+	state.ReqID = req.ReqID
+	state.Contract = req.Contract
+	state.Key = req.Key
+	state.Height = height
+	state.BlockHash = blockHash
+	state.Exist = exist
+	state.Value = value
+
+	return &state
+}
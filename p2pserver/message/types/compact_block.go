@@ -0,0 +1,200 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/common"
+	ct "github.com/ontio/ontology/core/types"
+	comm "github.com/ontio/ontology/p2pserver/common"
+)
+
+// CompactBlock carries a new block as its header plus short tx ids, the
+// receiver rebuilds the block from the transactions in its tx pool
+type CompactBlock struct {
+	Header     *ct.Header
+	ShortIDs   []uint64
+	MerkleRoot common.Uint256
+}
+
+// ShortTxID returns the short id of a transaction in the block, salted by the
+// block hash so that colliding ids can not be prepared in advance
+func ShortTxID(blockHash common.Uint256, txHash common.Uint256) uint64 {
+	var buf [common.UINT256_SIZE * 2]byte
+	copy(buf[:common.UINT256_SIZE], blockHash[:])
+	copy(buf[common.UINT256_SIZE:], txHash[:])
+	sum := sha256.Sum256(buf[:])
+	return binary.LittleEndian.Uint64(sum[:8])
+}
+
+// NewCompactBlock builds the compact form of the block
+func NewCompactBlock(block *ct.Block, merkleRoot common.Uint256) *CompactBlock {
+	blockHash := block.Hash()
+	ids := make([]uint64, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		ids = append(ids, ShortTxID(blockHash, tx.Hash()))
+	}
+	return &CompactBlock{
+		Header:     block.Header,
+		ShortIDs:   ids,
+		MerkleRoot: merkleRoot,
+	}
+}
+
+//Serialize message payload
+func (this *CompactBlock) Serialization(sink *common.ZeroCopySink) {
+	this.Header.Serialization(sink)
+	sink.WriteUint32(uint32(len(this.ShortIDs)))
+	for _, id := range this.ShortIDs {
+		sink.WriteUint64(id)
+	}
+	sink.WriteHash(this.MerkleRoot)
+}
+
+func (this *CompactBlock) CmdType() string {
+	return comm.CMPCT_BLOCK_TYPE
+}
+
+//Deserialize message payload
+func (this *CompactBlock) Deserialization(source *common.ZeroCopySource) error {
+	this.Header = new(ct.Header)
+	err := this.Header.Deserialization(source)
+	if err != nil {
+		return fmt.Errorf("read Header error. err:%v", err)
+	}
+
+	count, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if uint64(count)*8 > source.Len() {
+		return io.ErrUnexpectedEOF
+	}
+	this.ShortIDs = make([]uint64, 0, count)
+	for i := uint32(0); i < count; i++ {
+		id, eof := source.NextUint64()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		this.ShortIDs = append(this.ShortIDs, id)
+	}
+
+	this.MerkleRoot, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// GetBlockTxn requests the transactions of a compact block which are
+// missing in the tx pool of the receiver
+type GetBlockTxn struct {
+	BlockHash common.Uint256
+	Indexes   []uint32
+}
+
+//Serialize message payload
+func (this *GetBlockTxn) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteHash(this.BlockHash)
+	sink.WriteUint32(uint32(len(this.Indexes)))
+	for _, index := range this.Indexes {
+		sink.WriteUint32(index)
+	}
+}
+
+func (this *GetBlockTxn) CmdType() string {
+	return comm.GET_BLOCK_TXN_TYPE
+}
+
+//Deserialize message payload
+func (this *GetBlockTxn) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	count, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	if uint64(count)*4 > source.Len() {
+		return io.ErrUnexpectedEOF
+	}
+	this.Indexes = make([]uint32, 0, count)
+	for i := uint32(0); i < count; i++ {
+		index, eof := source.NextUint32()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		this.Indexes = append(this.Indexes, index)
+	}
+	return nil
+}
+
+// BlockTxn responds the transactions requested by GetBlockTxn
+type BlockTxn struct {
+	BlockHash common.Uint256
+	Indexes   []uint32
+	Txs       []*ct.Transaction
+}
+
+//Serialize message payload
+func (this *BlockTxn) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteHash(this.BlockHash)
+	sink.WriteUint32(uint32(len(this.Txs)))
+	for i, tx := range this.Txs {
+		sink.WriteUint32(this.Indexes[i])
+		tx.Serialization(sink)
+	}
+}
+
+func (this *BlockTxn) CmdType() string {
+	return comm.BLOCK_TXN_TYPE
+}
+
+//Deserialize message payload
+func (this *BlockTxn) Deserialization(source *common.ZeroCopySource) error {
+	var eof bool
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	count, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	for i := uint32(0); i < count; i++ {
+		index, eof := source.NextUint32()
+		if eof {
+			return io.ErrUnexpectedEOF
+		}
+		tx := &ct.Transaction{}
+		err := tx.Deserialization(source)
+		if err != nil {
+			return fmt.Errorf("read Transaction error. err:%v", err)
+		}
+		this.Indexes = append(this.Indexes, index)
+		this.Txs = append(this.Txs, tx)
+	}
+	return nil
+}
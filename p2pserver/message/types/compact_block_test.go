@@ -0,0 +1,70 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	ct "github.com/ontio/ontology/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactBlockSerializationDeserialization(t *testing.T) {
+	header := &ct.Header{
+		Version:          1,
+		PrevBlockHash:    common.Uint256{1, 2, 3},
+		TransactionsRoot: common.Uint256{4, 5, 6},
+		Timestamp:        12345678,
+		Height:           100,
+		ConsensusData:    987654321,
+	}
+	msg := &CompactBlock{
+		Header:     header,
+		ShortIDs:   []uint64{1, 2, 0xffffffffffffffff},
+		MerkleRoot: common.Uint256{7, 8, 9},
+	}
+
+	sink := common.NewZeroCopySink(nil)
+	WriteMessage(sink, msg)
+	demsg, _, err := ReadMessage(bytes.NewBuffer(sink.Bytes()))
+	assert.Nil(t, err)
+
+	cmpct := demsg.(*CompactBlock)
+	assert.Equal(t, header.Hash(), cmpct.Header.Hash())
+	assert.Equal(t, msg.ShortIDs, cmpct.ShortIDs)
+	assert.Equal(t, msg.MerkleRoot, cmpct.MerkleRoot)
+}
+
+func TestGetBlockTxnSerializationDeserialization(t *testing.T) {
+	msg := &GetBlockTxn{
+		BlockHash: common.Uint256{1, 2, 3},
+		Indexes:   []uint32{0, 3, 5},
+	}
+
+	MessageTest(t, msg)
+}
+
+func TestShortTxID(t *testing.T) {
+	txHash := common.Uint256{1}
+	id1 := ShortTxID(common.Uint256{1}, txHash)
+	id2 := ShortTxID(common.Uint256{2}, txHash)
+	assert.NotEqual(t, id1, id2)
+	assert.Equal(t, id1, ShortTxID(common.Uint256{1}, txHash))
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"io"
+
+	comm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+// KeyExchange is sent after version handshake to set up the encrypted link.
+// PubKey is the node's identity key which the peer ID is derived from, and
+// Signature signs the ephemeral key with it
+type KeyExchange struct {
+	PubKey    [32]byte
+	Ephemeral [32]byte
+	Signature [64]byte
+}
+
+//Serialize message payload
+func (this *KeyExchange) Serialization(sink *comm.ZeroCopySink) {
+	sink.WriteBytes(this.PubKey[:])
+	sink.WriteBytes(this.Ephemeral[:])
+	sink.WriteBytes(this.Signature[:])
+}
+
+func (this *KeyExchange) CmdType() string {
+	return common.KEY_EXCHANGE_TYPE
+}
+
+//Deserialize message payload
+func (this *KeyExchange) Deserialization(source *comm.ZeroCopySource) error {
+	buf, eof := source.NextBytes(uint64(len(this.PubKey)))
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	copy(this.PubKey[:], buf)
+	buf, eof = source.NextBytes(uint64(len(this.Ephemeral)))
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	copy(this.Ephemeral[:], buf)
+	buf, eof = source.NextBytes(uint64(len(this.Signature)))
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	copy(this.Signature[:], buf)
+	return nil
+}
@@ -110,12 +110,12 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 
 	magic := config.DefConfig.P2PNode.NetworkMagic
 	if hdr.Magic != magic {
-		return nil, 0, fmt.Errorf("unmatched magic number %d, expected %d", hdr.Magic, magic)
+		return nil, 0, &MalformedMsgError{fmt.Errorf("unmatched magic number %d, expected %d", hdr.Magic, magic)}
 	}
 
 	if hdr.Length > common.MAX_PAYLOAD_LEN {
-		return nil, 0, fmt.Errorf("msg payload length:%d exceed max payload size: %d",
-			hdr.Length, common.MAX_PAYLOAD_LEN)
+		return nil, 0, &MalformedMsgError{fmt.Errorf("msg payload length:%d exceed max payload size: %d",
+			hdr.Length, common.MAX_PAYLOAD_LEN)}
 	}
 
 	buf := make([]byte, hdr.Length)
@@ -126,20 +126,20 @@ func ReadMessage(reader io.Reader) (Message, uint32, error) {
 
 	checksum := common.Checksum(buf)
 	if checksum != hdr.Checksum {
-		return nil, 0, fmt.Errorf("message checksum mismatch: %x != %x ", hdr.Checksum, checksum)
+		return nil, 0, &MalformedMsgError{fmt.Errorf("message checksum mismatch: %x != %x ", hdr.Checksum, checksum)}
 	}
 
 	cmdType := string(bytes.TrimRight(hdr.CMD[:], string(0)))
 	msg, err := MakeEmptyMessage(cmdType)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, &MalformedMsgError{err}
 	}
 
 	// the buf is referenced by msg to avoid reallocation, so can not reused
 	source := comm.NewZeroCopySource(buf)
 	err = msg.Deserialization(source)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, &MalformedMsgError{err}
 	}
 
 	return msg, hdr.Length, nil
@@ -179,6 +179,18 @@ func MakeEmptyMessage(cmdType string) (Message, error) {
 		return &Disconnected{}, nil
 	case common.GET_BLOCKS_TYPE:
 		return &BlocksReq{}, nil
+	case common.CMPCT_BLOCK_TYPE:
+		return &CompactBlock{}, nil
+	case common.GET_BLOCK_TXN_TYPE:
+		return &GetBlockTxn{}, nil
+	case common.BLOCK_TXN_TYPE:
+		return &BlockTxn{}, nil
+	case common.KEY_EXCHANGE_TYPE:
+		return &KeyExchange{}, nil
+	case common.GET_STATE_TYPE:
+		return &GetStateValue{}, nil
+	case common.STATE_TYPE:
+		return &StateValue{}, nil
 	default:
 		return nil, errors.New("unsupported cmd type:" + cmdType)
 	}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	comm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+// Misbehavior is raised by link when the peer misbehaves, it is never sent
+// over network
+type Misbehavior struct {
+	Score  uint32
+	Reason string
+}
+
+//Serialize message payload
+func (this *Misbehavior) Serialization(sink *comm.ZeroCopySink) {
+}
+
+func (this *Misbehavior) CmdType() string {
+	return common.MISBEHAVIOR_TYPE
+}
+
+//Deserialize message payload
+func (this *Misbehavior) Deserialization(source *comm.ZeroCopySource) error {
+	return nil
+}
+
+// MalformedMsgError is returned by ReadMessage if the message received is
+// invalid, differing from the error of reading connection
+type MalformedMsgError struct {
+	Err error
+}
+
+func (this *MalformedMsgError) Error() string {
+	return this.Err.Error()
+}
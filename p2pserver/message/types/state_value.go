@@ -0,0 +1,144 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"io"
+
+	"github.com/ontio/ontology/common"
+	comm "github.com/ontio/ontology/p2pserver/common"
+)
+
+// GetStateValue requests the storage value of a contract from full peer
+type GetStateValue struct {
+	ReqID    uint64
+	Contract common.Address
+	Key      []byte
+}
+
+//Serialize message payload
+func (this *GetStateValue) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ReqID)
+	sink.WriteAddress(this.Contract)
+	sink.WriteVarBytes(this.Key)
+}
+
+func (this *GetStateValue) CmdType() string {
+	return comm.GET_STATE_TYPE
+}
+
+//Deserialize message payload
+func (this *GetStateValue) Deserialization(source *common.ZeroCopySource) error {
+	var eof, irregular bool
+	this.ReqID, eof = source.NextUint64()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Contract, eof = source.NextAddress()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Key, _, irregular, eof = source.NextVarBytes()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// StateValue responds the storage value at the block of the full peer. It
+// carries no proof: the state is not committed in block header, so the value
+// is only as trustworthy as the peer sent it. The light client accepts the
+// value only if the block hash matches its verified header chain and enough
+// peers return the same value
+type StateValue struct {
+	ReqID     uint64
+	Contract  common.Address
+	Key       []byte
+	Height    uint32
+	BlockHash common.Uint256
+	Exist     bool
+	Value     []byte
+}
+
+// StateValueFrom is the state value with the id of the peer sent it
+type StateValueFrom struct {
+	FromID uint64
+	State  *StateValue
+}
+
+//Serialize message payload
+func (this *StateValue) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ReqID)
+	sink.WriteAddress(this.Contract)
+	sink.WriteVarBytes(this.Key)
+	sink.WriteUint32(this.Height)
+	sink.WriteHash(this.BlockHash)
+	sink.WriteBool(this.Exist)
+	sink.WriteVarBytes(this.Value)
+}
+
+func (this *StateValue) CmdType() string {
+	return comm.STATE_TYPE
+}
+
+//Deserialize message payload
+func (this *StateValue) Deserialization(source *common.ZeroCopySource) error {
+	var eof, irregular bool
+	this.ReqID, eof = source.NextUint64()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Contract, eof = source.NextAddress()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Key, _, irregular, eof = source.NextVarBytes()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Height, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.BlockHash, eof = source.NextHash()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Exist, irregular, eof = source.NextBool()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Value, _, irregular, eof = source.NextVarBytes()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
@@ -19,6 +19,7 @@
 package utils
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
@@ -32,12 +33,15 @@ import (
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/core/ledger"
+	scom "github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/types"
+	ontErrors "github.com/ontio/ontology/errors"
 	actor "github.com/ontio/ontology/p2pserver/actor/req"
 	msgCommon "github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/message/msg_pack"
 	msgTypes "github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/net/protocol"
+	tc "github.com/ontio/ontology/txnpool/common"
 )
 
 //respCache cache for some response data
@@ -47,6 +51,30 @@ var respCache *lru.ARCCache
 // thread safe
 var txCache, _ = lru.NewARC(msgCommon.MAX_TX_CACHE_SIZE)
 
+//Store txHash and request time of the tx requested by inv, avoid fetching
+//the same tx from several peers at once
+var txReqCache, _ = lru.NewARC(msgCommon.MAX_TX_CACHE_SIZE)
+
+//Store txHash and the id of peer which relayed the tx, the peer is penalized
+//if the tx fails to verify
+var txPeerCache, _ = lru.NewARC(msgCommon.MAX_TX_CACHE_SIZE)
+
+//Store the compact blocks waiting for missing txs, keyed by block hash
+var cmpctBlkCache, _ = lru.NewARC(msgCommon.MAX_CMPCT_BLK_CNT)
+
+//pendingCmpctBlock is a compact block partly rebuilt from tx pool
+type pendingCmpctBlock struct {
+	fromID     uint64
+	header     *types.Header
+	merkleRoot common.Uint256
+	txs        []*types.Transaction
+}
+
+//MarkTxReceived record the tx as received, inv of it will be ignored
+func MarkTxReceived(hash common.Uint256) {
+	txCache.Add(hash, nil)
+}
+
 // AddrReqHandle handles the neighbor address request from peer
 func AddrReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
 	log.Trace("[p2p]receive addr request message", data.Addr, data.Id)
@@ -182,6 +210,7 @@ func BlockHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args
 			log.Info("received block msg with empty merkle root")
 			remotePeer := p2p.GetPeer(data.Id)
 			if remotePeer != nil {
+				p2p.Misbehave(remotePeer, msgCommon.MALFORMED_MSG_SCORE, "block with empty merkle root")
 				remotePeer.Close()
 			}
 
@@ -198,6 +227,183 @@ func BlockHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args
 	}
 }
 
+// CompactBlockHandle handles the compact block message from peer, the block
+// is rebuilt from tx pool and the missing txs are requested from the peer
+func CompactBlockHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive compact block message from ", data.Addr, data.Id)
+
+	if pid == nil {
+		return
+	}
+	var cmpct = data.Payload.(*msgTypes.CompactBlock)
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in CompactBlockHandle")
+		return
+	}
+	stateHashHeight := config.GetStateHashCheckHeight(config.DefConfig.P2PNode.NetworkId)
+	if cmpct.Header.Height >= stateHashHeight && cmpct.MerkleRoot == common.UINT256_EMPTY {
+		log.Info("received compact block msg with empty merkle root")
+		p2p.Misbehave(remotePeer, msgCommon.MALFORMED_MSG_SCORE, "compact block with empty merkle root")
+		remotePeer.Close()
+		return
+	}
+
+	blockHash := cmpct.Header.Hash()
+	pool := make(map[uint64]*types.Transaction)
+	txs, err := actor.GetTxnList()
+	if err != nil {
+		log.Debugf("[p2p]failed to get tx list from pool: %s", err)
+	}
+	for _, tx := range txs {
+		pool[msgTypes.ShortTxID(blockHash, tx.Hash())] = tx
+	}
+
+	pending := &pendingCmpctBlock{
+		fromID:     data.Id,
+		header:     cmpct.Header,
+		merkleRoot: cmpct.MerkleRoot,
+		txs:        make([]*types.Transaction, len(cmpct.ShortIDs)),
+	}
+	var missing []uint32
+	for i, id := range cmpct.ShortIDs {
+		if tx, ok := pool[id]; ok {
+			pending.txs[i] = tx
+		} else {
+			missing = append(missing, uint32(i))
+		}
+	}
+	if len(missing) == 0 {
+		appendCmpctBlock(pending, p2p, pid)
+		return
+	}
+
+	log.Debugf("[p2p]compact block %s missing %d txs", blockHash.ToHexString(), len(missing))
+	cmpctBlkCache.Add(blockHash, pending)
+	msg := msgpack.NewGetBlockTxn(blockHash, missing)
+	err = p2p.Send(remotePeer, msg)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+}
+
+// GetBlockTxnHandle handles the missing txs request of compact block from peer
+func GetBlockTxnHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive get block txn message", data.Addr, data.Id)
+
+	var req = data.Payload.(*msgTypes.GetBlockTxn)
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in GetBlockTxnHandle")
+		return
+	}
+	block, err := ledger.DefLedger.GetBlockByHash(req.BlockHash)
+	if err != nil || block == nil || block.Header == nil {
+		log.Debug("[p2p]can't get block by hash: ", req.BlockHash,
+			" ,send not found message")
+		msg := msgpack.NewNotFound(req.BlockHash)
+		err := p2p.Send(remotePeer, msg)
+		if err != nil {
+			log.Warn(err)
+		}
+		return
+	}
+	txs := make([]*types.Transaction, 0, len(req.Indexes))
+	for _, index := range req.Indexes {
+		if int(index) >= len(block.Transactions) {
+			log.Debugf("[p2p]invalid tx index %d of block %s", index, req.BlockHash.ToHexString())
+			p2p.Misbehave(remotePeer, msgCommon.MALFORMED_MSG_SCORE, "invalid block txn request")
+			return
+		}
+		txs = append(txs, block.Transactions[index])
+	}
+	msg := msgpack.NewBlockTxn(req.BlockHash, req.Indexes, txs)
+	err = p2p.Send(remotePeer, msg)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+}
+
+// BlockTxnHandle handles the missing txs of compact block from peer
+func BlockTxnHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive block txn message", data.Addr, data.Id)
+
+	if pid == nil {
+		return
+	}
+	var blkTxn = data.Payload.(*msgTypes.BlockTxn)
+	value, ok := cmpctBlkCache.Get(blkTxn.BlockHash)
+	if !ok {
+		log.Debug("[p2p]receive block txn of unknown compact block ", blkTxn.BlockHash)
+		return
+	}
+	pending := value.(*pendingCmpctBlock)
+	if pending.fromID != data.Id {
+		log.Debug("[p2p]receive block txn from unexpected peer ", data.Id)
+		return
+	}
+	cmpctBlkCache.Remove(blkTxn.BlockHash)
+
+	for i, index := range blkTxn.Indexes {
+		if int(index) >= len(pending.txs) {
+			log.Debugf("[p2p]invalid tx index %d of block %s", index, blkTxn.BlockHash.ToHexString())
+			p2p.Misbehave(p2p.GetPeer(data.Id), msgCommon.MALFORMED_MSG_SCORE, "invalid block txn")
+			requestFullBlock(data.Id, blkTxn.BlockHash, p2p)
+			return
+		}
+		pending.txs[index] = blkTxn.Txs[i]
+	}
+	for _, tx := range pending.txs {
+		if tx == nil {
+			requestFullBlock(data.Id, blkTxn.BlockHash, p2p)
+			return
+		}
+	}
+	appendCmpctBlock(pending, p2p, pid)
+}
+
+//appendCmpctBlock check the rebuilt block and send it to sync, fallback to
+//fetch full block if the tx root mismatched
+func appendCmpctBlock(pending *pendingCmpctBlock, p2p p2p.P2P, pid *evtActor.PID) {
+	blockHash := pending.header.Hash()
+	hashes := make([]common.Uint256, 0, len(pending.txs))
+	for _, tx := range pending.txs {
+		hashes = append(hashes, tx.Hash())
+	}
+	if common.ComputeMerkleRoot(hashes) != pending.header.TransactionsRoot {
+		log.Debugf("[p2p]compact block %s rebuilt with mismatched tx root", blockHash.ToHexString())
+		requestFullBlock(pending.fromID, blockHash, p2p)
+		return
+	}
+
+	block := &types.Block{
+		Header:       pending.header,
+		Transactions: pending.txs,
+	}
+	input := &msgCommon.AppendBlock{
+		FromID:     pending.fromID,
+		BlockSize:  uint32(len(block.ToArray())),
+		Block:      block,
+		MerkleRoot: pending.merkleRoot,
+	}
+	pid.Tell(input)
+}
+
+//requestFullBlock request the full block from peer
+func requestFullBlock(peerID uint64, hash common.Uint256, p2p p2p.P2P) {
+	remotePeer := p2p.GetPeer(peerID)
+	if remotePeer == nil {
+		return
+	}
+	msg := msgpack.NewBlkDataReq(hash)
+	err := p2p.Send(remotePeer, msg)
+	if err != nil {
+		log.Warn(err)
+	}
+}
+
 // ConsensusHandle handles the consensus message from peer
 func ConsensusHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
 	log.Debugf("[p2p]receive consensus message:%v,%d", data.Addr, data.Id)
@@ -225,8 +431,13 @@ func TransactionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID
 
 	var trn = data.Payload.(*msgTypes.Trn)
 
+	if remotePeer := p2p.GetPeer(data.Id); remotePeer != nil {
+		remotePeer.MarkKnownTx(trn.Txn.Hash())
+	}
+	txReqCache.Remove(trn.Txn.Hash())
 	if !txCache.Contains(trn.Txn.Hash()) {
 		txCache.Add(trn.Txn.Hash(), nil)
+		txPeerCache.Add(trn.Txn.Hash(), data.Id)
 		actor.AddTransaction(trn.Txn)
 	} else {
 		log.Tracef("[p2p]receive duplicate Transaction message, txHash: %x\n", trn.Txn.Hash())
@@ -253,21 +464,30 @@ func VersionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 	}
 	nodeAddr := addrIp + ":" +
 		strconv.Itoa(int(version.P.SyncPort))
-	if config.DefConfig.P2PNode.ReservedPeersOnly && len(config.DefConfig.P2PNode.ReservedCfg.ReservedPeers) > 0 {
-		found := false
-		for _, addr := range config.DefConfig.P2PNode.ReservedCfg.ReservedPeers {
-			if strings.HasPrefix(data.Addr, addr) {
-				log.Debug("[p2p]peer in reserved list", data.Addr)
-				found = true
-				break
-			}
+	if p2p.IsBanned(version.P.Nonce, data.Addr) || p2p.IsBanned(0, nodeAddr) {
+		remotePeer.Close()
+		log.Debug("[p2p]peer is banned,close", data.Addr)
+		return
+	}
+	secure := version.P.Cap[msgCommon.SECURE_LINK_FLAG] == 0x01 && remotePeer.Link.SecureSupported()
+	if !secure && remotePeer.Link.SecureSupported() {
+		//the capability is not authenticated, a peer without it may be downgraded
+		if config.DefConfig.P2PNode.RequireSecureLink {
+			remotePeer.Close()
+			log.Warnf("[p2p]peer %s not support encrypted link,close", data.Addr)
+			return
 		}
-		if !found {
+		log.Warnf("[p2p]peer %s not support encrypted link, link downgraded to plain", data.Addr)
+	}
+	rsvCfg := config.DefConfig.P2PNode.ReservedCfg
+	if config.DefConfig.P2PNode.ReservedPeersOnly && (len(rsvCfg.ReservedPeers) > 0 || len(rsvCfg.ReservedPubKeys) > 0) &&
+		!isReservedAddr(data.Addr) {
+		//peer not in reserved address list must be authenticated by node key
+		if !secure || len(rsvCfg.ReservedPubKeys) == 0 {
 			remotePeer.Close()
 			log.Debug("[p2p]peer not in reserved list,close", data.Addr)
 			return
 		}
-
 	}
 
 	if version.P.Nonce == p2p.GetID() {
@@ -328,6 +548,7 @@ func VersionHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 		remotePeer.SetHttpInfoState(false)
 	}
 	remotePeer.SetHttpInfoPort(version.P.HttpInfoPort)
+	remotePeer.SetSecureLinkState(secure)
 
 	remotePeer.UpdateInfo(time.Now(), version.P.Version,
 		version.P.Services, version.P.SyncPort, version.P.Nonce,
@@ -370,6 +591,19 @@ func VerAckHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, arg
 	}
 
 	s := remotePeer.GetState()
+	if remotePeer.GetSecureLinkState() {
+		//the link is established after key exchange, which is started by the
+		//peer receiving verack first
+		if s == msgCommon.HAND_SHAKE {
+			p2p.Send(remotePeer, msgpack.NewVerAck())
+			remotePeer.SetState(msgCommon.SECURE_HAND)
+			if err := remotePeer.Link.StartSecure(); err != nil {
+				log.Warnf("[p2p]start key exchange with %s failed: %s", data.Addr, err)
+				remotePeer.Close()
+			}
+		}
+		return
+	}
 	if s != msgCommon.HAND_SHAKE && s != msgCommon.HAND_SHAKED {
 		log.Warnf("[p2p]unknown status to received verAck,state:%d,%s\n", s, data.Addr)
 		return
@@ -389,11 +623,51 @@ func VerAckHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, arg
 
 }
 
-// AddrHandle handles the neighbor address response message from peer
+// KeyExchangeHandle handles the key exchange from peer, the link is already
+// switched to encrypted and the node key of peer authenticated by link layer
+func KeyExchangeHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive key exchange message from ", data.Addr, data.Id)
+
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Warn("[p2p]nbr node is not exist", data.Id, data.Addr)
+		return
+	}
+
+	s := remotePeer.GetState()
+	if s != msgCommon.SECURE_HAND && s != msgCommon.HAND_SHAKED {
+		log.Warnf("[p2p]unknown status to received key exchange,state:%d,%s\n", s, data.Addr)
+		return
+	}
+	pubKey := remotePeer.GetNodePubKey()
+	if pubKey == nil {
+		log.Warnf("[p2p]link with %s not encrypted after key exchange", data.Addr)
+		remotePeer.Close()
+		return
+	}
+	if config.DefConfig.P2PNode.ReservedPeersOnly && len(config.DefConfig.P2PNode.ReservedCfg.ReservedPubKeys) > 0 &&
+		!isReservedAddr(data.Addr) && !isReservedPubKey(pubKey) {
+		log.Debugf("[p2p]peer key %x not in reserved list,close %s", pubKey, data.Addr)
+		remotePeer.Close()
+		return
+	}
+
+	remotePeer.SetState(msgCommon.ESTABLISH)
+	p2p.RemoveFromConnectingList(data.Addr)
+	log.Infof("[p2p]encrypted link established with %s, node key %x", data.Addr, pubKey)
+	remotePeer.DumpInfo()
+
+	msg := msgpack.NewAddrReq()
+	go p2p.Send(remotePeer, msg)
+}
+
+// AddrHandle handles the neighbor address response message from peer, the
+// addresses are sent to address book
 func AddrHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
 	log.Trace("[p2p]handle addr message", data.Addr, data.Id)
 
 	var msg = data.Payload.(*msgTypes.Addr)
+	addrs := make([]string, 0, len(msg.NodeAddrs))
 	for _, v := range msg.NodeAddrs {
 		var ip net.IP
 		ip = v.IpAddr[:]
@@ -403,22 +677,17 @@ func AddrHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args
 			continue
 		}
 
-		if p2p.NodeEstablished(v.ID) {
-			continue
-		}
-
-		if ret := p2p.GetPeerFromAddr(address); ret != nil {
-			continue
-		}
-
 		if v.Port == 0 {
 			continue
 		}
-		if p2p.IsAddrFromConnecting(address) {
-			continue
+		addrs = append(addrs, address)
+	}
+	if pid != nil && len(addrs) > 0 {
+		input := &msgCommon.AppendAddrs{
+			FromAddr: data.Addr,
+			Addrs:    addrs,
 		}
-		log.Debug("[p2p]connect ip address:", address)
-		go p2p.Connect(address)
+		pid.Tell(input)
 	}
 }
 
@@ -436,7 +705,7 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 	reqType := common.InventoryType(dataReq.DataType)
 	hash := dataReq.Hash
 	switch reqType {
-	case common.BLOCK:
+	case common.BLOCK, common.COMPACT_BLOCK:
 		reqID := fmt.Sprintf("%x%s", reqType, hash.ToHexString())
 		data := getRespCacheValue(reqID)
 		var msg msgTypes.Message
@@ -444,6 +713,8 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 			switch data.(type) {
 			case *msgTypes.Block:
 				msg = data.(*msgTypes.Block)
+			case *msgTypes.CompactBlock:
+				msg = data.(*msgTypes.CompactBlock)
 			}
 		}
 		if msg == nil {
@@ -472,7 +743,11 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 				}
 				return
 			}
-			msg = msgpack.NewBlock(block, merkleRoot)
+			if reqType == common.COMPACT_BLOCK {
+				msg = msgpack.NewCompactBlock(block, merkleRoot)
+			} else {
+				msg = msgpack.NewBlock(block, merkleRoot)
+			}
 			saveRespCache(reqID, msg)
 		}
 		err := p2p.Send(remotePeer, msg)
@@ -482,17 +757,22 @@ func DataReqHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, ar
 		}
 
 	case common.TRANSACTION:
-		txn, err := ledger.DefLedger.GetTransaction(hash)
-		if err != nil {
+		// the announced tx is usually still pending in tx pool
+		txn, err := actor.GetTransaction(hash)
+		if err != nil || txn == nil {
+			txn, err = ledger.DefLedger.GetTransaction(hash)
+		}
+		if err != nil || txn == nil {
 			log.Debug("[p2p]Can't get transaction by hash: ",
 				hash, " ,send not found message")
 			msg := msgpack.NewNotFound(hash)
 			err = p2p.Send(remotePeer, msg)
 			if err != nil {
 				log.Warn(err)
-				return
 			}
+			return
 		}
+		remotePeer.MarkKnownTx(hash)
 		msg := msgpack.NewTxn(txn)
 		err = p2p.Send(remotePeer, msg)
 		if err != nil {
@@ -523,13 +803,28 @@ func InvHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args .
 		inv.P.InvType, len(inv.P.Blk), str)
 
 	invType := common.InventoryType(inv.P.InvType)
+	if config.DefConfig.P2PNode.LightClient && invType != common.CONSENSUS {
+		//light client syncs headers only and can not verify txs
+		return
+	}
 	switch invType {
 	case common.TRANSACTION:
-		log.Debug("[p2p]receive transaction message", id)
-		// TODO check the ID queue
-		id = inv.P.Blk[0]
-		trn, err := ledger.DefLedger.GetTransaction(id)
-		if trn == nil || err != nil {
+		log.Debug("[p2p]receive transaction inv message")
+		for _, id = range inv.P.Blk {
+			remotePeer.MarkKnownTx(id)
+			if txCache.Contains(id) {
+				continue
+			}
+			if reqTime, ok := txReqCache.Get(id); ok &&
+				time.Since(reqTime.(time.Time)) < msgCommon.TX_REQ_TIMEOUT*time.Second {
+				continue
+			}
+			trn, err := ledger.DefLedger.GetTransaction(id)
+			if trn != nil && err == nil {
+				txCache.Add(id, nil)
+				continue
+			}
+			txReqCache.Add(id, time.Now())
 			msg := msgpack.NewTxnDataReq(id)
 			err = p2p.Send(remotePeer, msg)
 			if err != nil {
@@ -551,7 +846,12 @@ func InvHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args .
 				msgTypes.LastInvHash = id
 				// send the block request
 				log.Infof("[p2p]inv request block hash: %x", id)
-				msg := msgpack.NewBlkDataReq(id)
+				var msg msgTypes.Message
+				if remotePeer.GetServices()&msgCommon.COMPACT_BLOCK_NODE != 0 {
+					msg = msgpack.NewCmpctBlkDataReq(id)
+				} else {
+					msg = msgpack.NewBlkDataReq(id)
+				}
 				err = p2p.Send(remotePeer, msg)
 				if err != nil {
 					log.Warn(err)
@@ -574,6 +874,58 @@ func InvHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args .
 
 }
 
+// GetStateValueHandle handles the state value request from light peer
+func GetStateValueHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive get state value message", data.Addr, data.Id)
+
+	if config.DefConfig.P2PNode.LightClient {
+		return
+	}
+	var req = data.Payload.(*msgTypes.GetStateValue)
+	remotePeer := p2p.GetPeer(data.Id)
+	if remotePeer == nil {
+		log.Debug("[p2p]remotePeer invalid in GetStateValueHandle")
+		return
+	}
+
+	var height uint32
+	var value []byte
+	var err error
+	for i := 0; i < 3; i++ {
+		//make sure the value is read at the height
+		height = ledger.DefLedger.GetCurrentBlockHeight()
+		value, err = ledger.DefLedger.GetStorageItem(req.Contract, req.Key)
+		if height == ledger.DefLedger.GetCurrentBlockHeight() {
+			break
+		}
+	}
+	if err != nil && err != scom.ErrNotFound {
+		log.Warnf("[p2p]failed to get storage for state value: %s", err)
+		return
+	}
+	exist := err == nil && value != nil
+	msg := msgpack.NewStateValue(req, height, ledger.DefLedger.GetBlockHash(height), exist, value)
+	err = p2p.Send(remotePeer, msg)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+}
+
+// StateValueHandle handles the state value from full peer
+func StateValueHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	log.Trace("[p2p]receive state value message", data.Addr, data.Id)
+
+	if pid != nil {
+		var state = data.Payload.(*msgTypes.StateValue)
+		input := &msgTypes.StateValueFrom{
+			FromID: data.Id,
+			State:  state,
+		}
+		pid.Tell(input)
+	}
+}
+
 // DisconnectHandle handles the disconnect events
 func DisconnectHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
 	log.Debug("[p2p]receive disconnect message", data.Addr, data.Id)
@@ -592,6 +944,32 @@ func DisconnectHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID,
 	}
 }
 
+// MisbehaviorHandle handles the misbehavior events raised by link
+func MisbehaviorHandle(data *msgTypes.MsgPayload, p2p p2p.P2P, pid *evtActor.PID, args ...interface{}) {
+	var misbehavior = data.Payload.(*msgTypes.Misbehavior)
+	log.Debug("[p2p]receive misbehavior message", data.Addr, data.Id, misbehavior.Reason)
+	remotePeer := p2p.GetPeerFromAddr(data.Addr)
+	if remotePeer == nil {
+		log.Debug("[p2p]misbehavior peer is nil")
+		return
+	}
+	p2p.Misbehave(remotePeer, misbehavior.Score, misbehavior.Reason)
+}
+
+// TxResultHandle handles the verify result of tx from network, the peer
+// relayed the tx with invalid signature or payload is penalized
+func TxResultHandle(result *tc.TxResult, p2p p2p.P2P) {
+	value, ok := txPeerCache.Get(result.Hash)
+	if !ok {
+		return
+	}
+	txPeerCache.Remove(result.Hash)
+	if result.Err != ontErrors.ErrVerifySignature && result.Err != ontErrors.ErrTransactionPayload {
+		return
+	}
+	p2p.Misbehave(p2p.GetPeer(value.(uint64)), msgCommon.INVALID_TX_SCORE, "invalid tx: "+result.Desc)
+}
+
 //get blk hdrs from starthash to stophash
 func GetHeadersFromHash(startHash common.Uint256, stopHash common.Uint256) ([]*types.RawHeader, error) {
 	var count uint32 = 0
@@ -696,3 +1074,25 @@ func saveRespCache(key string, value interface{}) bool {
 	respCache.Add(key, value)
 	return true
 }
+
+//isReservedAddr return whether the address is in reserved peer list
+func isReservedAddr(addr string) bool {
+	for _, rsvAddr := range config.DefConfig.P2PNode.ReservedCfg.ReservedPeers {
+		if strings.HasPrefix(addr, rsvAddr) {
+			log.Debug("[p2p]peer in reserved list", addr)
+			return true
+		}
+	}
+	return false
+}
+
+//isReservedPubKey return whether the node key is in reserved key list
+func isReservedPubKey(pubKey []byte) bool {
+	key := hex.EncodeToString(pubKey)
+	for _, rsvKey := range config.DefConfig.P2PNode.ReservedCfg.ReservedPubKeys {
+		if strings.EqualFold(key, rsvKey) {
+			return true
+		}
+	}
+	return false
+}
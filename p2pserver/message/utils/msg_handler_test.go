@@ -38,6 +38,7 @@ import (
 	ct "github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/events"
 	msgCommon "github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/link"
 	"github.com/ontio/ontology/p2pserver/message/msg_pack"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/net/netserver"
@@ -180,6 +181,62 @@ func TestVerAckHandle(t *testing.T) {
 	network.DelNbrNode(testID)
 }
 
+// TestKeyExchangeHandle tests the encrypted link is started by the version
+// ack and established by Function KeyExchangeHandle
+func TestKeyExchangeHandle(t *testing.T) {
+	localKey, err := link.GenerateNodeKey()
+	assert.Nil(t, err)
+	remoteKey, err := link.GenerateNodeKey()
+	assert.Nil(t, err)
+	testID := remoteKey.PeerID()
+	conn1, conn2 := net.Pipe()
+	defer conn1.Close()
+	defer conn2.Close()
+
+	// Simulate a remote peer agreed to encrypt the link
+	remotePeer := peer.NewPeer()
+	remotePeer.UpdateInfo(time.Now(), 1, 12345678, 20336, testID, 0, 12345, "1.5.2")
+	remotePeer.Link.SetID(testID)
+	remotePeer.Link.SetConn(conn1)
+	remotePeer.Link.SetNodeKey(localKey)
+	recvChan := make(chan *types.MsgPayload, 10)
+	remotePeer.Link.SetChan(recvChan)
+	remotePeer.SetSecureLinkState(true)
+	remotePeer.SetState(msgCommon.HAND_SHAKE)
+	network.AddNbrNode(remotePeer)
+	defer network.DelNbrNode(testID)
+	go remotePeer.Link.Rx()
+
+	// The link of the remote node replies the key exchange
+	remoteLink := link.NewLink()
+	remoteLink.SetID(localKey.PeerID())
+	remoteLink.SetConn(conn2)
+	remoteLink.SetNodeKey(remoteKey)
+	remoteLink.SetChan(make(chan *types.MsgPayload, 10))
+	go remoteLink.Rx()
+
+	msg := &types.MsgPayload{
+		Id:      testID,
+		Addr:    "127.0.0.1:50010",
+		Payload: msgpack.NewVerAck(),
+	}
+	VerAckHandle(msg, network, nil)
+	assert.Equal(t, uint32(msgCommon.SECURE_HAND), remotePeer.GetState())
+
+	var payload *types.MsgPayload
+	select {
+	case payload = <-recvChan:
+	case <-time.After(time.Second):
+		t.Fatal("key exchange not received")
+	}
+	assert.Equal(t, msgCommon.KEY_EXCHANGE_TYPE, payload.Payload.CmdType())
+	assert.True(t, remotePeer.Link.IsSecure())
+
+	KeyExchangeHandle(payload, network, nil)
+	assert.Equal(t, uint32(msgCommon.ESTABLISH), remotePeer.GetState())
+	assert.True(t, bytes.Equal(remoteKey.PublicKey(), remotePeer.GetNodePubKey()))
+}
+
 // TestAddrReqHandle tests Function AddrReqHandle handling an address req
 // testcase: no-mask neighbor
 func TestAddrReqHandle(t *testing.T) {
@@ -700,6 +757,54 @@ func TestInvHandle(t *testing.T) {
 	network.DelNbrNode(testID)
 }
 
+// TestTxInvHandle tests Function InvHandle requesting an announced transaction only once
+func TestTxInvHandle(t *testing.T) {
+	var testID uint64
+	_, testPub, _ := keypair.GenerateKeyPair(keypair.PK_ECDSA, keypair.P256)
+	key := keypair.SerializePublicKey(testPub)
+	err := binary.Read(bytes.NewBuffer(key[:8]), binary.LittleEndian, &(testID))
+	assert.Nil(t, err)
+
+	remotePeer := peer.NewPeer()
+	assert.NotNil(t, remotePeer)
+	remotePeer.UpdateInfo(time.Now(), 1, 12345678, 20336,
+		testID, 0, 12345, "1.5.2")
+	remotePeer.Link.SetAddr("127.0.0.1:50010")
+
+	network.AddNbrNode(remotePeer)
+
+	txHash := common.Uint256{0x7a, 0x11}
+	invPayload := msgpack.NewInvPayload(common.TRANSACTION, []common.Uint256{txHash})
+	msg := &types.MsgPayload{
+		Id:      testID,
+		Addr:    "127.0.0.1:50010",
+		Payload: msgpack.NewInv(invPayload),
+	}
+
+	sent := len(network.SentMsgs)
+	InvHandle(msg, network, nil)
+	assert.True(t, remotePeer.IsKnownTx(txHash))
+	assert.Equal(t, sent+1, len(network.SentMsgs))
+	dataReq, ok := network.SentMsgs[sent].(*types.DataReq)
+	assert.True(t, ok)
+	assert.Equal(t, common.TRANSACTION, dataReq.DataType)
+	assert.Equal(t, txHash, dataReq.Hash)
+
+	// the tx is being requested, don`t fetch it again
+	InvHandle(msg, network, nil)
+	assert.Equal(t, sent+1, len(network.SentMsgs))
+
+	// the tx has been received, don`t fetch it at all
+	received := common.Uint256{0x7a, 0x12}
+	MarkTxReceived(received)
+	invPayload = msgpack.NewInvPayload(common.TRANSACTION, []common.Uint256{received})
+	msg.Payload = msgpack.NewInv(invPayload)
+	InvHandle(msg, network, nil)
+	assert.Equal(t, sent+1, len(network.SentMsgs))
+
+	network.DelNbrNode(testID)
+}
+
 // TestDisconnectHandle tests Function DisconnectHandle handling a disconnect event
 func TestDisconnectHandle(t *testing.T) {
 	var testID uint64
@@ -21,9 +21,11 @@ package utils
 import (
 	"github.com/ontio/ontology-eventbus/actor"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/actor/req"
 	msgCommon "github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/net/protocol"
+	tc "github.com/ontio/ontology/txnpool/common"
 )
 
 // MessageHandler defines the unified api for each net message
@@ -35,6 +37,7 @@ type MessageRouter struct {
 	msgHandlers map[string]MessageHandler // Msg handler mapped to msg type
 	RecvChan    chan *types.MsgPayload    // The channel to handle sync msg
 	stopRecvCh  chan bool                 // To stop sync channel
+	stopTxCh    chan bool                 // To stop tx result channel
 	p2p         p2p.P2P                   // Refer to the p2p network
 	pid         *actor.PID                // P2P actor
 }
@@ -51,6 +54,7 @@ func (this *MessageRouter) init(p2p p2p.P2P) {
 	this.msgHandlers = make(map[string]MessageHandler)
 	this.RecvChan = p2p.GetMsgChan()
 	this.stopRecvCh = make(chan bool)
+	this.stopTxCh = make(chan bool)
 	this.p2p = p2p
 
 	// Register message handler
@@ -69,6 +73,13 @@ func (this *MessageRouter) init(p2p p2p.P2P) {
 	this.RegisterMsgHandler(msgCommon.NOT_FOUND_TYPE, NotFoundHandle)
 	this.RegisterMsgHandler(msgCommon.TX_TYPE, TransactionHandle)
 	this.RegisterMsgHandler(msgCommon.DISCONNECT_TYPE, DisconnectHandle)
+	this.RegisterMsgHandler(msgCommon.CMPCT_BLOCK_TYPE, CompactBlockHandle)
+	this.RegisterMsgHandler(msgCommon.GET_BLOCK_TXN_TYPE, GetBlockTxnHandle)
+	this.RegisterMsgHandler(msgCommon.BLOCK_TXN_TYPE, BlockTxnHandle)
+	this.RegisterMsgHandler(msgCommon.KEY_EXCHANGE_TYPE, KeyExchangeHandle)
+	this.RegisterMsgHandler(msgCommon.MISBEHAVIOR_TYPE, MisbehaviorHandle)
+	this.RegisterMsgHandler(msgCommon.GET_STATE_TYPE, GetStateValueHandle)
+	this.RegisterMsgHandler(msgCommon.STATE_TYPE, StateValueHandle)
 }
 
 // RegisterMsgHandler registers msg handler with the msg type
@@ -91,6 +102,7 @@ func (this *MessageRouter) SetPID(pid *actor.PID) {
 // Start starts the loop to handle the message from the network
 func (this *MessageRouter) Start() {
 	go this.hookChan(this.RecvChan, this.stopRecvCh)
+	go this.hookTxResult(req.TxResultChan(), this.stopTxCh)
 	log.Debug("[p2p]MessageRouter start to parse p2p message...")
 }
 
@@ -121,10 +133,28 @@ func (this *MessageRouter) hookChan(channel chan *types.MsgPayload,
 	}
 }
 
+// hookTxResult loops to handle the verify result of tx from the network
+func (this *MessageRouter) hookTxResult(channel <-chan *tc.TxResult,
+	stopCh chan bool) {
+	for {
+		select {
+		case result, ok := <-channel:
+			if ok {
+				TxResultHandle(result, this.p2p)
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
 // Stop stops the message router's loop
 func (this *MessageRouter) Stop() {
 
 	if this.stopRecvCh != nil {
 		this.stopRecvCh <- true
 	}
+	if this.stopTxCh != nil {
+		this.stopTxCh <- true
+	}
 }
@@ -0,0 +1,175 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package netserver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/common"
+)
+
+//BanList record the banned peer ids and ips with their expire time, the
+//list is saved to file on each change so bans persist across restarts
+type BanList struct {
+	sync.RWMutex
+	path string
+	ids  map[uint64]int64 //peer id to expire unix time
+	ips  map[string]int64 //ip to expire unix time
+}
+
+//NewBanList load the ban list from file, an empty list is returned if the
+//file not exist
+func NewBanList(path string) *BanList {
+	this := &BanList{
+		path: path,
+		ids:  make(map[uint64]int64),
+		ips:  make(map[string]int64),
+	}
+	if path == "" {
+		return this
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warnf("[p2p]read ban list %s error: %s", path, err)
+		}
+		return this
+	}
+	var peers []common.BannedPeer
+	if err = json.Unmarshal(data, &peers); err != nil {
+		log.Warnf("[p2p]parse ban list %s error: %s", path, err)
+		return this
+	}
+	now := time.Now().Unix()
+	for _, p := range peers {
+		if p.Expire <= now {
+			continue
+		}
+		if p.IP != "" {
+			this.ips[p.IP] = p.Expire
+		} else {
+			this.ids[p.ID] = p.Expire
+		}
+	}
+	return this
+}
+
+//BanID ban the peer id until expire
+func (this *BanList) BanID(id uint64, expire int64) {
+	this.Lock()
+	defer this.Unlock()
+	this.ids[id] = expire
+	this.save()
+}
+
+//BanIP ban the ip until expire
+func (this *BanList) BanIP(ip string, expire int64) {
+	this.Lock()
+	defer this.Unlock()
+	this.ips[ip] = expire
+	this.save()
+}
+
+//UnbanID remove the peer id from ban list, return false if not banned
+func (this *BanList) UnbanID(id uint64) bool {
+	this.Lock()
+	defer this.Unlock()
+	if _, ok := this.ids[id]; !ok {
+		return false
+	}
+	delete(this.ids, id)
+	this.save()
+	return true
+}
+
+//UnbanIP remove the ip from ban list, return false if not banned
+func (this *BanList) UnbanIP(ip string) bool {
+	this.Lock()
+	defer this.Unlock()
+	if _, ok := this.ips[ip]; !ok {
+		return false
+	}
+	delete(this.ips, ip)
+	this.save()
+	return true
+}
+
+//IsIDBanned return whether the peer id is banned
+func (this *BanList) IsIDBanned(id uint64) bool {
+	this.RLock()
+	defer this.RUnlock()
+	expire, ok := this.ids[id]
+	return ok && expire > time.Now().Unix()
+}
+
+//IsIPBanned return whether the ip is banned
+func (this *BanList) IsIPBanned(ip string) bool {
+	this.RLock()
+	defer this.RUnlock()
+	expire, ok := this.ips[ip]
+	return ok && expire > time.Now().Unix()
+}
+
+//GetBannedPeers return all banned peer ids and ips not expired
+func (this *BanList) GetBannedPeers() []common.BannedPeer {
+	this.RLock()
+	defer this.RUnlock()
+	return this.list()
+}
+
+//list return the entries not expired, called with lock held
+func (this *BanList) list() []common.BannedPeer {
+	now := time.Now().Unix()
+	peers := make([]common.BannedPeer, 0, len(this.ids)+len(this.ips))
+	for id, expire := range this.ids {
+		if expire > now {
+			peers = append(peers, common.BannedPeer{ID: id, Expire: expire})
+		}
+	}
+	for ip, expire := range this.ips {
+		if expire > now {
+			peers = append(peers, common.BannedPeer{IP: ip, Expire: expire})
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Expire < peers[j].Expire
+	})
+	return peers
+}
+
+//save write the ban list to file, called with lock held
+func (this *BanList) save() {
+	if this.path == "" {
+		return
+	}
+	data, err := json.Marshal(this.list())
+	if err != nil {
+		log.Warnf("[p2p]marshal ban list error: %s", err)
+		return
+	}
+	if err = ioutil.WriteFile(this.path, data, 0644); err != nil {
+		log.Warnf("[p2p]save ban list %s error: %s", this.path, err)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package netserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBanListPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "banlist")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "peers.ban")
+
+	expire := time.Now().Add(time.Hour).Unix()
+	list := NewBanList(path)
+	list.BanID(12345, expire)
+	list.BanIP("10.0.0.1", expire)
+	list.BanIP("10.0.0.2", time.Now().Add(-time.Hour).Unix())
+	require.True(t, list.IsIDBanned(12345))
+	require.True(t, list.IsIPBanned("10.0.0.1"))
+	require.False(t, list.IsIPBanned("10.0.0.2"))
+
+	list = NewBanList(path)
+	require.True(t, list.IsIDBanned(12345))
+	require.True(t, list.IsIPBanned("10.0.0.1"))
+	require.False(t, list.IsIPBanned("10.0.0.2"))
+	require.Equal(t, 2, len(list.GetBannedPeers()))
+
+	require.True(t, list.UnbanIP("10.0.0.1"))
+	require.False(t, list.UnbanIP("10.0.0.1"))
+	require.False(t, list.UnbanID(1))
+
+	list = NewBanList(path)
+	require.False(t, list.IsIPBanned("10.0.0.1"))
+	peers := list.GetBannedPeers()
+	require.Equal(t, 1, len(peers))
+	require.Equal(t, uint64(12345), peers[0].ID)
+	require.Equal(t, expire, peers[0].Expire)
+}
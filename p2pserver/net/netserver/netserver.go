@@ -22,6 +22,7 @@ import (
 	"errors"
 	"math/rand"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/ontio/ontology/core/ledger"
 	"github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/common/set"
+	"github.com/ontio/ontology/p2pserver/link"
 	"github.com/ontio/ontology/p2pserver/message/msg_pack"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/net/protocol"
@@ -60,7 +62,9 @@ type NetServer struct {
 	connectLock   sync.Mutex
 	inConnRecord  InConnectionRecord
 	outConnRecord OutConnectionRecord
-	OwnAddress    string //network`s own address(ip : sync port),which get from version check
+	OwnAddress    string        //network`s own address(ip : sync port),which get from version check
+	nodeKey       *link.NodeKey //node key of encrypted link, nil if disabled
+	banList       *BanList      //banned peer ids and ips
 }
 
 //InConnectionRecord include all addr connected
@@ -91,10 +95,12 @@ type PeerAddrMap struct {
 func (this *NetServer) init() error {
 	this.base.SetVersion(common.PROTOCOL_VERSION)
 
-	if config.DefConfig.Consensus.EnableConsensus {
-		this.base.SetServices(uint64(common.VERIFY_NODE))
+	if config.DefConfig.P2PNode.LightClient {
+		this.base.SetServices(uint64(common.SERVICE_NODE | common.LIGHT_NODE))
+	} else if config.DefConfig.Consensus.EnableConsensus {
+		this.base.SetServices(uint64(common.VERIFY_NODE | common.COMPACT_BLOCK_NODE))
 	} else {
-		this.base.SetServices(uint64(common.SERVICE_NODE))
+		this.base.SetServices(uint64(common.SERVICE_NODE | common.COMPACT_BLOCK_NODE))
 	}
 
 	if config.DefConfig.P2PNode.NodePort == 0 {
@@ -108,12 +114,23 @@ func (this *NetServer) init() error {
 
 	rand.Seed(time.Now().UnixNano())
 	id := rand.Uint64()
+	if config.DefConfig.P2PNode.EnableSecureLink {
+		key, err := link.LoadOrCreateNodeKey(config.DefConfig.P2PNode.NodeKeyFile)
+		if err != nil {
+			log.Errorf("[p2p]load node key failed, encrypted link disabled: %s", err)
+		} else {
+			this.nodeKey = key
+			id = key.PeerID()
+			log.Infof("[p2p]node key %x", key.PublicKey())
+		}
+	}
 
 	this.base.SetID(id)
 
 	log.Infof("[p2p]init peer ID to %d", this.base.GetID())
 	this.Np = &peer.NbrPeers{}
 	this.Np.Init()
+	this.banList = NewBanList(config.DefConfig.P2PNode.BanListFile)
 
 	this.connectingNodes.ConnectingAddrs = set.NewStringSet()
 	this.inConnRecord.InConnectingAddrs = set.NewStringSet()
@@ -127,6 +144,11 @@ func (this *NetServer) Start() {
 	this.startListening()
 }
 
+//GetNodeKey return the node key of encrypted link, nil if disabled
+func (this *NetServer) GetNodeKey() *link.NodeKey {
+	return this.nodeKey
+}
+
 //GetVersion return self peer`s version
 func (this *NetServer) GetVersion() uint32 {
 	return this.base.GetVersion()
@@ -257,6 +279,10 @@ func (this *NetServer) Connect(addr string) error {
 	if !this.AddrValid(addr) {
 		return nil
 	}
+	if this.IsBanned(0, addr) {
+		log.Debugf("[p2p]Address: %s is banned", addr)
+		return nil
+	}
 
 	this.connectLock.Lock()
 	connCount := uint(this.GetOutConnRecordLen())
@@ -307,6 +333,7 @@ func (this *NetServer) Connect(addr string) error {
 	this.AddPeerAddress(addr, remotePeer)
 	remotePeer.Link.SetAddr(addr)
 	remotePeer.Link.SetConn(conn)
+	remotePeer.Link.SetNodeKey(this.nodeKey)
 	remotePeer.AttachChan(this.NetChan)
 	go remotePeer.Link.Rx()
 	remotePeer.SetState(common.HAND)
@@ -383,6 +410,12 @@ func (this *NetServer) startNetAccept(listener net.Listener) {
 			continue
 		}
 
+		if this.IsBanned(0, conn.RemoteAddr().String()) {
+			log.Debugf("[p2p]remote %s is banned, close it ", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+
 		if this.IsAddrInInConnRecord(conn.RemoteAddr().String()) {
 			conn.Close()
 			continue
@@ -418,6 +451,7 @@ func (this *NetServer) startNetAccept(listener net.Listener) {
 
 		remotePeer.Link.SetAddr(addr)
 		remotePeer.Link.SetConn(conn)
+		remotePeer.Link.SetNodeKey(this.nodeKey)
 		remotePeer.AttachChan(this.NetChan)
 		go remotePeer.Link.Rx()
 	}
@@ -611,11 +645,86 @@ func (this *NetServer) AddrValid(addr string) bool {
 				return true
 			}
 		}
-		return false
+		//peer from other address could be authenticated by node key
+		return this.nodeKey != nil && len(config.DefConfig.P2PNode.ReservedCfg.ReservedPubKeys) > 0
 	}
 	return true
 }
 
+//Misbehave add misbehavior score to the peer, the peer is banned once its
+//score reach the threshold
+func (this *NetServer) Misbehave(p *peer.Peer, score uint32, reason string) {
+	if p == nil {
+		return
+	}
+	total := p.AddMisbehavior(score)
+	log.Debugf("[p2p]peer %d %s misbehave: %s, score %d", p.GetID(), p.GetAddr(), reason, total)
+	if total < common.MISBEHAVIOR_BAN_SCORE || total-score >= common.MISBEHAVIOR_BAN_SCORE {
+		return
+	}
+	ip, err := common.ParseIPAddr(p.GetAddr())
+	if err == nil && !config.DefConfig.P2PNode.BanWholeIP {
+		//only the node address, other nodes behind the same ip are not affected
+		ip = ip + ":" + strconv.Itoa(int(p.GetPort()))
+	}
+	log.Warnf("[p2p]ban peer %d %s: %s", p.GetID(), ip, reason)
+	this.BanPeer(p.GetID(), ip, time.Duration(config.DefConfig.P2PNode.BanDuration)*time.Second)
+}
+
+//BanPeer ban the peer id and ip or node address(ip:port) for the duration,
+//zero id or empty ip is ignored. The connected peers banned are closed
+func (this *NetServer) BanPeer(id uint64, ip string, duration time.Duration) {
+	expire := time.Now().Add(duration).Unix()
+	if id != 0 {
+		this.banList.BanID(id, expire)
+	}
+	if ip != "" {
+		this.banList.BanIP(ip, expire)
+	}
+
+	this.PeerAddrMap.RLock()
+	var banned []*peer.Peer
+	for addr, p := range this.PeerAddress {
+		if id != 0 && p.GetID() == id {
+			banned = append(banned, p)
+		} else if peerIp, err := common.ParseIPAddr(addr); err == nil &&
+			(peerIp == ip || peerIp+":"+strconv.Itoa(int(p.GetPort())) == ip) {
+			banned = append(banned, p)
+		}
+	}
+	this.PeerAddrMap.RUnlock()
+	for _, p := range banned {
+		p.Close()
+	}
+}
+
+//UnbanPeer remove the peer id and ip from ban list, return false if neither
+//is banned
+func (this *NetServer) UnbanPeer(id uint64, ip string) bool {
+	idOK, ipOK := false, false
+	if id != 0 {
+		idOK = this.banList.UnbanID(id)
+	}
+	if ip != "" {
+		ipOK = this.banList.UnbanIP(ip)
+	}
+	return idOK || ipOK
+}
+
+//GetBannedPeers return the banned peer ids and ips
+func (this *NetServer) GetBannedPeers() []common.BannedPeer {
+	return this.banList.GetBannedPeers()
+}
+
+//IsBanned return whether the peer id, the address or its ip is banned
+func (this *NetServer) IsBanned(id uint64, addr string) bool {
+	if id != 0 && this.banList.IsIDBanned(id) {
+		return true
+	}
+	ip, err := common.ParseIPAddr(addr)
+	return err == nil && (this.banList.IsIPBanned(ip) || this.banList.IsIPBanned(addr))
+}
+
 //check own network address
 func (this *NetServer) IsOwnAddress(addr string) bool {
 	return addr == this.OwnAddress
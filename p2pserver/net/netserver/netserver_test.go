@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/p2pserver/common"
 	"github.com/ontio/ontology/p2pserver/peer"
@@ -66,7 +67,7 @@ func TestNewNetServer(t *testing.T) {
 	if !server.GetRelay() {
 		t.Error("TestNewNetServer server relay state error", server.GetRelay())
 	}
-	if server.GetServices() != 1 {
+	if server.GetServices() != common.VERIFY_NODE|common.COMPACT_BLOCK_NODE {
 		t.Error("TestNewNetServer server service state error", server.GetServices())
 	}
 	if server.GetVersion() != common.PROTOCOL_VERSION {
@@ -185,3 +186,30 @@ func TestOutConnAPI(t *testing.T) {
 	a.Equal(server.IsAddrInOutConnRecord("192.168.1.1:300"), false, "fail to test IsAddrInOutConnRecord")
 	a.Equal(server.IsAddrInOutConnRecord("192.168.1.1:200"), true, "fail to test IsAddrInOutConnRecord")
 }
+
+func TestNetServerMisbehave(t *testing.T) {
+	a := require.New(t)
+	server := NewNetServer()
+	server.(*NetServer).banList = NewBanList("")
+
+	p := creatPeers(1)[0]
+	server.AddPeerAddress(p.GetAddr(), p)
+	server.Misbehave(p, common.MISBEHAVIOR_BAN_SCORE-1, "test")
+	a.False(server.IsBanned(p.GetID(), p.GetAddr()))
+
+	server.Misbehave(p, 1, "test")
+	a.True(server.IsBanned(p.GetID(), ""))
+	a.True(server.IsBanned(0, "127.0.0.1:20224"))
+	a.False(server.IsBanned(0, "127.0.0.1:20338"))
+	a.Equal(2, len(server.GetBannedPeers()))
+
+	a.True(server.UnbanPeer(p.GetID(), "127.0.0.1:20224"))
+	a.False(server.IsBanned(p.GetID(), "127.0.0.1:20224"))
+	a.False(server.UnbanPeer(p.GetID(), ""))
+
+	config.DefConfig.P2PNode.BanWholeIP = true
+	defer func() { config.DefConfig.P2PNode.BanWholeIP = false }()
+	p.AddMisbehavior(0 - p.GetMisbehavior())
+	server.Misbehave(p, common.MISBEHAVIOR_BAN_SCORE, "test")
+	a.True(server.IsBanned(0, "127.0.0.1:20338"))
+}
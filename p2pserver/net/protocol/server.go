@@ -20,7 +20,10 @@
 package p2p
 
 import (
+	"time"
+
 	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/link"
 	"github.com/ontio/ontology/p2pserver/message/types"
 	"github.com/ontio/ontology/p2pserver/peer"
 )
@@ -64,4 +67,12 @@ type P2P interface {
 	SetOwnAddress(addr string)
 	IsOwnAddress(addr string) bool
 	IsAddrFromConnecting(addr string) bool
+	IsAddrInOutConnRecord(addr string) bool
+	IsNbrPeerAddr(addr string) bool
+	GetNodeKey() *link.NodeKey
+	Misbehave(p *peer.Peer, score uint32, reason string)
+	BanPeer(id uint64, ip string, duration time.Duration)
+	UnbanPeer(id uint64, ip string) bool
+	GetBannedPeers() []common.BannedPeer
+	IsBanned(id uint64, addr string) bool
 }
@@ -0,0 +1,314 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simnet
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/common"
+	"github.com/ontio/ontology/p2pserver/link"
+	"github.com/ontio/ontology/p2pserver/message/types"
+	p2pnet "github.com/ontio/ontology/p2pserver/net/protocol"
+	"github.com/ontio/ontology/p2pserver/peer"
+)
+
+var _ p2pnet.P2P = (*Node)(nil)
+
+//Node is a simulated p2p node, it implements the p2p net interface
+type Node struct {
+	net     *Network
+	id      uint64
+	addr    string
+	port    uint16
+	height  uint64
+	halted  int32
+	msgChan chan *types.MsgPayload
+	np      *peer.NbrPeers
+	lock    sync.RWMutex
+	banned  map[uint64]time.Time
+}
+
+func newNode(net *Network, id uint64, port uint16) *Node {
+	node := &Node{
+		net:     net,
+		id:      id,
+		addr:    fmt.Sprintf("127.0.0.1:%d", port),
+		port:    port,
+		msgChan: make(chan *types.MsgPayload, common.CHAN_CAPABILITY),
+		np:      &peer.NbrPeers{},
+		banned:  make(map[uint64]time.Time),
+	}
+	node.np.Init()
+	return node
+}
+
+//addPeer add the other node as an established neighbor
+func (this *Node) addPeer(other *Node) {
+	p := peer.NewPeer()
+	p.UpdateInfo(time.Now(), common.PROTOCOL_VERSION, common.SERVICE_NODE,
+		other.GetPort(), other.id, 1, 0, "simnet")
+	p.Link.SetID(other.id)
+	p.Link.SetAddr(other.addr)
+	p.SetState(common.ESTABLISH)
+	this.np.AddNbrNode(p)
+}
+
+//receive put the message from peer into the message channel
+func (this *Node) receive(from uint64, msg types.Message) error {
+	if this.isHalted() || this.IsBanned(from, "") {
+		return nil
+	}
+	msg, size, err := copyMessage(msg)
+	if err != nil {
+		log.Warnf("[simnet]node %d receive bad message from %d: %s", this.id, from, err)
+		return err
+	}
+	addr := ""
+	if p := this.GetPeer(from); p != nil {
+		addr = p.GetAddr()
+	}
+	select {
+	case this.msgChan <- &types.MsgPayload{
+		Id:          from,
+		Addr:        addr,
+		PayloadSize: size,
+		Payload:     msg,
+	}:
+	case <-this.net.quit:
+	}
+	return nil
+}
+
+func (this *Node) isHalted() bool {
+	return atomic.LoadInt32(&this.halted) != 0
+}
+
+//Start resume the node halted
+func (this *Node) Start() {
+	atomic.StoreInt32(&this.halted, 0)
+}
+
+//Halt simulate a crash of node, it neither sends nor receives messages
+func (this *Node) Halt() {
+	atomic.StoreInt32(&this.halted, 1)
+}
+
+func (this *Node) Connect(addr string) error {
+	return nil
+}
+
+func (this *Node) GetID() uint64 {
+	return this.id
+}
+
+func (this *Node) GetVersion() uint32 {
+	return common.PROTOCOL_VERSION
+}
+
+func (this *Node) GetPort() uint16 {
+	return this.port
+}
+
+func (this *Node) GetHttpInfoPort() uint16 {
+	return 0
+}
+
+func (this *Node) GetRelay() bool {
+	return true
+}
+
+func (this *Node) GetHeight() uint64 {
+	return atomic.LoadUint64(&this.height)
+}
+
+func (this *Node) SetHeight(height uint64) {
+	atomic.StoreUint64(&this.height, height)
+}
+
+func (this *Node) GetTime() int64 {
+	return time.Now().UnixNano()
+}
+
+func (this *Node) GetServices() uint64 {
+	return uint64(common.SERVICE_NODE)
+}
+
+func (this *Node) GetNeighbors() []*peer.Peer {
+	return this.np.GetNeighbors()
+}
+
+func (this *Node) GetNeighborAddrs() []common.PeerAddr {
+	return this.np.GetNeighborAddrs()
+}
+
+func (this *Node) GetConnectionCnt() uint32 {
+	return this.np.GetNbrNodeCnt()
+}
+
+func (this *Node) GetMaxPeerBlockHeight() uint64 {
+	var max uint64
+	for _, p := range this.np.GetNeighbors() {
+		if p.GetHeight() > max {
+			max = p.GetHeight()
+		}
+	}
+	return max
+}
+
+func (this *Node) GetNp() *peer.NbrPeers {
+	return this.np
+}
+
+func (this *Node) GetPeer(id uint64) *peer.Peer {
+	return this.np.GetPeer(id)
+}
+
+func (this *Node) IsPeerEstablished(p *peer.Peer) bool {
+	return p != nil && this.np.NodeEstablished(p.GetID())
+}
+
+//Send route the message to peer through the network
+func (this *Node) Send(p *peer.Peer, msg types.Message) error {
+	if p == nil {
+		return errors.New("[simnet]send to nil peer")
+	}
+	if this.isHalted() {
+		return nil
+	}
+	return this.net.send(this.id, p.GetID(), msg)
+}
+
+func (this *Node) GetMsgChan() chan *types.MsgPayload {
+	return this.msgChan
+}
+
+func (this *Node) GetPeerFromAddr(addr string) *peer.Peer {
+	for _, p := range this.np.GetNeighbors() {
+		if p.GetAddr() == addr {
+			return p
+		}
+	}
+	return nil
+}
+
+func (this *Node) AddOutConnectingList(addr string) (added bool) {
+	return false
+}
+
+func (this *Node) GetOutConnRecordLen() int {
+	return 0
+}
+
+func (this *Node) RemoveFromConnectingList(addr string) {}
+
+func (this *Node) RemoveFromOutConnRecord(addr string) {}
+
+func (this *Node) RemoveFromInConnRecord(addr string) {}
+
+func (this *Node) AddPeerAddress(addr string, p *peer.Peer) {}
+
+func (this *Node) GetOutConnectingListLen() (count uint) {
+	return 0
+}
+
+func (this *Node) RemovePeerAddress(addr string) {}
+
+func (this *Node) AddNbrNode(p *peer.Peer) {
+	this.np.AddNbrNode(p)
+}
+
+func (this *Node) DelNbrNode(id uint64) (*peer.Peer, bool) {
+	return this.np.DelNbrNode(id)
+}
+
+func (this *Node) NodeEstablished(id uint64) bool {
+	return this.np.NodeEstablished(id)
+}
+
+//Xmit broadcast the message to all neighbors
+func (this *Node) Xmit(msg types.Message) {
+	for _, p := range this.np.GetNeighbors() {
+		this.Send(p, msg)
+	}
+}
+
+func (this *Node) SetOwnAddress(addr string) {}
+
+func (this *Node) IsOwnAddress(addr string) bool {
+	return addr == this.addr
+}
+
+func (this *Node) IsAddrFromConnecting(addr string) bool {
+	return false
+}
+
+func (this *Node) IsAddrInOutConnRecord(addr string) bool {
+	return this.GetPeerFromAddr(addr) != nil
+}
+
+func (this *Node) IsNbrPeerAddr(addr string) bool {
+	return this.GetPeerFromAddr(addr) != nil
+}
+
+func (this *Node) GetNodeKey() *link.NodeKey {
+	return nil
+}
+
+func (this *Node) Misbehave(p *peer.Peer, score uint32, reason string) {
+	if p != nil {
+		log.Infof("[simnet]node %d misbehave of %d: %s", this.id, p.GetID(), reason)
+	}
+}
+
+//BanPeer stop receiving messages from the peer id
+func (this *Node) BanPeer(id uint64, ip string, duration time.Duration) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.banned[id] = time.Now().Add(duration)
+}
+
+func (this *Node) UnbanPeer(id uint64, ip string) bool {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	_, ok := this.banned[id]
+	delete(this.banned, id)
+	return ok
+}
+
+func (this *Node) GetBannedPeers() []common.BannedPeer {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	peers := make([]common.BannedPeer, 0, len(this.banned))
+	for id, until := range this.banned {
+		peers = append(peers, common.BannedPeer{ID: id, Expire: until.Unix()})
+	}
+	return peers
+}
+
+func (this *Node) IsBanned(id uint64, addr string) bool {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	until, ok := this.banned[id]
+	return ok && time.Now().Before(until)
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package simnet provides an in-process network implementing the p2p net
+// interface, it is used to run several nodes in one test process with
+// configurable latency, packet loss, partitions and message injection
+package simnet
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/p2pserver/message/types"
+)
+
+const (
+	LINK_QUEUE_SIZE = 4096 //Messages waiting for delivery on one link
+	BASE_PORT       = 20000
+)
+
+//Interceptor is called for every message sent through the network, the
+//returned messages are delivered instead of the original one, so that
+//messages can be dropped, tampered or duplicated to simulate byzantine nodes
+type Interceptor func(from, to uint64, msg types.Message) []types.Message
+
+type envelope struct {
+	from uint64
+	due  time.Time
+	msg  types.Message
+}
+
+type linkKey struct {
+	from uint64
+	to   uint64
+}
+
+//simLink delivers messages from one node to another in order, like a tcp
+//connection
+type simLink struct {
+	ch      chan *envelope
+	lastDue time.Time
+}
+
+//Network connects all of the simulated nodes with each other
+type Network struct {
+	lock        sync.RWMutex
+	nodes       map[uint64]*Node
+	links       map[linkKey]*simLink
+	minLatency  time.Duration
+	maxLatency  time.Duration
+	lossRate    float64
+	groups      map[uint64]int //Map node id => partition group
+	interceptor Interceptor
+	rand        *rand.Rand
+	quit        chan struct{}
+}
+
+//NewNetwork return a network without latency, loss and partitions
+func NewNetwork() *Network {
+	return &Network{
+		nodes: make(map[uint64]*Node),
+		links: make(map[linkKey]*simLink),
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		quit:  make(chan struct{}),
+	}
+}
+
+//NewNode add a node with the id to network, the node is connected to all of
+//the existing nodes
+func (this *Network) NewNode(id uint64) *Node {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if _, ok := this.nodes[id]; ok {
+		panic(fmt.Errorf("simnet: node %d already exists", id))
+	}
+	node := newNode(this, id, uint16(BASE_PORT+len(this.nodes)))
+	for _, other := range this.nodes {
+		other.addPeer(node)
+		node.addPeer(other)
+	}
+	this.nodes[id] = node
+	return node
+}
+
+//GetNode return the node of id
+func (this *Network) GetNode(id uint64) *Node {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.nodes[id]
+}
+
+//SetLatency set the range of message delay, each message is delayed by a
+//random duration in [min, max] and the order of one link is kept
+func (this *Network) SetLatency(min, max time.Duration) {
+	if max < min {
+		max = min
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.minLatency = min
+	this.maxLatency = max
+}
+
+//SetLossRate set the probability in [0, 1] that a message is lost
+func (this *Network) SetLossRate(rate float64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.lossRate = rate
+}
+
+//Partition split the nodes into the groups, nodes can only reach the nodes of
+//same group. The nodes not listed form another group
+func (this *Network) Partition(groups ...[]uint64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.groups = make(map[uint64]int)
+	for i, group := range groups {
+		for _, id := range group {
+			this.groups[id] = i + 1
+		}
+	}
+}
+
+//Heal remove all partitions
+func (this *Network) Heal() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.groups = nil
+}
+
+//SetInterceptor set the interceptor of messages, nil to remove it
+func (this *Network) SetInterceptor(interceptor Interceptor) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.interceptor = interceptor
+}
+
+//Reachable return whether the messages from node can reach the other node
+func (this *Network) Reachable(from, to uint64) bool {
+	this.lock.RLock()
+	defer this.lock.RUnlock()
+	return this.reachable(from, to)
+}
+
+func (this *Network) reachable(from, to uint64) bool {
+	if this.groups == nil {
+		return true
+	}
+	return this.groups[from] == this.groups[to]
+}
+
+//Inject deliver the message to node as if it is sent by the from node, the
+//message bypasses latency, loss, partitions and interceptor
+func (this *Network) Inject(from, to uint64, msg types.Message) error {
+	node := this.GetNode(to)
+	if node == nil {
+		return fmt.Errorf("simnet: node %d not found", to)
+	}
+	return node.receive(from, msg)
+}
+
+//Close stop delivering messages
+func (this *Network) Close() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	select {
+	case <-this.quit:
+	default:
+		close(this.quit)
+	}
+}
+
+//send route the message through the rules of network
+func (this *Network) send(from, to uint64, msg types.Message) error {
+	this.lock.RLock()
+	_, ok := this.nodes[to]
+	reachable := this.reachable(from, to)
+	interceptor := this.interceptor
+	this.lock.RUnlock()
+	if !ok {
+		return fmt.Errorf("simnet: node %d not found", to)
+	}
+	if !reachable {
+		return nil
+	}
+	//interceptor is called without lock, it may inject messages
+	msgs := []types.Message{msg}
+	if interceptor != nil {
+		msgs = interceptor(from, to, msg)
+	}
+
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for _, m := range msgs {
+		if this.lossRate > 0 && this.rand.Float64() < this.lossRate {
+			continue
+		}
+		delay := this.minLatency
+		if this.maxLatency > this.minLatency {
+			delay += time.Duration(this.rand.Int63n(int64(this.maxLatency - this.minLatency)))
+		}
+		l := this.getLink(from, to)
+		due := time.Now().Add(delay)
+		if due.Before(l.lastDue) {
+			due = l.lastDue
+		}
+		l.lastDue = due
+		select {
+		case l.ch <- &envelope{from: from, due: due, msg: m}:
+		default:
+			log.Warnf("[simnet]link %d -> %d is full, drop %s", from, to, m.CmdType())
+		}
+	}
+	return nil
+}
+
+func (this *Network) getLink(from, to uint64) *simLink {
+	key := linkKey{from: from, to: to}
+	l, ok := this.links[key]
+	if ok {
+		return l
+	}
+	l = &simLink{ch: make(chan *envelope, LINK_QUEUE_SIZE)}
+	this.links[key] = l
+	node := this.nodes[to]
+	go func() {
+		for {
+			select {
+			case <-this.quit:
+				return
+			case env := <-l.ch:
+				if d := time.Until(env.due); d > 0 {
+					time.Sleep(d)
+				}
+				node.receive(env.from, env.msg)
+			}
+		}
+	}()
+	return l
+}
+
+//copyMessage make a copy of message through serialization, receivers may
+//modify the message
+func copyMessage(msg types.Message) (types.Message, uint32, error) {
+	sink := common.NewZeroCopySink(nil)
+	types.WriteMessage(sink, msg)
+	return types.ReadMessage(bytes.NewBuffer(sink.Bytes()))
+}
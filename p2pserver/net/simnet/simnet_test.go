@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package simnet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ontio/ontology/p2pserver/message/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func recvPing(node *Node, timeout time.Duration) *types.Ping {
+	select {
+	case payload := <-node.GetMsgChan():
+		return payload.Payload.(*types.Ping)
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+func newTestNetwork(n int) (*Network, []*Node) {
+	net := NewNetwork()
+	nodes := make([]*Node, 0, n)
+	for i := 1; i <= n; i++ {
+		nodes = append(nodes, net.NewNode(uint64(i)))
+	}
+	return net, nodes
+}
+
+func TestNetworkSend(t *testing.T) {
+	net, nodes := newTestNetwork(3)
+	defer net.Close()
+
+	assert.Equal(t, uint32(2), nodes[0].GetConnectionCnt())
+	err := nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: 1})
+	assert.Nil(t, err)
+	ping := recvPing(nodes[1], time.Second)
+	assert.NotNil(t, ping)
+	assert.Equal(t, uint64(1), ping.Height)
+
+	nodes[0].Xmit(&types.Ping{Height: 2})
+	assert.Equal(t, uint64(2), recvPing(nodes[1], time.Second).Height)
+	assert.Equal(t, uint64(2), recvPing(nodes[2], time.Second).Height)
+}
+
+func TestNetworkLatencyKeepOrder(t *testing.T) {
+	net, nodes := newTestNetwork(2)
+	defer net.Close()
+	net.SetLatency(10*time.Millisecond, 50*time.Millisecond)
+
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: uint64(i)})
+	}
+	for i := 0; i < 20; i++ {
+		ping := recvPing(nodes[1], time.Second)
+		assert.NotNil(t, ping)
+		assert.Equal(t, uint64(i), ping.Height)
+	}
+	assert.True(t, time.Since(start) >= 10*time.Millisecond)
+}
+
+func TestNetworkPartition(t *testing.T) {
+	net, nodes := newTestNetwork(3)
+	defer net.Close()
+
+	net.Partition([]uint64{1}, []uint64{2, 3})
+	assert.False(t, net.Reachable(1, 2))
+	assert.True(t, net.Reachable(2, 3))
+	nodes[0].Xmit(&types.Ping{Height: 1})
+	assert.Nil(t, recvPing(nodes[1], 100*time.Millisecond))
+	nodes[1].Send(nodes[1].GetPeer(3), &types.Ping{Height: 2})
+	assert.NotNil(t, recvPing(nodes[2], time.Second))
+
+	net.Heal()
+	nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: 3})
+	assert.NotNil(t, recvPing(nodes[1], time.Second))
+}
+
+func TestNetworkLossAndHalt(t *testing.T) {
+	net, nodes := newTestNetwork(2)
+	defer net.Close()
+
+	net.SetLossRate(1)
+	nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: 1})
+	assert.Nil(t, recvPing(nodes[1], 100*time.Millisecond))
+	net.SetLossRate(0)
+
+	nodes[1].Halt()
+	nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: 2})
+	assert.Nil(t, recvPing(nodes[1], 100*time.Millisecond))
+	nodes[1].Start()
+	nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: 3})
+	assert.Equal(t, uint64(3), recvPing(nodes[1], time.Second).Height)
+}
+
+func TestNetworkInterceptAndInject(t *testing.T) {
+	net, nodes := newTestNetwork(2)
+	defer net.Close()
+
+	net.SetInterceptor(func(from, to uint64, msg types.Message) []types.Message {
+		ping := msg.(*types.Ping)
+		return []types.Message{ping, &types.Ping{Height: ping.Height + 100}}
+	})
+	nodes[0].Send(nodes[0].GetPeer(2), &types.Ping{Height: 1})
+	assert.Equal(t, uint64(1), recvPing(nodes[1], time.Second).Height)
+	assert.Equal(t, uint64(101), recvPing(nodes[1], time.Second).Height)
+	net.SetInterceptor(nil)
+
+	err := net.Inject(1, 2, &types.Ping{Height: 7})
+	assert.Nil(t, err)
+	payload := <-nodes[1].GetMsgChan()
+	assert.Equal(t, uint64(1), payload.Id)
+	assert.Equal(t, uint64(7), payload.Payload.(*types.Ping).Height)
+}
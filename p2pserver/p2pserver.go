@@ -24,7 +24,6 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"net"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
@@ -54,7 +53,8 @@ type P2PServer struct {
 	blockSync *BlockSyncMgr
 	ledger    *ledger.Ledger
 	ReconnectAddrs
-	recentPeers    map[uint32][]string
+	addrBook       *AddrBook
+	lightClient    *LightClient
 	quitSyncRecent chan bool
 	quitOnline     chan bool
 	quitHeartBeat  chan bool
@@ -77,7 +77,10 @@ func NewServer() *P2PServer {
 
 	p.msgRouter = utils.NewMsgRouter(p.network)
 	p.blockSync = NewBlockSyncMgr(p)
-	p.recentPeers = make(map[uint32][]string)
+	if config.DefConfig.P2PNode.LightClient {
+		p.lightClient = NewLightClient(p)
+	}
+	p.addrBook = NewAddrBook(common.ADDR_BOOK_FILE_NAME, config.DefConfig.P2PNode.NetworkMagic)
 	p.quitSyncRecent = make(chan bool)
 	p.quitOnline = make(chan bool)
 	p.quitHeartBeat = make(chan bool)
@@ -123,6 +126,7 @@ func (this *P2PServer) Stop() {
 	this.quitHeartBeat <- true
 	this.msgRouter.Stop()
 	this.blockSync.Close()
+	this.addrBook.Save()
 }
 
 // GetNetWork returns the low level netserver
@@ -151,9 +155,12 @@ func (this *P2PServer) Xmit(message interface{}) error {
 	var msg msgtypes.Message
 	switch message.(type) {
 	case *types.Transaction:
-		log.Debug("[p2p]TX transaction message")
+		log.Debug("[p2p]TX transaction inv message")
 		txn := message.(*types.Transaction)
-		msg = msgpack.NewTxn(txn)
+		// announce the hash only, peers fetch the transaction if they don`t have it
+		utils.MarkTxReceived(txn.Hash())
+		this.network.GetNp().BroadcastTxInv(txn.Hash())
+		return nil
 	case *msgtypes.ConsensusPayload:
 		log.Debug("[p2p]TX consensus message")
 		consensusPayload := message.(*msgtypes.ConsensusPayload)
@@ -191,6 +198,9 @@ func (this *P2PServer) GetID() uint64 {
 
 // OnAddNode adds the peer id to the block sync mgr
 func (this *P2PServer) OnAddNode(id uint64) {
+	if p := this.getNode(id); p != nil {
+		this.addPeerToAddrBook(p)
+	}
 	this.blockSync.OnAddNode(id)
 }
 
@@ -210,6 +220,21 @@ func (this *P2PServer) OnBlockReceive(fromID uint64, blockSize uint32,
 	this.blockSync.OnBlockReceive(fromID, blockSize, block, merkleRoot)
 }
 
+// OnStateValueReceive passes the state value to the light client
+func (this *P2PServer) OnStateValueReceive(fromID uint64, state *msgtypes.StateValue) {
+	if this.lightClient != nil {
+		this.lightClient.OnStateValueReceive(fromID, state)
+	}
+}
+
+// GetStorage queries the contract storage from full peers in light client mode
+func (this *P2PServer) GetStorage(contract comm.Address, key []byte) ([]byte, uint32, error) {
+	if this.lightClient == nil {
+		return nil, 0, errors.New("light client is not enabled")
+	}
+	return this.lightClient.GetStorage(contract, key)
+}
+
 // Todo: remove it if no use
 func (this *P2PServer) GetConnectionState() uint32 {
 	return common.INIT
@@ -438,10 +463,11 @@ func (this *P2PServer) retryInactivePeer() {
 			log.Debug("[p2p]Try to reconnect peer, peer addr is ", addr)
 			<-time.After(time.Duration(rand.Intn(common.CONN_MAX_BACK)) * time.Millisecond)
 			log.Debug("[p2p]Back off time`s up, start connect node")
-			this.network.Connect(addr)
+			this.connectAddr(addr)
 		}
 
 	}
+	this.connectFromAddrBook()
 }
 
 //connectSeedService make sure seed peer be connected
@@ -563,39 +589,44 @@ func (this *P2PServer) removeFromRetryList(addr string) {
 	}
 }
 
-//tryRecentPeers try connect recent contact peer when service start
+//tryRecentPeers try connect the peers in address book when service start
 func (this *P2PServer) tryRecentPeers() {
-	netID := config.DefConfig.P2PNode.NetworkMagic
-	if comm.FileExisted(common.RECENT_FILE_NAME) {
-		buf, err := ioutil.ReadFile(common.RECENT_FILE_NAME)
-		if err != nil {
-			log.Warn("[p2p]read %s fail:%s, connect recent peers cancel", common.RECENT_FILE_NAME, err.Error())
-			return
-		}
-
-		err = json.Unmarshal(buf, &this.recentPeers)
-		if err != nil {
-			log.Warn("[p2p]parse recent peer file fail: ", err)
-			return
-		}
-		if len(this.recentPeers[netID]) > 0 {
-			log.Info("[p2p]try to connect recent peer")
-		}
-		for _, v := range this.recentPeers[netID] {
-			go this.network.Connect(v)
-		}
+	this.importRecentPeers()
+	if this.addrBook.Size() > 0 {
+		log.Info("[p2p]try to connect recent peer")
+	}
+	this.connectFromAddrBook()
+}
 
+//importRecentPeers import the legacy recent contact list to address book
+func (this *P2PServer) importRecentPeers() {
+	if this.addrBook.Size() > 0 || !comm.FileExisted(common.RECENT_FILE_NAME) {
+		return
+	}
+	buf, err := ioutil.ReadFile(common.RECENT_FILE_NAME)
+	if err != nil {
+		log.Warnf("[p2p]read %s fail:%s, import recent peers cancel", common.RECENT_FILE_NAME, err.Error())
+		return
+	}
+	recentPeers := make(map[uint32][]string)
+	err = json.Unmarshal(buf, &recentPeers)
+	if err != nil {
+		log.Warn("[p2p]parse recent peer file fail: ", err)
+		return
+	}
+	for _, addr := range recentPeers[config.DefConfig.P2PNode.NetworkMagic] {
+		this.addrBook.AddAddress(addr, "")
 	}
 }
 
-//syncUpRecentPeers sync up recent peers periodically
+//syncUpRecentPeers save address book periodically
 func (this *P2PServer) syncUpRecentPeers() {
 	periodTime := common.RECENT_TIMEOUT
 	t := time.NewTicker(time.Second * (time.Duration(periodTime)))
 	for {
 		select {
 		case <-t.C:
-			this.syncPeerAddr()
+			this.addrBook.Save()
 		case <-this.quitSyncRecent:
 			t.Stop()
 			return
@@ -604,62 +635,59 @@ func (this *P2PServer) syncUpRecentPeers() {
 
 }
 
-//syncPeerAddr compare snapshot of recent peer with current link,then persist the list
-func (this *P2PServer) syncPeerAddr() {
-	changed := false
-	netID := config.DefConfig.P2PNode.NetworkMagic
-	for i := 0; i < len(this.recentPeers[netID]); i++ {
-		p := this.network.GetPeerFromAddr(this.recentPeers[netID][i])
-		if p == nil || (p != nil && p.GetState() != common.ESTABLISH) {
-			this.recentPeers[netID] = append(this.recentPeers[netID][:i], this.recentPeers[netID][i+1:]...)
-			changed = true
-			i--
-		}
+//OnAddrReceive add the addresses told by peer to address book, and connect
+//more peers if out connections are not enough
+func (this *P2PServer) OnAddrReceive(fromAddr string, addrs []string) {
+	src, _ := common.ParseIPAddr(fromAddr)
+	for _, addr := range addrs {
+		this.addrBook.AddAddress(addr, src)
 	}
-	left := common.RECENT_LIMIT - len(this.recentPeers[netID])
-	if left > 0 {
-		np := this.network.GetNp()
-		np.Lock()
-		var ip net.IP
-		for _, p := range np.List {
-			addr, _ := p.GetAddr16()
-			ip = addr[:]
-			nodeAddr := ip.To16().String() + ":" +
-				strconv.Itoa(int(p.GetPort()))
-			found := false
-			for i := 0; i < len(this.recentPeers[netID]); i++ {
-				if nodeAddr == this.recentPeers[netID][i] {
-					found = true
-					break
-				}
-			}
-			if !found {
-				this.recentPeers[netID] = append(this.recentPeers[netID], nodeAddr)
-				left--
-				changed = true
-				if left == 0 {
-					break
-				}
-			}
-		}
-		np.Unlock()
-	} else {
-		if left < 0 {
-			left = -left
-			this.recentPeers[netID] = append(this.recentPeers[netID][:0], this.recentPeers[netID][0+left:]...)
-			changed = true
-		}
+	this.connectFromAddrBook()
+}
 
+//addPeerToAddrBook record the handshaked peer in address book, the address
+//dialed out is marked good, and the listen address of inbound peer is added
+//as new address
+func (this *P2PServer) addPeerToAddrBook(p *peer.Peer) {
+	linkAddr := p.Link.GetAddr()
+	if this.network.IsAddrInOutConnRecord(linkAddr) {
+		this.addrBook.Good(linkAddr)
+		return
 	}
-	if changed {
-		buf, err := json.Marshal(this.recentPeers)
-		if err != nil {
-			log.Warn("[p2p]package recent peer fail: ", err)
-			return
-		}
-		err = ioutil.WriteFile(common.RECENT_FILE_NAME, buf, os.ModePerm)
-		if err != nil {
-			log.Warn("[p2p]write recent peer fail: ", err)
-		}
+	ip, err := common.ParseIPAddr(linkAddr)
+	if err != nil || p.GetPort() == 0 {
+		return
+	}
+	this.addrBook.AddAddress(ip+":"+strconv.Itoa(int(p.GetPort())), ip)
+}
+
+//connectFromAddrBook connect the addresses picked from address book if out
+//connections are not enough
+func (this *P2PServer) connectFromAddrBook() {
+	left := int(config.DefConfig.P2PNode.MaxConnOutBound) - this.network.GetOutConnRecordLen() -
+		int(this.network.GetOutConnectingListLen())
+	if left > common.ADDR_PICK_CNT {
+		left = common.ADDR_PICK_CNT
+	}
+	if left <= 0 {
+		return
+	}
+	for _, addr := range this.addrBook.PickAddresses(left, this.isAddrInUse) {
+		log.Debug("[p2p]connect ip address:", addr)
+		go this.connectAddr(addr)
 	}
 }
+
+//isAddrInUse return whether the address is connected, being connected or
+//not allowed to connect
+func (this *P2PServer) isAddrInUse(addr string) bool {
+	return this.network.IsOwnAddress(addr) || this.network.IsNbrPeerAddr(addr) ||
+		this.network.GetPeerFromAddr(addr) != nil || this.network.IsAddrFromConnecting(addr) ||
+		this.network.IsAddrInOutConnRecord(addr) || this.network.IsBanned(0, addr)
+}
+
+//connectAddr connect the address and record the attempt in address book
+func (this *P2PServer) connectAddr(addr string) {
+	this.addrBook.Attempt(addr)
+	this.network.Connect(addr)
+}
@@ -47,6 +47,25 @@ func (this *NbrPeers) Broadcast(msg types.Message) {
 	}
 }
 
+//BroadcastTxInv announce the transaction hash to all establish peers which
+//don`t know the transaction yet, peers fetch the transaction by data request
+func (this *NbrPeers) BroadcastTxInv(hash comm.Uint256) {
+	var msg types.Inv
+	msg.P.InvType = comm.TRANSACTION
+	msg.P.Blk = []comm.Uint256{hash}
+	sink := comm.NewZeroCopySink(nil)
+	types.WriteMessage(sink, &msg)
+
+	this.RLock()
+	defer this.RUnlock()
+	for _, node := range this.List {
+		if node.linkState == common.ESTABLISH && node.GetRelay() && !node.IsKnownTx(hash) {
+			node.MarkKnownTx(hash)
+			node.SendRaw(msg.CmdType(), sink.Bytes())
+		}
+	}
+}
+
 //NodeExisted return when peer in nbr list
 func (this *NbrPeers) NodeExisted(uid uint64) bool {
 	_, ok := this.List[uid]
@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	comm "github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/p2pserver/common"
@@ -134,6 +135,8 @@ type Peer struct {
 	txnCnt    uint64
 	rxTxnCnt  uint64
 	connLock  sync.RWMutex
+	knownTxs  *lru.Cache
+	score     uint32 //misbehavior score
 }
 
 //NewPeer return new peer without publickey initial
@@ -141,6 +144,7 @@ func NewPeer() *Peer {
 	p := &Peer{
 		linkState: common.INIT,
 	}
+	p.knownTxs, _ = lru.New(common.MAX_KNOWN_TX_CNT)
 	p.Link = conn.NewLink()
 	runtime.SetFinalizer(p, rmPeer)
 	return p
@@ -279,6 +283,16 @@ func (this *Peer) Send(msg types.Message) error {
 	return this.SendRaw(msg.CmdType(), sink.Bytes())
 }
 
+//MarkKnownTx record that peer already has the transaction
+func (this *Peer) MarkKnownTx(hash comm.Uint256) {
+	this.knownTxs.Add(hash, nil)
+}
+
+//IsKnownTx return whether peer already has the transaction
+func (this *Peer) IsKnownTx(hash comm.Uint256) bool {
+	return this.knownTxs.Contains(hash)
+}
+
 //SetHttpInfoState set peer`s httpinfo state
 func (this *Peer) SetHttpInfoState(httpInfo bool) {
 	if httpInfo {
@@ -293,6 +307,37 @@ func (this *Peer) GetHttpInfoState() bool {
 	return this.cap[common.HTTP_INFO_FLAG] == 1
 }
 
+//AddMisbehavior add score to peer`s misbehavior score and return the total
+func (this *Peer) AddMisbehavior(score uint32) uint32 {
+	return atomic.AddUint32(&this.score, score)
+}
+
+//GetMisbehavior return peer`s misbehavior score
+func (this *Peer) GetMisbehavior() uint32 {
+	return atomic.LoadUint32(&this.score)
+}
+
+//SetSecureLinkState set whether the link with peer is to be encrypted
+func (this *Peer) SetSecureLinkState(secure bool) {
+	if secure {
+		this.cap[common.SECURE_LINK_FLAG] = 0x01
+	} else {
+		this.cap[common.SECURE_LINK_FLAG] = 0x00
+	}
+	this.Link.SetSecureRequired(secure)
+}
+
+//GetSecureLinkState return whether the link with peer is to be encrypted
+func (this *Peer) GetSecureLinkState() bool {
+	return this.cap[common.SECURE_LINK_FLAG] == 1
+}
+
+//GetNodePubKey return the authenticated node key of peer, nil if the link is
+//not encrypted
+func (this *Peer) GetNodePubKey() []byte {
+	return this.Link.GetRemotePubKey()
+}
+
 //GetHttpInfoPort return peer`s httpinfo port
 func (this *Peer) GetHttpInfoPort() uint16 {
 	return this.base.GetHttpInfoPort()
@@ -21,6 +21,9 @@ package peer
 import (
 	"testing"
 	"time"
+
+	comm "github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/p2pserver/common"
 )
 
 func initTestPeer() *Peer {
@@ -102,3 +105,22 @@ func TestUpdatePeer(t *testing.T) {
 	p.DumpInfo()
 
 }
+
+func TestKnownTx(t *testing.T) {
+	p := initTestPeer()
+	hash := comm.Uint256{1, 2, 3}
+	if p.IsKnownTx(hash) {
+		t.Errorf("peer should not know the tx")
+	}
+	p.MarkKnownTx(hash)
+	if !p.IsKnownTx(hash) {
+		t.Errorf("peer should know the tx")
+	}
+
+	for i := 0; i < common.MAX_KNOWN_TX_CNT; i++ {
+		p.MarkKnownTx(comm.Uint256{byte(i), byte(i >> 8), byte(i >> 16), 0xff})
+	}
+	if p.IsKnownTx(hash) {
+		t.Errorf("known tx set should be bounded")
+	}
+}
@@ -18,17 +18,21 @@
 package main
 
 import (
+	"fmt"
 	"github.com/ontio/ontology/cmd"
 	"github.com/ontio/ontology/cmd/abi"
+	cmdcom "github.com/ontio/ontology/cmd/common"
 	cmdsvr "github.com/ontio/ontology/cmd/sigsvr"
 	clisvrcom "github.com/ontio/ontology/cmd/sigsvr/common"
 	"github.com/ontio/ontology/cmd/sigsvr/store"
 	"github.com/ontio/ontology/cmd/utils"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
+	"github.com/ontio/ontology/consensus/signer"
 	"github.com/urfave/cli"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"syscall"
 )
@@ -46,6 +50,9 @@ func setupSigSvr() *cli.App {
 		utils.CliAddressFlag,
 		utils.CliRpcPortFlag,
 		utils.CliABIPathFlag,
+		//consensus signer setting
+		utils.CliConsensusAccountFlag,
+		utils.AccountPassFlag,
 	}
 	app.Commands = []cli.Command{
 		cmdsvr.ImportWalletCommand,
@@ -81,6 +88,15 @@ func startSigSvr(ctx *cli.Context) {
 	}
 	log.Infof("Load wallet data success. Account number:%d", accountNum)
 
+	if address := ctx.String(utils.GetFlagName(utils.CliConsensusAccountFlag)); address != "" {
+		err = initConsensusSigner(ctx, walletDirPath, address)
+		if err != nil {
+			log.Errorf("initConsensusSigner error:%s", err)
+			return
+		}
+		log.Infof("Consensus signer of account %s enabled", address)
+	}
+
 	rpcAddress := ctx.String(utils.GetFlagName(utils.CliAddressFlag))
 	rpcPort := ctx.Uint(utils.GetFlagName(utils.CliRpcPortFlag))
 	if rpcPort == 0 {
@@ -108,6 +124,28 @@ func startSigSvr(ctx *cli.Context) {
 	<-exit
 }
 
+//initConsensusSigner unlocks the consensus account, signing records are kept in
+//wallet dir to refuse double signing after restart
+func initConsensusSigner(ctx *cli.Context, walletDirPath, address string) error {
+	passwd, err := cmdcom.GetPasswd(ctx)
+	if err != nil {
+		return err
+	}
+	acc, err := clisvrcom.DefWalletStore.GetAccountByAddress(address, passwd)
+	if err != nil {
+		return err
+	}
+	if acc == nil {
+		return fmt.Errorf("cannot find account by address: %s", address)
+	}
+	guard, err := signer.NewSignGuard(filepath.Join(walletDirPath, signer.GUARD_FILE_NAME))
+	if err != nil {
+		return err
+	}
+	clisvrcom.DefConsensusSigner = signer.NewLocalSigner(acc, guard)
+	return nil
+}
+
 func main() {
 	if err := setupSigSvr().Run(os.Args); err != nil {
 		cmd.PrintErrorMsg(err.Error())
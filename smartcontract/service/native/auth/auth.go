@@ -25,6 +25,7 @@ import (
 
 	"github.com/ontio/ontology/account"
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/log"
 	"github.com/ontio/ontology/errors"
 	"github.com/ontio/ontology/smartcontract/service/native"
@@ -125,7 +126,7 @@ func Transfer(native *native.NativeService) ([]byte, error) {
 	//prepare event msg
 	contract := param.ContractAddr.ToHexString()
 	failState := []interface{}{"transfer", contract, false}
-	sucState := []interface{}{"transfer", contract, true}
+	sucState := []interface{}{"transfer", contract, true, string(param.NewAdminOntID)}
 
 	//call transfer func
 	ret, err := transfer(native, param.ContractAddr, param.NewAdminOntID, param.KeyNo)
@@ -152,7 +153,8 @@ func AssignFuncsToRole(native *native.NativeService) ([]byte, error) {
 	//prepare event msg
 	contract := param.ContractAddr.ToHexString()
 	failState := []interface{}{"assignFuncsToRole", contract, false}
-	sucState := []interface{}{"assignFuncsToRole", contract, true}
+	sucState := []interface{}{"assignFuncsToRole", contract, true, string(param.Role),
+		StringsDedupAndSort(param.FuncNames)}
 
 	if param.Role == nil {
 		return nil, fmt.Errorf("[assignFuncsToRole] invalid param: role is nil")
@@ -227,9 +229,12 @@ func assignToRole(native *native.NativeService, param *OntIDsToRoleParam) (bool,
 		return false, nil
 	}
 
-	//init a permanent auth token
+	//init a permanent auth token, or one expires at param.ExpireTime
 	token := new(AuthToken)
 	token.expireTime = uint32(future.Unix())
+	if param.ExpireTime != 0 {
+		token.expireTime = param.ExpireTime
+	}
 	token.level = 2
 	token.role = param.Role
 
@@ -245,6 +250,9 @@ func assignToRole(native *native.NativeService, param *OntIDsToRoleParam) (bool,
 			tokens = new(roleTokens)
 			tokens.tokens = make([]*AuthToken, 1)
 			tokens.tokens[0] = token
+		} else if old := findToken(tokens, param.Role); old != nil {
+			//renew the token assigned before, which may have expired
+			old.expireTime = token.expireTime
 		} else {
 			ret, err := hasRole(native, param.ContractAddr, p, param.Role)
 			if err != nil {
@@ -276,6 +284,15 @@ func AssignOntIDsToRole(native *native.NativeService) ([]byte, error) {
 	if param.Role == nil {
 		return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: role is nil")
 	}
+	expireHeight := config.GetRoleExpireHeight(config.DefConfig.P2PNode.NetworkId)
+	if param.ExpireTime != 0 && native.Height < expireHeight {
+		return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: expireTime is not supported before height %d",
+			expireHeight)
+	}
+	if param.ExpireTime != 0 && param.ExpireTime <= native.Time {
+		return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: expireTime %d is not later than now %d",
+			param.ExpireTime, native.Time)
+	}
 	for i, ontID := range param.Persons {
 		if !account.VerifyID(string(ontID)) {
 			return nil, fmt.Errorf("[assignOntIDsToRole] invalid param: param.Persons[%d]=%s",
@@ -289,8 +306,13 @@ func AssignOntIDsToRole(native *native.NativeService) ([]byte, error) {
 	}
 
 	contract := param.ContractAddr.ToHexString()
+	persons := make([]string, 0, len(param.Persons))
+	for _, p := range param.Persons {
+		persons = append(persons, string(p))
+	}
 	failState := []interface{}{"assignOntIDsToRole", contract, false}
-	sucState := []interface{}{"assignOntIDsToRole", contract, true}
+	sucState := []interface{}{"assignOntIDsToRole", contract, true, string(param.Role), persons,
+		param.ExpireTime}
 	if ret {
 		pushEvent(native, sucState)
 		return utils.BYTE_TRUE, nil
@@ -306,10 +328,11 @@ func getAuthToken(native *native.NativeService, contractAddr common.Address, ont
 		return nil, fmt.Errorf("get token failed, caused by %v", err)
 	}
 	if tokens != nil {
-		for _, token := range tokens.tokens {
-			if bytes.Compare(token.role, role) == 0 { //permanent token
-				return token, nil
-			}
+		token := findToken(tokens, role)
+		//token assigned by admin, roles can expire since the role expire height
+		expireHeight := config.GetRoleExpireHeight(config.DefConfig.P2PNode.NetworkId)
+		if token != nil && (native.Height < expireHeight || token.expireTime >= native.Time) {
+			return token, nil
 		}
 	}
 	status, err := getDelegateStatus(native, contractAddr, ontID)
@@ -470,7 +493,8 @@ func Delegate(native *native.NativeService) ([]byte, error) {
 	//prepare event msg
 	contract := param.ContractAddr.ToHexString()
 	failState := []interface{}{"delegate", contract, param.From, param.To, false}
-	sucState := []interface{}{"delegate", contract, param.From, param.To, true}
+	sucState := []interface{}{"delegate", contract, param.From, param.To, true, string(param.Role),
+		param.Level, native.Time + uint32(param.Period)}
 
 	//call the delegate func
 	ret, err := delegate(native, param.ContractAddr, param.From, param.To, param.Role,
@@ -544,7 +568,7 @@ func Withdraw(native *native.NativeService) ([]byte, error) {
 	//prepare event msg
 	contract := param.ContractAddr.ToHexString()
 	failState := []interface{}{"withdraw", contract, param.Initiator, param.Delegate, false}
-	sucState := []interface{}{"withdraw", contract, param.Initiator, param.Delegate, true}
+	sucState := []interface{}{"withdraw", contract, param.Initiator, param.Delegate, true, string(param.Role)}
 
 	//call the withdraw func
 	ret, err := withdraw(native, param.ContractAddr, param.Initiator, param.Delegate, param.Role, param.KeyNo)
@@ -637,6 +661,52 @@ func VerifyToken(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_FALSE, nil
 }
 
+func findToken(tokens *roleTokens, role []byte) *AuthToken {
+	for _, token := range tokens.tokens {
+		if bytes.Compare(token.role, role) == 0 {
+			return token
+		}
+	}
+	return nil
+}
+
+/*
+ * listing methods for audit
+ */
+func GetRoles(native *native.NativeService) ([]byte, error) {
+	param := new(GetRolesParam)
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return nil, fmt.Errorf("[getRoles] deserialize param failed: %v", err)
+	}
+	roles, err := getRoles(native, param.ContractAddr)
+	if err != nil {
+		return nil, fmt.Errorf("[getRoles] getRoles failed: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint32(uint32(len(roles)))
+	for _, role := range roles {
+		role.Serialization(sink)
+	}
+	return sink.Bytes(), nil
+}
+
+func GetRoleHolders(native *native.NativeService) ([]byte, error) {
+	param := new(GetRoleHoldersParam)
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return nil, fmt.Errorf("[getRoleHolders] deserialize param failed: %v", err)
+	}
+	holders, err := getRoleHolders(native, param.ContractAddr, param.Role)
+	if err != nil {
+		return nil, fmt.Errorf("[getRoleHolders] getRoleHolders failed: %v", err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint32(uint32(len(holders)))
+	for _, holder := range holders {
+		holder.Serialization(sink)
+	}
+	return sink.Bytes(), nil
+}
+
 func verifySig(native *native.NativeService, ontID []byte, keyNo uint64) (bool, error) {
 	sink := common.NewZeroCopySink(nil)
 	sink.WriteVarBytes(ontID)
@@ -665,4 +735,10 @@ func RegisterAuthContract(native *native.NativeService) {
 	native.Register("assignOntIDsToRole", AssignOntIDsToRole)
 	native.Register("verifyToken", VerifyToken)
 	native.Register("transfer", Transfer)
+
+	//query methods are only served in pre-execution, they are not part of on-chain execution
+	if native.PreExec {
+		native.Register("getRoles", GetRoles)
+		native.Register("getRoleHolders", GetRoleHolders)
+	}
 }
@@ -0,0 +1,228 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package auth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/ontid"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+func init() {
+	ontid.Init()
+	Init()
+}
+
+func TestRoleExpiryAndListing(t *testing.T) {
+	testsuite.InvokeNativeContract(t, utils.AuthContractAddress,
+		func(n *native.NativeService) ([]byte, error) {
+			caseRoleExpiryAndListing(t, n)
+			return nil, nil
+		},
+	)
+}
+
+// Test case: assign roles with expiry and list roles and holders of contract
+func caseRoleExpiryAndListing(t *testing.T, n *native.NativeService) {
+	adminAcc := account.NewAccount("")
+	adminID := regTestID(t, n, adminAcc)
+	p1 := []byte(regTestID(t, n, account.NewAccount("")))
+	p2 := []byte(regTestID(t, n, account.NewAccount("")))
+	contract := n.ContextRef.CallingContext().ContractAddress
+
+	// 1. init admin and assign funcs to role
+	n.Input = common.SerializeToBytes(&InitContractAdminParam{AdminOntID: []byte(adminID)})
+	if _, err := InitContractAdmin(n); err != nil {
+		t.Fatal(err)
+	}
+	n.Tx.SignedAddr = []common.Address{adminAcc.Address}
+	n.Input = common.SerializeToBytes(&FuncsToRoleParam{
+		ContractAddr: contract,
+		AdminOntID:   []byte(adminID),
+		Role:         []byte(role),
+		FuncNames:    funcs,
+		KeyNo:        1,
+	})
+	if ret, err := AssignFuncsToRole(n); err != nil || string(ret) != string(utils.BYTE_TRUE) {
+		t.Fatalf("assign funcs to role failed: %v", err)
+	}
+
+	// 2. assign role with expire time before role expire height or in the past, should fail
+	expireHeight := config.GetRoleExpireHeight(config.DefConfig.P2PNode.NetworkId)
+	if err := assignTestRole(n, contract, adminID, n.Time+100, p1); err == nil {
+		t.Error("role assigned with expire time before role expire height")
+	}
+	n.Height = expireHeight
+	if err := assignTestRole(n, contract, adminID, n.Time, p1); err == nil {
+		t.Error("role assigned with expired time")
+	}
+
+	// 3. assign role to p1 with expire time and p2 permanently
+	expireTime := n.Time + 100
+	if err := assignTestRole(n, contract, adminID, expireTime, p1); err != nil {
+		t.Fatal(err)
+	}
+	states := n.Notifications[len(n.Notifications)-1].States.([]interface{})
+	if len(states) != 6 || states[3] != role || states[5] != expireTime {
+		t.Errorf("invalid event %v", states)
+	}
+	if err := assignTestRole(n, contract, adminID, 0, p2); err != nil {
+		t.Fatal(err)
+	}
+
+	// 4. list roles and holders
+	roles, err := getRoles(n, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roles) != 1 || string(roles[0].Role) != role || len(roles[0].FuncNames) != len(funcs) {
+		t.Fatalf("invalid roles %v", roles)
+	}
+	holders := getTestHolders(t, n, contract)
+	if holders[string(p1)].ExpireTime != expireTime || holders[string(p2)].ExpireTime != uint32(future.Unix()) {
+		t.Errorf("invalid expire time of holders")
+	}
+
+	// 5. role of p1 expires
+	if ok, _ := hasRole(n, contract, p1, []byte(role)); !ok {
+		t.Error("p1 should have the role")
+	}
+	n.Time = expireTime
+	if ok, _ := hasRole(n, contract, p1, []byte(role)); !ok {
+		t.Error("p1 should have the role until expire time")
+	}
+	n.Time = expireTime + 1
+	if ok, _ := hasRole(n, contract, p1, []byte(role)); ok {
+		t.Error("role of p1 should have expired")
+	}
+	n.Height = expireHeight - 1
+	if ok, _ := hasRole(n, contract, p1, []byte(role)); !ok {
+		t.Error("role of p1 should not expire before role expire height")
+	}
+	n.Height = expireHeight
+	if ok, _ := hasRole(n, contract, p2, []byte(role)); !ok {
+		t.Error("p2 should have the role")
+	}
+
+	// 6. renew role of p1
+	if err := assignTestRole(n, contract, adminID, 0, p1); err != nil {
+		t.Fatal(err)
+	}
+	holders = getTestHolders(t, n, contract)
+	if holders[string(p1)].ExpireTime != uint32(future.Unix()) {
+		t.Errorf("role of p1 is not renewed")
+	}
+	if ok, _ := hasRole(n, contract, p1, []byte(role)); !ok {
+		t.Error("p1 should have the role after renewal")
+	}
+
+	// 7. roles can expire since genesis on solo network
+	networkId := config.DefConfig.P2PNode.NetworkId
+	config.DefConfig.P2PNode.NetworkId = config.NETWORK_ID_SOLO_NET
+	defer func() { config.DefConfig.P2PNode.NetworkId = networkId }()
+	n.Height = 0
+	if err := assignTestRole(n, contract, adminID, n.Time+100, p2); err != nil {
+		t.Errorf("role with expire time should be assigned on solo network: %v", err)
+	}
+}
+
+func TestQueryMethodsPreExecOnly(t *testing.T) {
+	n := &native.NativeService{ServiceMap: make(map[string]native.Handler)}
+	RegisterAuthContract(n)
+	if _, ok := n.ServiceMap["getRoles"]; ok {
+		t.Error("getRoles should not be registered out of pre-execution")
+	}
+	if _, ok := n.ServiceMap["getRoleHolders"]; ok {
+		t.Error("getRoleHolders should not be registered out of pre-execution")
+	}
+
+	n = &native.NativeService{ServiceMap: make(map[string]native.Handler), PreExec: true}
+	RegisterAuthContract(n)
+	if _, ok := n.ServiceMap["getRoles"]; !ok {
+		t.Error("getRoles should be registered in pre-execution")
+	}
+	if _, ok := n.ServiceMap["getRoleHolders"]; !ok {
+		t.Error("getRoleHolders should be registered in pre-execution")
+	}
+}
+
+func regTestID(t *testing.T, n *native.NativeService, acc *account.Account) string {
+	id, err := account.GenerateID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes([]byte(id))
+	sink.WriteVarBytes(keypair.SerializePublicKey(acc.PubKey()))
+	n.Tx.SignedAddr = []common.Address{acc.Address}
+	if _, err := n.NativeCall(utils.OntIDContractAddress, "regIDWithPublicKey", sink.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func assignTestRole(n *native.NativeService, contract common.Address, adminID string, expireTime uint32,
+	persons ...[]byte) error {
+	n.Input = common.SerializeToBytes(&OntIDsToRoleParam{
+		ContractAddr: contract,
+		AdminOntID:   []byte(adminID),
+		Role:         []byte(role),
+		Persons:      persons,
+		KeyNo:        1,
+		ExpireTime:   expireTime,
+	})
+	ret, err := AssignOntIDsToRole(n)
+	if err != nil {
+		return err
+	}
+	if string(ret) != string(utils.BYTE_TRUE) {
+		return fmt.Errorf("assignOntIDsToRole returns false")
+	}
+	return nil
+}
+
+func getTestHolders(t *testing.T, n *native.NativeService, contract common.Address) map[string]*RoleHolder {
+	n.Input = common.SerializeToBytes(&GetRoleHoldersParam{ContractAddr: contract, Role: []byte(role)})
+	res, err := GetRoleHolders(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := common.NewZeroCopySource(res)
+	count, _ := source.NextUint32()
+	holders := make(map[string]*RoleHolder)
+	for i := uint32(0); i < count; i++ {
+		holder := new(RoleHolder)
+		if err := holder.Deserialization(source); err != nil {
+			t.Fatal(err)
+		}
+		holders[string(holder.OntID)] = holder
+	}
+	if len(holders) != 2 {
+		t.Fatalf("%d holders, expect 2", len(holders))
+	}
+	return holders
+}
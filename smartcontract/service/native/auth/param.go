@@ -133,6 +133,7 @@ type OntIDsToRoleParam struct {
 	Role         []byte
 	Persons      [][]byte
 	KeyNo        uint64
+	ExpireTime   uint32 //optional, 0 means the role never expires
 }
 
 func (this *OntIDsToRoleParam) Serialization(sink *common.ZeroCopySink) {
@@ -145,6 +146,7 @@ func (this *OntIDsToRoleParam) Serialization(sink *common.ZeroCopySink) {
 		sink.WriteVarBytes(p)
 	}
 	utils.EncodeVarUint(sink, this.KeyNo)
+	utils.EncodeVarUint(sink, uint64(this.ExpireTime))
 }
 
 func (this *OntIDsToRoleParam) Deserialization(source *common.ZeroCopySource) error {
@@ -173,6 +175,19 @@ func (this *OntIDsToRoleParam) Deserialization(source *common.ZeroCopySource) er
 	if this.KeyNo, err = utils.DecodeVarUint(source); err != nil {
 		return err
 	}
+	//expire time is absent in the param of earlier version
+	if source.Len() == 0 {
+		this.ExpireTime = 0
+		return nil
+	}
+	expireTime, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("ExpireTime Deserialization error: %s", err)
+	}
+	if expireTime > math.MaxUint32 {
+		return fmt.Errorf("ExpireTime is too large: %d", expireTime)
+	}
+	this.ExpireTime = uint32(expireTime)
 	return nil
 }
 
@@ -292,3 +307,40 @@ func (this *VerifyTokenParam) Deserialization(source *common.ZeroCopySource) err
 	}
 	return nil
 }
+
+type GetRolesParam struct {
+	ContractAddr common.Address
+}
+
+func (this *GetRolesParam) Serialization(sink *common.ZeroCopySink) {
+	serializeAddress(sink, this.ContractAddr)
+}
+
+func (this *GetRolesParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.ContractAddr, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	return nil
+}
+
+type GetRoleHoldersParam struct {
+	ContractAddr common.Address
+	Role         []byte
+}
+
+func (this *GetRoleHoldersParam) Serialization(sink *common.ZeroCopySink) {
+	serializeAddress(sink, this.ContractAddr)
+	sink.WriteVarBytes(this.Role)
+}
+
+func (this *GetRoleHoldersParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.ContractAddr, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Role, err = utils.DecodeVarBytes(source); err != nil {
+		return fmt.Errorf("Role Deserialization error: %s", err)
+	}
+	return nil
+}
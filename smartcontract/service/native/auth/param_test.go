@@ -123,6 +123,41 @@ func TestSerialization_AssignOntIDs(t *testing.T) {
 	assert.Equal(t, param, param2)
 }
 
+func TestSerialization_AssignOntIDsWithExpireTime(t *testing.T) {
+	param := &OntIDsToRoleParam{
+		ContractAddr: OntContractAddr,
+		AdminOntID:   admin,
+		Role:         []byte(role),
+		Persons:      [][]byte{p1, p2},
+		KeyNo:        1,
+		ExpireTime:   1600000000,
+	}
+	bf := common.NewZeroCopySink(nil)
+	param.Serialization(bf)
+	param2 := new(OntIDsToRoleParam)
+	if err := param2.Deserialization(common.NewZeroCopySource(bf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, param, param2)
+
+	//param without expire time
+	bf.Reset()
+	serializeAddress(bf, param.ContractAddr)
+	bf.WriteVarBytes(param.AdminOntID)
+	bf.WriteVarBytes(param.Role)
+	utils.EncodeVarUint(bf, uint64(len(param.Persons)))
+	for _, p := range param.Persons {
+		bf.WriteVarBytes(p)
+	}
+	utils.EncodeVarUint(bf, param.KeyNo)
+	param3 := new(OntIDsToRoleParam)
+	if err := param3.Deserialization(common.NewZeroCopySource(bf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	param.ExpireTime = 0
+	assert.Equal(t, param, param3)
+}
+
 func TestSerialization_Delegate(t *testing.T) {
 	param := &DelegateParam{
 		ContractAddr: OntContractAddr,
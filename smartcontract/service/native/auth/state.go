@@ -182,3 +182,77 @@ func (this *roleTokens) Deserialization(source *common.ZeroCopySource) error {
 	}
 	return nil
 }
+
+/*
+ * RoleInfo and RoleHolder are the results of the listing methods
+ */
+type RoleInfo struct {
+	Role      []byte
+	FuncNames []string
+}
+
+func (this *RoleInfo) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Role)
+	sink.WriteUint32(uint32(len(this.FuncNames)))
+	for _, fn := range this.FuncNames {
+		sink.WriteString(fn)
+	}
+}
+
+func (this *RoleInfo) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	this.Role, err = utils.DecodeVarBytes(source)
+	if err != nil {
+		return err
+	}
+	fnLen, eof := source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.FuncNames = make([]string, 0)
+	for i := uint32(0); i < fnLen; i++ {
+		fn, err := utils.DecodeString(source)
+		if err != nil {
+			return err
+		}
+		this.FuncNames = append(this.FuncNames, fn)
+	}
+	return nil
+}
+
+//Delegator is empty if the role is assigned by contract admin
+type RoleHolder struct {
+	OntID      []byte
+	Delegator  []byte
+	ExpireTime uint32
+	Level      uint8
+}
+
+func (this *RoleHolder) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.OntID)
+	sink.WriteVarBytes(this.Delegator)
+	sink.WriteUint32(this.ExpireTime)
+	sink.WriteUint8(this.Level)
+}
+
+func (this *RoleHolder) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	this.OntID, err = utils.DecodeVarBytes(source)
+	if err != nil {
+		return err
+	}
+	this.Delegator, err = utils.DecodeVarBytes(source)
+	if err != nil {
+		return err
+	}
+	var eof bool
+	this.ExpireTime, eof = source.NextUint32()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	this.Level, eof = source.NextUint8()
+	if eof {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
@@ -19,10 +19,12 @@
 package auth
 
 import (
+	"bytes"
 	"fmt"
 	"sort"
 
 	"github.com/ontio/ontology/common"
+	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -164,6 +166,137 @@ func putDelegateStatus(native *native.NativeService, contractAddr common.Address
 	return nil
 }
 
+//type(this.contractAddr.prefix) is the prefix of role funcs, tokens or delegate status of contractAddr
+func concatContractPrefix(native *native.NativeService, contractAddr common.Address, prefix []byte) []byte {
+	this := native.ContextRef.CurrentContext().ContractAddress
+	key := append(this[:], contractAddr[:]...)
+	key = append(key, prefix...)
+
+	return key
+}
+
+//iterate over the items stored under the prefix, suffix is the rest of the key, e.g. role or ontID
+func iterateContractItems(native *native.NativeService, contractAddr common.Address, prefix []byte,
+	handler func(suffix []byte, source *common.ZeroCopySource) error) error {
+	key := concatContractPrefix(native, contractAddr, prefix)
+	iter := native.CacheDB.NewIterator(key)
+	defer iter.Release()
+	for has := iter.First(); has; has = iter.Next() {
+		value, err := cstates.GetValueFromRawStorageItem(iter.Value())
+		if err != nil {
+			return err
+		}
+		suffix := append([]byte{}, iter.Key()[len(key):]...)
+		if err := handler(suffix, common.NewZeroCopySource(value)); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+//list the roles of contract, including the roles which are assigned to ontID but have no funcs
+func getRoles(native *native.NativeService, contractAddr common.Address) ([]*RoleInfo, error) {
+	roles := make(map[string]*RoleInfo)
+	addRole := func(role []byte) {
+		if _, ok := roles[string(role)]; !ok {
+			roles[string(role)] = &RoleInfo{Role: role, FuncNames: make([]string, 0)}
+		}
+	}
+	err := iterateContractItems(native, contractAddr, PreRoleFunc, func(role []byte, source *common.ZeroCopySource) error {
+		funcs := new(roleFuncs)
+		if err := funcs.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize roleFuncs object failed: %v", err)
+		}
+		roles[string(role)] = &RoleInfo{Role: role, FuncNames: funcs.funcNames}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = iterateContractItems(native, contractAddr, PreRoleToken, func(_ []byte, source *common.ZeroCopySource) error {
+		tokens := new(roleTokens)
+		if err := tokens.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize roleTokens object failed: %v", err)
+		}
+		for _, token := range tokens.tokens {
+			addRole(token.role)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = iterateContractItems(native, contractAddr, PreDelegateStatus, func(_ []byte, source *common.ZeroCopySource) error {
+		status := new(Status)
+		if err := status.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize Status object failed: %v", err)
+		}
+		for _, s := range status.status {
+			addRole(s.role)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := make([]*RoleInfo, 0, len(names))
+	for _, name := range names {
+		res = append(res, roles[name])
+	}
+	return res, nil
+}
+
+//list the ontIDs holding the role, both assigned by admin and delegated, expired ones included
+func getRoleHolders(native *native.NativeService, contractAddr common.Address, role []byte) ([]*RoleHolder, error) {
+	holders := make([]*RoleHolder, 0)
+	err := iterateContractItems(native, contractAddr, PreRoleToken, func(ontID []byte, source *common.ZeroCopySource) error {
+		tokens := new(roleTokens)
+		if err := tokens.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize roleTokens object failed: %v", err)
+		}
+		for _, token := range tokens.tokens {
+			if bytes.Equal(token.role, role) {
+				holders = append(holders, &RoleHolder{
+					OntID:      ontID,
+					ExpireTime: token.expireTime,
+					Level:      token.level,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = iterateContractItems(native, contractAddr, PreDelegateStatus, func(ontID []byte, source *common.ZeroCopySource) error {
+		status := new(Status)
+		if err := status.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize Status object failed: %v", err)
+		}
+		for _, s := range status.status {
+			if bytes.Equal(s.role, role) {
+				holders = append(holders, &RoleHolder{
+					OntID:      ontID,
+					Delegator:  s.root,
+					ExpireTime: s.expireTime,
+					Level:      s.level,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return holders, nil
+}
+
 //remove duplicates in the slice of string and sorts the slice in increasing order.
 func StringsDedupAndSort(s []string) []string {
 	smap := make(map[string]int)
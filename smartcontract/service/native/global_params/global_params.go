@@ -157,12 +157,15 @@ func SetOperator(native *native.NativeService) ([]byte, error) {
 
 func SetGlobalParam(native *native.NativeService) ([]byte, error) {
 	contract := native.ContextRef.CurrentContext().ContractAddress
-	operator, err := GetStorageRole(native, GenerateOperatorKey(contract))
-	if err != nil || operator == common.ADDRESS_EMPTY {
-		return utils.BYTE_FALSE, fmt.Errorf("set param, operator doesn't exist, caused by %v", err)
-	}
-	if !native.ContextRef.CheckWitness(operator) {
-		return utils.BYTE_FALSE, errors.NewErr("set param, authentication failed!")
+	// params can also be set by passed proposals of governance contract
+	if !native.ContextRef.CheckWitness(utils.GovernanceContractAddress) {
+		operator, err := GetStorageRole(native, GenerateOperatorKey(contract))
+		if err != nil || operator == common.ADDRESS_EMPTY {
+			return utils.BYTE_FALSE, fmt.Errorf("set param, operator doesn't exist, caused by %v", err)
+		}
+		if !native.ContextRef.CheckWitness(operator) {
+			return utils.BYTE_FALSE, errors.NewErr("set param, authentication failed!")
+		}
 	}
 	params := Params{}
 	if err := params.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
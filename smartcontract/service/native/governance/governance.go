@@ -23,12 +23,15 @@ package governance
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/common/config"
 	"github.com/ontio/ontology/common/constants"
+	vbftconfig "github.com/ontio/ontology/consensus/vbft/config"
 	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/global_params"
@@ -74,6 +77,21 @@ const (
 	REDUCE_INIT_POS                  = "reduceInitPos"
 	SET_PROMISE_POS                  = "setPromisePos"
 	SET_GAS_ADDRESS                  = "setGasAddress"
+	REPORT_DOUBLE_SIGN               = "reportDoubleSign"
+	CREATE_PROPOSAL                  = "createProposal"
+	VOTE_PROPOSAL                    = "voteProposal"
+	EXECUTE_PROPOSAL                 = "executeProposal"
+
+	//read-only function name
+	GET_PEER_POOL_LIST            = "getPeerPoolList"
+	GET_AUTHORIZE_INFO_BY_ADDRESS = "getAuthorizeInfoByAddress"
+	GET_SPLIT_FEE_ADDRESS         = "getSplitFeeAddress"
+	GET_TOTAL_STAKE               = "getTotalStake"
+	GET_GOVERNANCE_VIEW           = "getGovernanceView"
+	GET_PEER_ATTRIBUTES           = "getPeerAttributes"
+	GET_PROPOSAL                  = "getProposal"
+	GET_PROPOSAL_LIST             = "getProposalList"
+	ESTIMATE_STAKING_REWARD       = "estimateStakingReward"
 
 	//key prefix
 	GLOBAL_PARAM      = "globalParam"
@@ -93,12 +111,31 @@ const (
 	PROMISE_POS       = "promisePos"
 	PRE_CONFIG        = "preConfig"
 	GAS_ADDRESS       = "gasAddress"
+	DOUBLE_SIGN       = "doubleSign"
+	PROPOSAL          = "proposal"
+	PROPOSAL_INDEX    = "proposalIndex"
+	PROPOSAL_LIST     = "proposalList"
+	PROPOSAL_VOTE     = "proposalVote"
 
 	//global
 	PRECISE            = 1000000
 	NEW_VERSION_VIEW   = 6
 	NEW_VERSION_BLOCK  = 414100
 	NEW_WITHDRAW_BLOCK = 2800000
+
+	//dynamic block interval, ms
+	MIN_MAX_BLOCK_INTERVAL = 1000
+	MAX_MAX_BLOCK_INTERVAL = 600000
+
+	//proposal
+	PROPOSAL_VOTE_VIEWS   = 1    //views a proposal can still be voted after the view it is created in
+	MAX_ACTIVE_PROPOSALS  = 32   //max num of proposals in voting
+	MAX_PROPOSAL_DESC_LEN = 1024 //max length of proposal description
+	PROPOSAL_QUORUM       = 30   //percent of total stake that must vote
+	PROPOSAL_PASS_RATE    = 67   //percent of voted stake that must approve
+
+	//max rounds of staking reward simulation
+	MAX_SIMULATION_ROUNDS = 365
 )
 
 // candidate fee must >= 1 ONG
@@ -136,6 +173,22 @@ func RegisterGovernanceContract(native *native.NativeService) {
 	native.Register(WITHDRAW_FEE, WithdrawFee)
 	native.Register(ADD_INIT_POS, AddInitPos)
 	native.Register(REDUCE_INIT_POS, ReduceInitPos)
+	native.Register(REPORT_DOUBLE_SIGN, ReportDoubleSign)
+	native.Register(CREATE_PROPOSAL, CreateProposal)
+	native.Register(VOTE_PROPOSAL, VoteProposal)
+
+	//query methods are only served in pre-execution, they are not part of on-chain execution
+	if native.PreExec {
+		native.Register(GET_PEER_POOL_LIST, GetPeerPoolList)
+		native.Register(GET_AUTHORIZE_INFO_BY_ADDRESS, GetAuthorizeInfoByAddress)
+		native.Register(GET_SPLIT_FEE_ADDRESS, GetSplitFeeAddress)
+		native.Register(GET_TOTAL_STAKE, GetTotalStake)
+		native.Register(GET_GOVERNANCE_VIEW, GetGovernanceViewInfo)
+		native.Register(GET_PEER_ATTRIBUTES, GetPeerAttributes)
+		native.Register(GET_PROPOSAL, GetProposal)
+		native.Register(GET_PROPOSAL_LIST, GetProposalList)
+		native.Register(ESTIMATE_STAKING_REWARD, EstimateStakingReward)
+	}
 
 	native.Register(INIT_CONFIG, InitConfig)
 	native.Register(APPROVE_CANDIDATE, ApproveCandidate)
@@ -273,6 +326,9 @@ func InitConfig(native *native.NativeService) ([]byte, error) {
 		HashMsgDelay:         configuration.HashMsgDelay,
 		PeerHandshakeTimeout: configuration.PeerHandshakeTimeout,
 		MaxBlockChangeView:   configuration.MaxBlockChangeView,
+		BlockInterval:        configuration.BlockInterval,
+		MaxBlockInterval:     configuration.MaxBlockInterval,
+		TxThreshold:          configuration.TxThreshold,
 	}
 	err = putConfig(native, contract, config)
 	if err != nil {
@@ -619,27 +675,19 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	}
 	commit := false
 	for _, peerPubkey := range params.PeerPubkeyList {
-		peerPubkeyPrefix, err := hex.DecodeString(peerPubkey)
-		if err != nil {
-			return utils.BYTE_FALSE, fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
-		}
 		peerPoolItem, ok := peerPoolMap.PeerPoolMap[peerPubkey]
 		if !ok {
 			return utils.BYTE_FALSE, fmt.Errorf("blackNode, peerPubkey is not in peerPoolMap")
 		}
 
-		blackListItem := &BlackListItem{
-			PeerPubkey: peerPoolItem.PeerPubkey,
-			Address:    peerPoolItem.Address,
-			InitPos:    peerPoolItem.InitPos,
-		}
-		//put peer into black list
-		native.CacheDB.Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(common.SerializeToBytes(blackListItem)))
 		//change peerPool status
 		if peerPoolItem.Status == ConsensusStatus {
 			commit = true
 		}
-		peerPoolItem.Status = BlackStatus
+		err = blackPeer(native, contract, peerPoolItem)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("blackPeer, blackPeer error: %v", err)
+		}
 		peerPoolMap.PeerPoolMap[peerPubkey] = peerPoolItem
 	}
 	err = putPeerPoolMap(native, contract, view, peerPoolMap)
@@ -657,6 +705,69 @@ func BlackNode(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+//Report the evidence that a peer has signed conflicting proposals at one height,
+//anyone can report it. The peer is put into black list and its stake is
+//penalized when it quits
+func ReportDoubleSign(native *native.NativeService) ([]byte, error) {
+	params := new(DoubleSignEvidenceParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, contract params deserialize error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	peerPoolItem, ok := peerPoolMap.PeerPoolMap[params.PeerPubkey]
+	if !ok {
+		return utils.BYTE_FALSE, fmt.Errorf("reportDoubleSign, peerPubkey is not in peerPoolMap")
+	}
+	if peerPoolItem.Status == BlackStatus {
+		return utils.BYTE_FALSE, fmt.Errorf("reportDoubleSign, peer is already in black list")
+	}
+
+	//verify evidence
+	pubkey, err := vbftconfig.Pubkey(params.PeerPubkey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportDoubleSign, peerPubkey format error: %v", err)
+	}
+	height, err := vbftconfig.VerifyDoubleSign(pubkey, peerPoolItem.Index, params.Headers)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("reportDoubleSign, verify evidence error: %v", err)
+	}
+	err = putDoubleSignEvidence(native, contract, height, params)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putDoubleSignEvidence, put evidence error: %v", err)
+	}
+
+	commit := peerPoolItem.Status == ConsensusStatus
+	err = blackPeer(native, contract, peerPoolItem)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("blackPeer, blackPeer error: %v", err)
+	}
+	peerPoolMap.PeerPoolMap[params.PeerPubkey] = peerPoolItem
+	err = putPeerPoolMap(native, contract, view, peerPoolMap)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("putPeerPoolMap, put peerPoolMap error: %v", err)
+	}
+
+	//commitDpos
+	if commit {
+		err = executeCommitDpos(native, contract)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("executeCommitDpos, executeCommitDpos error: %v", err)
+		}
+	}
+	return utils.BYTE_TRUE, nil
+}
+
 //Remove a node from black list, allow it to be registered
 func WhiteNode(native *native.NativeService) ([]byte, error) {
 	params := new(WhiteNodeParam)
@@ -1023,73 +1134,14 @@ func UpdateConfig(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
-	//get globalParam
-	globalParam, err := getGlobalParam(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
-	}
-
 	configuration := new(Configuration)
 	if err := configuration.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize configuration error: %v", err)
 	}
 
-	//get current view
-	view, err := GetView(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
-	}
-	//get peerPoolMap
-	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
-	}
-	candidateNum := 0
-	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
-		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
-			candidateNum = candidateNum + 1
-		}
-	}
-
-	//check the configuration
-	if configuration.C == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. C can not be 0 in config")
-	}
-	if int(configuration.K) > candidateNum {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. K can not be larger than num of candidate peer in config")
-	}
-	if configuration.L < 16*configuration.K || configuration.L%configuration.K != 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. L can not be less than 16*K and K must be times of L in config")
-	}
-	if configuration.K < 2*configuration.C+1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. K can not be less than 2*C+1 in config")
-	}
-	if 4*configuration.K > globalParam.CandidateNum {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. 4*K can not be more than candidateNum")
-	}
-	if configuration.N < configuration.K || configuration.K < 7 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. config not match N >= K >= 7")
-	}
-	if configuration.BlockMsgDelay < 5000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
-	}
-	if configuration.HashMsgDelay < 5000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
-	}
-	if configuration.PeerHandshakeTimeout < 10 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
-	}
-	if configuration.MaxBlockChangeView < 10000 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateConfig. MaxBlockChangeView must >= 10000")
-	}
-
-	preConfig := &PreConfig{
-		Configuration: configuration,
-		SetView:       view,
-	}
-	err = putPreConfig(native, contract, preConfig)
+	err = updateConfig(native, contract, configuration)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putPreConfig, put preConfig error: %v", err)
+		return utils.BYTE_FALSE, fmt.Errorf("updateConfig, update config error: %v", err)
 	}
 
 	return utils.BYTE_TRUE, nil
@@ -1111,42 +1163,14 @@ func UpdateGlobalParam(native *native.NativeService) ([]byte, error) {
 	}
 	contract := native.ContextRef.CurrentContext().ContractAddress
 
-	// get config
-	config, err := getConfig(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
-	}
-
 	globalParam := new(GlobalParam)
 	if err := globalParam.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize globalParam error: %v", err)
 	}
 
-	//check the globalParam
-	if (globalParam.A + globalParam.B) != 100 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. A + B must equal to 100")
-	}
-	if globalParam.Yita == 0 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. Yita must > 0")
-	}
-	if globalParam.Penalty > 100 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. Penalty must <= 100")
-	}
-	if globalParam.PosLimit < 1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. PosLimit must >= 1")
-	}
-	if globalParam.CandidateNum < 4*config.K {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. CandidateNum must >= 4*K")
-	}
-	if globalParam.CandidateFee != 0 && globalParam.CandidateFee < MIN_CANDIDATE_FEE {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. CandidateFee must >= %d", MIN_CANDIDATE_FEE)
-	}
-	if globalParam.MinInitStake < 1 {
-		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam. MinInitStake must >= 1")
-	}
-	err = putGlobalParam(native, contract, globalParam)
+	err = updateGlobalParam(native, contract, globalParam)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putGlobalParam, put globalParam error: %v", err)
+		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam, update globalParam error: %v", err)
 	}
 
 	return utils.BYTE_TRUE, nil
@@ -1176,18 +1200,9 @@ func UpdateGlobalParam2(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, fmt.Errorf("deserialize, deserialize globalParam2 error: %v", err)
 	}
 
-	// get config
-	config, err := getConfig(native, contract)
-	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
-	}
-	if globalParam2.CandidateFeeSplitNum < config.K {
-		return utils.BYTE_FALSE, fmt.Errorf("globalParam2.CandidateFeeSplitNum can not be less than config.K")
-	}
-
-	err = putGlobalParam2(native, contract, globalParam2)
+	err = updateGlobalParam2(native, contract, globalParam2)
 	if err != nil {
-		return utils.BYTE_FALSE, fmt.Errorf("putGlobalParam2, put globalParam2 error: %v", err)
+		return utils.BYTE_FALSE, fmt.Errorf("updateGlobalParam2, update globalParam2 error: %v", err)
 	}
 
 	return utils.BYTE_TRUE, nil
@@ -1674,3 +1689,401 @@ func SetGasAddress(native *native.NativeService) ([]byte, error) {
 
 	return utils.BYTE_TRUE, nil
 }
+
+//Create a proposal to change governance params, used by candidates and stakers.
+//The proposal is voted weighted by stake and executed at commitDpos when passed
+func CreateProposal(native *native.NativeService) ([]byte, error) {
+	params := new(CreateProposalParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, contract params deserialize error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Proposer)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, checkWitness error: %v", err)
+	}
+	if len(params.Description) > MAX_PROPOSAL_DESC_LEN {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, description is longer than %d", MAX_PROPOSAL_DESC_LEN)
+	}
+	if err := checkProposalContent(params.Type, params.Content); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, content of proposal is invalid: %v", err)
+	}
+	if err := checkProposer(native, contract, params.Proposer); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, %v", err)
+	}
+
+	proposalList, err := getProposalList(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalList, get proposalList error: %v", err)
+	}
+	if len(proposalList.IDs) >= MAX_ACTIVE_PROPOSALS {
+		return utils.BYTE_FALSE, fmt.Errorf("createProposal, num of proposals in voting reaches %d", MAX_ACTIVE_PROPOSALS)
+	}
+	for _, id := range proposalList.IDs {
+		proposal, err := getProposal(native, contract, id)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("getProposal, get proposal error: %v", err)
+		}
+		if proposal.Proposer == params.Proposer {
+			return utils.BYTE_FALSE, fmt.Errorf("createProposal, proposer already has a proposal in voting")
+		}
+	}
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	id, err := getProposalIndex(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalIndex, get proposalIndex error: %v", err)
+	}
+	proposal := &Proposal{
+		ID:          id,
+		Proposer:    params.Proposer,
+		Type:        params.Type,
+		Content:     params.Content,
+		Description: params.Description,
+		StartView:   view,
+		EndView:     view + PROPOSAL_VOTE_VIEWS,
+		Status:      ProposalVotingStatus,
+	}
+	putProposal(native, contract, proposal)
+	putProposalIndex(native, contract, id+1)
+	proposalList.IDs = append(proposalList.IDs, id)
+	putProposalList(native, contract, proposalList)
+
+	notifyProposal(native, contract, CREATE_PROPOSAL, proposal)
+	return utils.BYTE_TRUE, nil
+}
+
+//Vote for a proposal in voting, the weight of vote is the total stake of voter.
+//Voting again replaces the previous vote of voter
+func VoteProposal(native *native.NativeService) ([]byte, error) {
+	params := new(VoteProposalParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, contract params deserialize error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//check witness
+	err := utils.ValidateOwner(native, params.Voter)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, checkWitness error: %v", err)
+	}
+
+	proposal, err := getProposal(native, contract, params.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposal, get proposal error: %v", err)
+	}
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	if proposal.Status != ProposalVotingStatus || view > proposal.EndView {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, proposal is not in voting")
+	}
+
+	totalStake, err := getTotalStake(native, contract, params.Voter)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getTotalStake, get totalStake error: %v", err)
+	}
+	if totalStake.Stake == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("voteProposal, voter has no stake")
+	}
+
+	//revoke previous vote
+	vote, err := getProposalVote(native, contract, params.ID, params.Voter)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalVote, get proposalVote error: %v", err)
+	}
+	if vote != nil {
+		if vote.Approve {
+			proposal.ApproveStake = proposal.ApproveStake - vote.Stake
+		} else {
+			proposal.RejectStake = proposal.RejectStake - vote.Stake
+		}
+	}
+
+	vote = &ProposalVote{
+		Approve: params.Approve,
+		Stake:   totalStake.Stake,
+	}
+	if vote.Approve {
+		proposal.ApproveStake = proposal.ApproveStake + vote.Stake
+	} else {
+		proposal.RejectStake = proposal.RejectStake + vote.Stake
+	}
+	putProposalVote(native, contract, params.ID, params.Voter, vote)
+	putProposal(native, contract, proposal)
+
+	notifyProposal(native, contract, VOTE_PROPOSAL, proposal)
+	return utils.BYTE_TRUE, nil
+}
+
+//Get peers of current view in json, ordered by index
+func GetPeerPoolList(native *native.NativeService) ([]byte, error) {
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	peers := make([]*PeerPoolItemRsp, 0, len(peerPoolMap.PeerPoolMap))
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		peers = append(peers, newPeerPoolItemRsp(peerPoolItem))
+	}
+	sort.SliceStable(peers, func(i, j int) bool {
+		return peers[i].Index < peers[j].Index
+	})
+	return json.Marshal(peers)
+}
+
+//Get authorize info of an address for all peers of current view in json
+func GetAuthorizeInfoByAddress(native *native.NativeService) ([]byte, error) {
+	address, err := utils.DecodeAddress(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getAuthorizeInfoByAddress, deserialize address error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	peerPubkeys := make([]string, 0, len(peerPoolMap.PeerPoolMap))
+	for peerPubkey := range peerPoolMap.PeerPoolMap {
+		peerPubkeys = append(peerPubkeys, peerPubkey)
+	}
+	sort.Strings(peerPubkeys)
+
+	infos := make([]*AuthorizeInfoRsp, 0)
+	for _, peerPubkey := range peerPubkeys {
+		authorizeInfo, err := getAuthorizeInfo(native, contract, peerPubkey, address)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("getAuthorizeInfo, get authorizeInfo error: %v", err)
+		}
+		if authorizeInfo.ConsensusPos != 0 || authorizeInfo.CandidatePos != 0 || authorizeInfo.NewPos != 0 ||
+			authorizeInfo.WithdrawConsensusPos != 0 || authorizeInfo.WithdrawCandidatePos != 0 ||
+			authorizeInfo.WithdrawUnfreezePos != 0 {
+			infos = append(infos, newAuthorizeInfoRsp(authorizeInfo))
+		}
+	}
+	return json.Marshal(infos)
+}
+
+//Get ong motivation of an address in json
+func GetSplitFeeAddress(native *native.NativeService) ([]byte, error) {
+	address, err := utils.DecodeAddress(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getSplitFeeAddress, deserialize address error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	splitFeeAddress, err := getSplitFeeAddress(native, contract, address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getSplitFeeAddress, get splitFeeAddress error: %v", err)
+	}
+	return json.Marshal(&SplitFeeAddressRsp{
+		Address: address.ToBase58(),
+		Amount:  splitFeeAddress.Amount,
+	})
+}
+
+//Get total stake of an address in json
+func GetTotalStake(native *native.NativeService) ([]byte, error) {
+	address, err := utils.DecodeAddress(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getTotalStake, deserialize address error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	totalStake, err := getTotalStake(native, contract, address)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getTotalStake, get totalStake error: %v", err)
+	}
+	return json.Marshal(&TotalStakeRsp{
+		Address:    address.ToBase58(),
+		Stake:      totalStake.Stake,
+		TimeOffset: totalStake.TimeOffset,
+	})
+}
+
+//Get governance view in json
+func GetGovernanceViewInfo(native *native.NativeService) ([]byte, error) {
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	governanceView, err := GetGovernanceView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getGovernanceView, get governanceView error: %v", err)
+	}
+	return json.Marshal(&GovernanceViewRsp{
+		View:   governanceView.View,
+		Height: governanceView.Height,
+		TxHash: governanceView.TxHash.ToHexString(),
+	})
+}
+
+//Get attributes of a peer in json
+func GetPeerAttributes(native *native.NativeService) ([]byte, error) {
+	peerPubkey, err := utils.DecodeString(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerAttributes, deserialize peerPubkey error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	peerAttributes, err := getPeerAttributes(native, contract, peerPubkey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerAttributes, get peerAttributes error: %v", err)
+	}
+	return json.Marshal(&PeerAttributesRsp{
+		PeerPubkey:   peerAttributes.PeerPubkey,
+		MaxAuthorize: peerAttributes.MaxAuthorize,
+		T2PeerCost:   peerAttributes.T2PeerCost,
+		T1PeerCost:   peerAttributes.T1PeerCost,
+		TPeerCost:    peerAttributes.TPeerCost,
+	})
+}
+
+//Get a proposal by id in json
+func GetProposal(native *native.NativeService) ([]byte, error) {
+	id, err := utils.DecodeVarUint(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposal, deserialize id error: %v", err)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	proposal, err := getProposal(native, contract, id)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposal, get proposal error: %v", err)
+	}
+	return json.Marshal(newProposalRsp(proposal))
+}
+
+//Get proposals in voting in json
+func GetProposalList(native *native.NativeService) ([]byte, error) {
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	proposalList, err := getProposalList(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getProposalList, get proposalList error: %v", err)
+	}
+	proposals := make([]*ProposalRsp, 0, len(proposalList.IDs))
+	for _, id := range proposalList.IDs {
+		proposal, err := getProposal(native, contract, id)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("getProposal, get proposal error: %v", err)
+		}
+		proposals = append(proposals, newProposalRsp(proposal))
+	}
+	return json.Marshal(proposals)
+}
+
+//Estimate the ong income of authorizing pos to a peer for some rounds in json,
+//based on the state of current view
+func EstimateStakingReward(native *native.NativeService) ([]byte, error) {
+	params := new(EstimateStakingRewardParam)
+	if err := params.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("deserialize, contract params deserialize error: %v", err)
+	}
+	if params.Rounds == 0 || params.Rounds > MAX_SIMULATION_ROUNDS {
+		return utils.BYTE_FALSE, fmt.Errorf("estimateStakingReward, rounds must be in [1, %d]", MAX_SIMULATION_ROUNDS)
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	configuration, err := getConfig(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getConfig, get config error: %v", err)
+	}
+	globalParam, err := getGlobalParam(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
+	}
+	globalParam2, err := getGlobalParam2(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getGlobalParam2, getGlobalParam2 error: %v", err)
+	}
+	splitCurve, err := getSplitCurve(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getSplitCurve, get splitCurve error: %v", err)
+	}
+	peerAttributes, err := getPeerAttributes(native, contract, params.PeerPubkey)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getPeerAttributes, get peerAttributes error: %v", err)
+	}
+	gasAddress, err := getGasAddress(native, contract)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("getGasAddress, getGasAddress error: %v", err)
+	}
+
+	feePerRound := params.FeePerRound
+	if feePerRound == 0 {
+		balance, err := getOngBalance(native, utils.GovernanceContractAddress)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("getOngBalance, getOngBalance error: %v", err)
+		}
+		splitFee, err := getSplitFee(native, contract)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("getSplitFee, getSplitFee error: %v", err)
+		}
+		if balance > splitFee {
+			feePerRound = balance - splitFee
+		}
+	}
+	roundSeconds := params.RoundSeconds
+	if roundSeconds == 0 {
+		roundSeconds = configuration.MaxBlockChangeView * config.DEFAULT_GEN_BLOCK_TIME
+	}
+
+	peers := make([]*PeerPoolItem, 0, len(peerPoolMap.PeerPoolMap))
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		peers = append(peers, peerPoolItem)
+	}
+	sort.SliceStable(peers, func(i, j int) bool {
+		return peers[i].Index < peers[j].Index
+	})
+	rsp, err := SimulateStakingReward(&RewardSimulation{
+		Peers:          peers,
+		PeerAttributes: peerAttributes,
+		Config:         configuration,
+		GlobalParam:    globalParam,
+		GlobalParam2:   globalParam2,
+		SplitCurve:     splitCurve,
+		HasGasAddress:  gasAddress.Address != common.ADDRESS_EMPTY,
+		PeerPubkey:     params.PeerPubkey,
+		Pos:            params.Pos,
+		FeePerRound:    feePerRound,
+		Rounds:         params.Rounds,
+		RoundSeconds:   roundSeconds,
+		TimeOffset:     native.Time - constants.GENESIS_BLOCK_TIMESTAMP,
+	})
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("estimateStakingReward, %v", err)
+	}
+	return json.Marshal(rsp)
+}
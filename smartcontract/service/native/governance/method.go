@@ -28,6 +28,7 @@ import (
 	"github.com/ontio/ontology/common/constants"
 	cstates "github.com/ontio/ontology/core/states"
 	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/global_params"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 )
 
@@ -318,6 +319,24 @@ func normalQuit(native *native.NativeService, contract common.Address, peerPoolI
 	return nil
 }
 
+//blackPeer put the peer into black list and mark it black, it quits and is
+//penalized at next commitDpos
+func blackPeer(native *native.NativeService, contract common.Address, peerPoolItem *PeerPoolItem) error {
+	peerPubkeyPrefix, err := hex.DecodeString(peerPoolItem.PeerPubkey)
+	if err != nil {
+		return fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	blackListItem := &BlackListItem{
+		PeerPubkey: peerPoolItem.PeerPubkey,
+		Address:    peerPoolItem.Address,
+		InitPos:    peerPoolItem.InitPos,
+	}
+	//put peer into black list
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(BLACK_LIST), peerPubkeyPrefix), cstates.GenRawStorageItem(common.SerializeToBytes(blackListItem)))
+	peerPoolItem.Status = BlackStatus
+	return nil
+}
+
 func blackQuit(native *native.NativeService, contract common.Address, peerPoolItem *PeerPoolItem) error {
 	// ont transfer to trigger unboundong
 	err := appCallTransferOnt(native, utils.GovernanceContractAddress, utils.GovernanceContractAddress, peerPoolItem.InitPos)
@@ -688,6 +707,12 @@ func executeCommitDpos(native *native.NativeService, contract common.Address) er
 	//get current view
 	view := governanceView.View
 
+	//execute passed proposals before entering the new epoch
+	err = executeProposals(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("executeProposals error: %v", err)
+	}
+
 	if view <= NEW_VERSION_VIEW {
 		err = executeCommitDpos1(native, contract)
 		if err != nil {
@@ -1374,3 +1399,284 @@ func splitNodeFee(native *native.NativeService, contract common.Address, peerPub
 	}
 	return nil
 }
+
+func updateConfig(native *native.NativeService, contract common.Address, configuration *Configuration) error {
+	//get globalParam
+	globalParam, err := getGlobalParam(native, contract)
+	if err != nil {
+		return fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
+	}
+
+	//get current view
+	view, err := GetView(native, contract)
+	if err != nil {
+		return fmt.Errorf("getView, get view error: %v", err)
+	}
+	//get peerPoolMap
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	candidateNum := 0
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus {
+			candidateNum = candidateNum + 1
+		}
+	}
+
+	//check the configuration
+	if configuration.C == 0 {
+		return fmt.Errorf("updateConfig. C can not be 0 in config")
+	}
+	if int(configuration.K) > candidateNum {
+		return fmt.Errorf("updateConfig. K can not be larger than num of candidate peer in config")
+	}
+	if configuration.L < 16*configuration.K || configuration.L%configuration.K != 0 {
+		return fmt.Errorf("updateConfig. L can not be less than 16*K and K must be times of L in config")
+	}
+	if configuration.K < 2*configuration.C+1 {
+		return fmt.Errorf("updateConfig. K can not be less than 2*C+1 in config")
+	}
+	if 4*configuration.K > globalParam.CandidateNum {
+		return fmt.Errorf("updateConfig. 4*K can not be more than candidateNum")
+	}
+	if configuration.N < configuration.K || configuration.K < 7 {
+		return fmt.Errorf("updateConfig. config not match N >= K >= 7")
+	}
+	if configuration.BlockMsgDelay < 5000 {
+		return fmt.Errorf("updateConfig. BlockMsgDelay must >= 5000")
+	}
+	if configuration.HashMsgDelay < 5000 {
+		return fmt.Errorf("updateConfig. HashMsgDelay must >= 5000")
+	}
+	if configuration.PeerHandshakeTimeout < 10 {
+		return fmt.Errorf("updateConfig. PeerHandshakeTimeout must >= 10")
+	}
+	if configuration.MaxBlockChangeView < 10000 {
+		return fmt.Errorf("updateConfig. MaxBlockChangeView must >= 10000")
+	}
+	if err := checkBlockInterval(configuration.BlockInterval, configuration.MaxBlockInterval); err != nil {
+		return fmt.Errorf("updateConfig. %v", err)
+	}
+
+	preConfig := &PreConfig{
+		Configuration: configuration,
+		SetView:       view,
+	}
+	err = putPreConfig(native, contract, preConfig)
+	if err != nil {
+		return fmt.Errorf("putPreConfig, put preConfig error: %v", err)
+	}
+	return nil
+}
+
+func updateGlobalParam(native *native.NativeService, contract common.Address, globalParam *GlobalParam) error {
+	// get config
+	config, err := getConfig(native, contract)
+	if err != nil {
+		return fmt.Errorf("getConfig, get config error: %v", err)
+	}
+
+	//check the globalParam
+	if (globalParam.A + globalParam.B) != 100 {
+		return fmt.Errorf("updateGlobalParam. A + B must equal to 100")
+	}
+	if globalParam.Yita == 0 {
+		return fmt.Errorf("updateGlobalParam. Yita must > 0")
+	}
+	if globalParam.Penalty > 100 {
+		return fmt.Errorf("updateGlobalParam. Penalty must <= 100")
+	}
+	if globalParam.PosLimit < 1 {
+		return fmt.Errorf("updateGlobalParam. PosLimit must >= 1")
+	}
+	if globalParam.CandidateNum < 4*config.K {
+		return fmt.Errorf("updateGlobalParam. CandidateNum must >= 4*K")
+	}
+	if globalParam.CandidateFee != 0 && globalParam.CandidateFee < MIN_CANDIDATE_FEE {
+		return fmt.Errorf("updateGlobalParam. CandidateFee must >= %d", MIN_CANDIDATE_FEE)
+	}
+	if globalParam.MinInitStake < 1 {
+		return fmt.Errorf("updateGlobalParam. MinInitStake must >= 1")
+	}
+	err = putGlobalParam(native, contract, globalParam)
+	if err != nil {
+		return fmt.Errorf("putGlobalParam, put globalParam error: %v", err)
+	}
+	return nil
+}
+
+func updateGlobalParam2(native *native.NativeService, contract common.Address, globalParam2 *GlobalParam2) error {
+	// get config
+	config, err := getConfig(native, contract)
+	if err != nil {
+		return fmt.Errorf("getConfig, get config error: %v", err)
+	}
+	if globalParam2.CandidateFeeSplitNum < config.K {
+		return fmt.Errorf("globalParam2.CandidateFeeSplitNum can not be less than config.K")
+	}
+
+	err = putGlobalParam2(native, contract, globalParam2)
+	if err != nil {
+		return fmt.Errorf("putGlobalParam2, put globalParam2 error: %v", err)
+	}
+	return nil
+}
+
+//checkProposalContent check that the content of proposal can be decoded as the
+//param of its type, the param itself is checked when the proposal is executed
+func checkProposalContent(proposalType ProposalType, content []byte) error {
+	source := common.NewZeroCopySource(content)
+	switch proposalType {
+	case UpdateGlobalParamProposal:
+		return new(GlobalParam).Deserialization(source)
+	case UpdateGlobalParam2Proposal:
+		return new(GlobalParam2).Deserialization(source)
+	case UpdateSplitCurveProposal:
+		return new(SplitCurve).Deserialization(source)
+	case UpdateConfigProposal:
+		return new(Configuration).Deserialization(source)
+	case SetGlobalParamProposal:
+		params := global_params.Params{}
+		if err := params.Deserialization(source); err != nil {
+			return err
+		}
+		if len(params) == 0 {
+			return fmt.Errorf("params is nil")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown proposal type %d", proposalType)
+	}
+}
+
+//checkProposer check whether address can create a proposal, it must be the owner
+//of a candidate or consensus peer, or have at least MinInitStake ONT staked
+func checkProposer(native *native.NativeService, contract common.Address, address common.Address) error {
+	view, err := GetView(native, contract)
+	if err != nil {
+		return fmt.Errorf("getView, get view error: %v", err)
+	}
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Address == address &&
+			(peerPoolItem.Status == CandidateStatus || peerPoolItem.Status == ConsensusStatus) {
+			return nil
+		}
+	}
+	globalParam, err := getGlobalParam(native, contract)
+	if err != nil {
+		return fmt.Errorf("getGlobalParam, getGlobalParam error: %v", err)
+	}
+	totalStake, err := getTotalStake(native, contract, address)
+	if err != nil {
+		return fmt.Errorf("getTotalStake, get totalStake error: %v", err)
+	}
+	if totalStake.Stake < uint64(globalParam.MinInitStake) {
+		return fmt.Errorf("proposer is not a candidate and stake is less than %d", globalParam.MinInitStake)
+	}
+	return nil
+}
+
+//proposalPassed check whether the voted stake reaches the quorum of total stake
+//and the approved stake reaches the pass rate of voted stake
+func proposalPassed(proposal *Proposal, totalStake uint64) bool {
+	voted := proposal.ApproveStake + proposal.RejectStake
+	if voted == 0 || voted*100 < totalStake*PROPOSAL_QUORUM {
+		return false
+	}
+	return proposal.ApproveStake*100 >= voted*PROPOSAL_PASS_RATE
+}
+
+func executeProposal(native *native.NativeService, contract common.Address, proposal *Proposal) error {
+	source := common.NewZeroCopySource(proposal.Content)
+	switch proposal.Type {
+	case UpdateGlobalParamProposal:
+		globalParam := new(GlobalParam)
+		if err := globalParam.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize, deserialize globalParam error: %v", err)
+		}
+		return updateGlobalParam(native, contract, globalParam)
+	case UpdateGlobalParam2Proposal:
+		if native.Height < NEW_VERSION_BLOCK {
+			return fmt.Errorf("block num is not reached for this func")
+		}
+		globalParam2 := new(GlobalParam2)
+		if err := globalParam2.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize, deserialize globalParam2 error: %v", err)
+		}
+		return updateGlobalParam2(native, contract, globalParam2)
+	case UpdateSplitCurveProposal:
+		splitCurve := new(SplitCurve)
+		if err := splitCurve.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize, deserialize splitCurve error: %v", err)
+		}
+		return putSplitCurve(native, contract, splitCurve)
+	case UpdateConfigProposal:
+		configuration := new(Configuration)
+		if err := configuration.Deserialization(source); err != nil {
+			return fmt.Errorf("deserialize, deserialize configuration error: %v", err)
+		}
+		return updateConfig(native, contract, configuration)
+	case SetGlobalParamProposal:
+		//params are checked when proposal is created, native call can not fail here
+		if _, err := native.NativeCall(utils.ParamContractAddress, global_params.SET_GLOBAL_PARAM_NAME, proposal.Content); err != nil {
+			return fmt.Errorf("appCallSetGlobalParam, appCall error: %v", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown proposal type %d", proposal.Type)
+	}
+}
+
+//executeProposals tally the proposals whose voting period is over, passed proposals
+//are executed before the new consensus epoch
+func executeProposals(native *native.NativeService, contract common.Address, view uint32) error {
+	proposalList, err := getProposalList(native, contract)
+	if err != nil {
+		return fmt.Errorf("getProposalList, get proposalList error: %v", err)
+	}
+	if len(proposalList.IDs) == 0 {
+		return nil
+	}
+
+	//total stake of all peers not in black list
+	peerPoolMap, err := GetPeerPoolMap(native, contract, view)
+	if err != nil {
+		return fmt.Errorf("getPeerPoolMap, get peerPoolMap error: %v", err)
+	}
+	var totalStake uint64
+	for _, peerPoolItem := range peerPoolMap.PeerPoolMap {
+		if peerPoolItem.Status != BlackStatus {
+			totalStake = totalStake + peerPoolItem.InitPos + peerPoolItem.TotalPos
+		}
+	}
+
+	remain := make([]uint64, 0, len(proposalList.IDs))
+	for _, id := range proposalList.IDs {
+		proposal, err := getProposal(native, contract, id)
+		if err != nil {
+			return fmt.Errorf("getProposal, get proposal error: %v", err)
+		}
+		if view < proposal.EndView {
+			remain = append(remain, id)
+			continue
+		}
+		if !proposalPassed(proposal, totalStake) {
+			proposal.Status = ProposalRejectedStatus
+		} else if err := executeProposal(native, contract, proposal); err != nil {
+			//a failed proposal must not block commitDpos
+			proposal.Status = ProposalFailedStatus
+		} else {
+			proposal.Status = ProposalExecutedStatus
+		}
+		putProposal(native, contract, proposal)
+		notifyProposal(native, contract, EXECUTE_PROPOSAL, proposal)
+	}
+	proposalList.IDs = remain
+	putProposalList(native, contract, proposalList)
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"math"
 
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 )
 
@@ -187,6 +188,45 @@ func (this *BlackNodeParam) Deserialization(source *common.ZeroCopySource) error
 	return nil
 }
 
+type DoubleSignEvidenceParam struct {
+	PeerPubkey string
+	Headers    []*types.Header
+}
+
+func (this *DoubleSignEvidenceParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.PeerPubkey)
+	utils.EncodeVarUint(sink, uint64(len(this.Headers)))
+	for _, v := range this.Headers {
+		sink.WriteVarBytes(common.SerializeToBytes(v))
+	}
+}
+
+func (this *DoubleSignEvidenceParam) Deserialization(source *common.ZeroCopySource) error {
+	peerPubkey, err := utils.DecodeString(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize peerPubkey error: %v", err)
+	}
+	n, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarUint, deserialize headers length error: %v", err)
+	}
+	headers := make([]*types.Header, 0)
+	for i := 0; uint64(i) < n; i++ {
+		raw, err := utils.DecodeVarBytes(source)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadVarBytes, deserialize header error: %v", err)
+		}
+		header, err := types.HeaderFromRawBytes(raw)
+		if err != nil {
+			return fmt.Errorf("types.HeaderFromRawBytes, deserialize header error: %v", err)
+		}
+		headers = append(headers, header)
+	}
+	this.PeerPubkey = peerPubkey
+	this.Headers = headers
+	return nil
+}
+
 type WhiteNodeParam struct {
 	PeerPubkey string
 }
@@ -352,9 +392,17 @@ type Configuration struct {
 	HashMsgDelay         uint32
 	PeerHandshakeTimeout uint32
 	MaxBlockChangeView   uint32
+	BlockInterval        uint32 //ms, max waiting time of txs less than TxThreshold
+	MaxBlockInterval     uint32 //ms, interval of empty blocks, zero disables dynamic block interval
+	TxThreshold          uint32 //txs in pool to propose block at once
 }
 
 func (this *Configuration) Serialization(sink *common.ZeroCopySink) {
+	this.serializeBase(sink)
+	this.serializeBlockInterval(sink)
+}
+
+func (this *Configuration) serializeBase(sink *common.ZeroCopySink) {
 	utils.EncodeVarUint(sink, uint64(this.N))
 	utils.EncodeVarUint(sink, uint64(this.C))
 	utils.EncodeVarUint(sink, uint64(this.K))
@@ -365,7 +413,25 @@ func (this *Configuration) Serialization(sink *common.ZeroCopySink) {
 	utils.EncodeVarUint(sink, uint64(this.MaxBlockChangeView))
 }
 
+//block interval params are appended only if dynamic block interval is enabled,
+//so that configs stored before stay the same
+func (this *Configuration) serializeBlockInterval(sink *common.ZeroCopySink) {
+	if this.MaxBlockInterval == 0 {
+		return
+	}
+	utils.EncodeVarUint(sink, uint64(this.BlockInterval))
+	utils.EncodeVarUint(sink, uint64(this.MaxBlockInterval))
+	utils.EncodeVarUint(sink, uint64(this.TxThreshold))
+}
+
 func (this *Configuration) Deserialization(source *common.ZeroCopySource) error {
+	if err := this.deserializeBase(source); err != nil {
+		return err
+	}
+	return this.deserializeBlockInterval(source)
+}
+
+func (this *Configuration) deserializeBase(source *common.ZeroCopySource) error {
 	n, err := utils.DecodeVarUint(source)
 	if err != nil {
 		return fmt.Errorf("utils.ReadVarUint, deserialize n error: %v", err)
@@ -433,19 +499,51 @@ func (this *Configuration) Deserialization(source *common.ZeroCopySource) error
 	return nil
 }
 
+func (this *Configuration) deserializeBlockInterval(source *common.ZeroCopySource) error {
+	if source.Len() == 0 {
+		return nil
+	}
+	blockInterval, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize blockInterval error: %v", err)
+	}
+	maxBlockInterval, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize maxBlockInterval error: %v", err)
+	}
+	txThreshold, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize txThreshold error: %v", err)
+	}
+	if blockInterval > math.MaxUint32 {
+		return fmt.Errorf("blockInterval larger than max of uint32")
+	}
+	if maxBlockInterval > math.MaxUint32 {
+		return fmt.Errorf("maxBlockInterval larger than max of uint32")
+	}
+	if txThreshold > math.MaxUint32 {
+		return fmt.Errorf("txThreshold larger than max of uint32")
+	}
+	this.BlockInterval = uint32(blockInterval)
+	this.MaxBlockInterval = uint32(maxBlockInterval)
+	this.TxThreshold = uint32(txThreshold)
+	return nil
+}
+
 type PreConfig struct {
 	Configuration *Configuration
 	SetView       uint32
 }
 
 func (this *PreConfig) Serialization(sink *common.ZeroCopySink) {
-	this.Configuration.Serialization(sink)
+	this.Configuration.serializeBase(sink)
 	utils.EncodeVarUint(sink, uint64(this.SetView))
+	this.Configuration.serializeBlockInterval(sink)
 }
 
 func (this *PreConfig) Deserialization(source *common.ZeroCopySource) error {
 	config := new(Configuration)
-	err := config.Deserialization(source)
+	err := config.deserializeBase(source)
 	if err != nil {
 		return fmt.Errorf("utils.ReadVarUint, deserialize configuration error: %v", err)
 	}
@@ -456,6 +554,10 @@ func (this *PreConfig) Deserialization(source *common.ZeroCopySource) error {
 	if setView > math.MaxUint32 {
 		return fmt.Errorf("setView larger than max of uint32")
 	}
+	err = config.deserializeBlockInterval(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize configuration error: %v", err)
+	}
 	this.Configuration = config
 	this.SetView = uint32(setView)
 	return nil
@@ -871,3 +973,126 @@ func (this *GasAddress) Deserialization(source *common.ZeroCopySource) error {
 	this.Address = address
 	return nil
 }
+
+type CreateProposalParam struct {
+	Proposer    common.Address
+	Type        ProposalType
+	Content     []byte
+	Description string
+}
+
+func (this *CreateProposalParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.Proposer[:])
+	utils.EncodeVarUint(sink, uint64(this.Type))
+	sink.WriteVarBytes(this.Content)
+	sink.WriteString(this.Description)
+}
+
+func (this *CreateProposalParam) Deserialization(source *common.ZeroCopySource) error {
+	proposer, err := utils.DecodeAddress(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize proposer error: %v", err)
+	}
+	proposalType, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize type error: %v", err)
+	}
+	if proposalType > math.MaxUint8 {
+		return fmt.Errorf("type larger than max of uint8")
+	}
+	content, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize content error: %v", err)
+	}
+	description, err := utils.DecodeString(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize description error: %v", err)
+	}
+	this.Proposer = proposer
+	this.Type = ProposalType(proposalType)
+	this.Content = content
+	this.Description = description
+	return nil
+}
+
+type VoteProposalParam struct {
+	ID      uint64
+	Voter   common.Address
+	Approve bool
+}
+
+func (this *VoteProposalParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, this.ID)
+	sink.WriteVarBytes(this.Voter[:])
+	sink.WriteBool(this.Approve)
+}
+
+func (this *VoteProposalParam) Deserialization(source *common.ZeroCopySource) error {
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize id error: %v", err)
+	}
+	voter, err := utils.DecodeAddress(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadAddress, deserialize voter error: %v", err)
+	}
+	approve, err := utils.DecodeBool(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadBool, deserialize approve error: %v", err)
+	}
+	this.ID = id
+	this.Voter = voter
+	this.Approve = approve
+	return nil
+}
+
+type EstimateStakingRewardParam struct {
+	PeerPubkey   string
+	Pos          uint64
+	Rounds       uint32
+	FeePerRound  uint64 //0 means using the fee income of current view
+	RoundSeconds uint32 //0 means using MaxBlockChangeView blocks of default block time
+}
+
+func (this *EstimateStakingRewardParam) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteString(this.PeerPubkey)
+	utils.EncodeVarUint(sink, this.Pos)
+	utils.EncodeVarUint(sink, uint64(this.Rounds))
+	utils.EncodeVarUint(sink, this.FeePerRound)
+	utils.EncodeVarUint(sink, uint64(this.RoundSeconds))
+}
+
+func (this *EstimateStakingRewardParam) Deserialization(source *common.ZeroCopySource) error {
+	peerPubkey, err := utils.DecodeString(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize peerPubkey error: %v", err)
+	}
+	pos, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize pos error: %v", err)
+	}
+	rounds, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize rounds error: %v", err)
+	}
+	if rounds > math.MaxUint32 {
+		return fmt.Errorf("rounds larger than max of uint32")
+	}
+	feePerRound, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize feePerRound error: %v", err)
+	}
+	roundSeconds, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("utils.ReadVarUint, deserialize roundSeconds error: %v", err)
+	}
+	if roundSeconds > math.MaxUint32 {
+		return fmt.Errorf("roundSeconds larger than max of uint32")
+	}
+	this.PeerPubkey = peerPubkey
+	this.Pos = pos
+	this.Rounds = uint32(rounds)
+	this.FeePerRound = feePerRound
+	this.RoundSeconds = uint32(roundSeconds)
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/auth"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+//initTestGovernance init governance with the mainnet peers each staking MinInitStake,
+//and return the addresses of peers
+func initTestGovernance(t *testing.T, n *native.NativeService) []common.Address {
+	vbft := *config.MainNetConfig.VBFT
+	vbft.Peers = nil
+	addresses := make([]common.Address, 0, len(config.MainNetConfig.VBFT.Peers))
+	for _, peer := range config.MainNetConfig.VBFT.Peers {
+		p := *peer
+		p.InitPos = uint64(vbft.MinInitStake)
+		vbft.Peers = append(vbft.Peers, &p)
+		address, err := common.AddressFromBase58(p.Address)
+		assert.Nil(t, err)
+		addresses = append(addresses, address)
+	}
+	sink := common.NewZeroCopySink(nil)
+	assert.Nil(t, vbft.Serialization(sink))
+	input := common.NewZeroCopySink(nil)
+	input.WriteVarBytes(sink.Bytes())
+	n.Input = input.Bytes()
+	_, err := InitConfig(n)
+	assert.Nil(t, err)
+	return addresses
+}
+
+//invokeAs call the governance method with the witness of address
+func invokeAs(n *native.NativeService, address common.Address, method native.Handler,
+	param common.Serializable) ([]byte, error) {
+	n.Tx.SignedAddr = []common.Address{address}
+	n.Input = common.SerializeToBytes(param)
+	return method(n)
+}
+
+func TestProposalSerialization(t *testing.T) {
+	proposal := &Proposal{
+		ID:           3,
+		Proposer:     common.Address{1, 2, 3},
+		Type:         UpdateSplitCurveProposal,
+		Content:      []byte{4, 5, 6},
+		Description:  "raise yita",
+		StartView:    10,
+		EndView:      10 + PROPOSAL_VOTE_VIEWS,
+		ApproveStake: 1000,
+		RejectStake:  200,
+		Status:       ProposalExecutedStatus,
+	}
+	proposal2 := new(Proposal)
+	err := proposal2.Deserialization(common.NewZeroCopySource(common.SerializeToBytes(proposal)))
+	assert.Nil(t, err)
+	assert.Equal(t, proposal, proposal2)
+
+	list := &ProposalList{IDs: []uint64{1, 3, 7}}
+	list2 := new(ProposalList)
+	err = list2.Deserialization(common.NewZeroCopySource(common.SerializeToBytes(list)))
+	assert.Nil(t, err)
+	assert.Equal(t, list, list2)
+}
+
+func TestProposalPassed(t *testing.T) {
+	//no vote
+	assert.False(t, proposalPassed(&Proposal{}, 1000))
+	//quorum not reached
+	assert.False(t, proposalPassed(&Proposal{ApproveStake: 299}, 1000))
+	assert.True(t, proposalPassed(&Proposal{ApproveStake: 300}, 1000))
+	//pass rate not reached
+	assert.False(t, proposalPassed(&Proposal{ApproveStake: 660, RejectStake: 340}, 1000))
+	assert.True(t, proposalPassed(&Proposal{ApproveStake: 670, RejectStake: 330}, 1000))
+}
+
+func TestProposalExecutedAtCommitDpos(t *testing.T) {
+	ont.InitOnt()
+	ong.InitOng()
+	auth.Init()
+	testsuite.InvokeNativeContract(t, utils.GovernanceContractAddress, func(n *native.NativeService) ([]byte, error) {
+		peers := initTestGovernance(t, n)
+
+		splitCurve := &SplitCurve{Yi: make([]uint32, 101)}
+		for i := range splitCurve.Yi {
+			splitCurve.Yi[i] = uint32(i)
+		}
+		content := common.NewZeroCopySink(nil)
+		assert.Nil(t, splitCurve.Serialization(content))
+		_, err := invokeAs(n, peers[0], CreateProposal, &CreateProposalParam{
+			Proposer:    peers[0],
+			Type:        UpdateSplitCurveProposal,
+			Content:     content.Bytes(),
+			Description: "linear split curve",
+		})
+		assert.Nil(t, err)
+		//3 of 7 peers approve, 1 rejects: quorum and pass rate reached
+		for i, voter := range peers[:4] {
+			_, err = invokeAs(n, voter, VoteProposal, &VoteProposalParam{ID: 0, Voter: voter, Approve: i < 3})
+			assert.Nil(t, err)
+		}
+		proposal, err := getProposal(n, utils.GovernanceContractAddress, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, 3*uint64(config.MainNetConfig.VBFT.MinInitStake), proposal.ApproveStake)
+
+		for i := 0; i <= PROPOSAL_VOTE_VIEWS; i++ {
+			n.Height++
+			assert.Nil(t, executeCommitDpos(n, utils.GovernanceContractAddress))
+			proposal, err = getProposal(n, utils.GovernanceContractAddress, 0)
+			assert.Nil(t, err)
+			if i < PROPOSAL_VOTE_VIEWS {
+				assert.Equal(t, ProposalVotingStatus, proposal.Status)
+			}
+		}
+		assert.Equal(t, ProposalExecutedStatus, proposal.Status)
+		curve, err := getSplitCurve(n, utils.GovernanceContractAddress)
+		assert.Nil(t, err)
+		assert.Equal(t, splitCurve, curve)
+		list, err := getProposalList(n, utils.GovernanceContractAddress)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(list.IDs))
+		return nil, nil
+	})
+}
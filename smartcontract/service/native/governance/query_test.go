@@ -0,0 +1,174 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/auth"
+	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/service/native/ont"
+	"github.com/ontio/ontology/smartcontract/service/native/testsuite"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryMethodsPreExecOnly(t *testing.T) {
+	n := &native.NativeService{ServiceMap: make(map[string]native.Handler)}
+	RegisterGovernanceContract(n)
+	_, ok := n.ServiceMap[GET_PEER_POOL_LIST]
+	assert.False(t, ok)
+	_, ok = n.ServiceMap[GET_PROPOSAL_LIST]
+	assert.False(t, ok)
+	_, ok = n.ServiceMap[ESTIMATE_STAKING_REWARD]
+	assert.False(t, ok)
+	_, ok = n.ServiceMap[VOTE_PROPOSAL]
+	assert.True(t, ok)
+
+	n = &native.NativeService{ServiceMap: make(map[string]native.Handler), PreExec: true}
+	RegisterGovernanceContract(n)
+	_, ok = n.ServiceMap[GET_PEER_POOL_LIST]
+	assert.True(t, ok)
+	_, ok = n.ServiceMap[GET_PROPOSAL_LIST]
+	assert.True(t, ok)
+	_, ok = n.ServiceMap[ESTIMATE_STAKING_REWARD]
+	assert.True(t, ok)
+}
+
+func TestGetPeerPoolList(t *testing.T) {
+	ont.InitOnt()
+	ong.InitOng()
+	auth.Init()
+	testsuite.InvokeNativeContract(t, utils.GovernanceContractAddress, func(n *native.NativeService) ([]byte, error) {
+		initTestGovernance(t, n)
+
+		data, err := GetPeerPoolList(n)
+		assert.Nil(t, err)
+		peers := make([]*PeerPoolItemRsp, 0)
+		assert.Nil(t, json.Unmarshal(data, &peers))
+		assert.Equal(t, len(config.MainNetConfig.VBFT.Peers), len(peers))
+		for i, peer := range peers {
+			assert.Equal(t, uint32(i+1), peer.Index)
+			assert.Equal(t, uint64(config.MainNetConfig.VBFT.MinInitStake), peer.InitPos)
+		}
+		return nil, nil
+	})
+}
+
+func TestGetAuthorizeInfoByAddress(t *testing.T) {
+	ont.InitOnt()
+	ong.InitOng()
+	auth.Init()
+	testsuite.InvokeNativeContract(t, utils.GovernanceContractAddress, func(n *native.NativeService) ([]byte, error) {
+		initTestGovernance(t, n)
+		peerPubkey := config.MainNetConfig.VBFT.Peers[0].PeerPubkey
+		address := common.Address{1}
+		input := common.NewZeroCopySink(nil)
+		utils.EncodeAddress(input, address)
+
+		n.Input = input.Bytes()
+		data, err := GetAuthorizeInfoByAddress(n)
+		assert.Nil(t, err)
+		assert.Equal(t, "[]", string(data))
+
+		//the sum of pos overflows to zero, the info must still be returned
+		assert.Nil(t, putAuthorizeInfo(n, utils.GovernanceContractAddress, &AuthorizeInfo{
+			PeerPubkey:   peerPubkey,
+			Address:      address,
+			ConsensusPos: 1 << 63,
+			CandidatePos: 1 << 63,
+		}))
+		n.Input = input.Bytes()
+		data, err = GetAuthorizeInfoByAddress(n)
+		assert.Nil(t, err)
+		infos := make([]*AuthorizeInfoRsp, 0)
+		assert.Nil(t, json.Unmarshal(data, &infos))
+		assert.Equal(t, 1, len(infos))
+		assert.Equal(t, peerPubkey, infos[0].PeerPubkey)
+		assert.Equal(t, address.ToBase58(), infos[0].Address)
+		assert.Equal(t, uint64(1<<63), infos[0].ConsensusPos)
+		return nil, nil
+	})
+}
+
+func TestGetProposalList(t *testing.T) {
+	ont.InitOnt()
+	ong.InitOng()
+	auth.Init()
+	testsuite.InvokeNativeContract(t, utils.GovernanceContractAddress, func(n *native.NativeService) ([]byte, error) {
+		peers := initTestGovernance(t, n)
+
+		data, err := GetProposalList(n)
+		assert.Nil(t, err)
+		assert.Equal(t, "[]", string(data))
+
+		splitCurve := &SplitCurve{Yi: make([]uint32, 101)}
+		for i := range splitCurve.Yi {
+			splitCurve.Yi[i] = uint32(i)
+		}
+		content := common.NewZeroCopySink(nil)
+		assert.Nil(t, splitCurve.Serialization(content))
+		_, err = invokeAs(n, peers[0], CreateProposal, &CreateProposalParam{
+			Proposer:    peers[0],
+			Type:        UpdateSplitCurveProposal,
+			Content:     content.Bytes(),
+			Description: "query test",
+		})
+		assert.Nil(t, err)
+		data, err = GetProposalList(n)
+		assert.Nil(t, err)
+		proposals := make([]*ProposalRsp, 0)
+		assert.Nil(t, json.Unmarshal(data, &proposals))
+		assert.Equal(t, 1, len(proposals))
+		assert.Equal(t, peers[0].ToBase58(), proposals[0].Proposer)
+		assert.Equal(t, "query test", proposals[0].Description)
+
+		input := common.NewZeroCopySink(nil)
+		utils.EncodeVarUint(input, proposals[0].ID)
+		n.Input = input.Bytes()
+		data, err = GetProposal(n)
+		assert.Nil(t, err)
+		proposal := new(ProposalRsp)
+		assert.Nil(t, json.Unmarshal(data, proposal))
+		assert.Equal(t, proposals[0], proposal)
+		return nil, nil
+	})
+}
+
+func TestProposalRsp(t *testing.T) {
+	proposal := &Proposal{
+		ID:       1,
+		Proposer: common.Address{1},
+		Type:     SetGlobalParamProposal,
+		Content:  []byte{1, 2},
+		EndView:  PROPOSAL_VOTE_VIEWS,
+	}
+	data, err := json.Marshal(newProposalRsp(proposal))
+	assert.Nil(t, err)
+	rsp := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(data, &rsp))
+	assert.Equal(t, proposal.Proposer.ToBase58(), rsp["proposer"])
+	assert.Equal(t, "0102", rsp["content"])
+	assert.Equal(t, float64(SetGlobalParamProposal), rsp["type"])
+	assert.Equal(t, float64(ProposalVotingStatus), rsp["status"])
+}
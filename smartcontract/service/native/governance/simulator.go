@@ -0,0 +1,246 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//RewardSimulation is a snapshot of governance state and a hypothetical authorization,
+//used to project the fee split of each round without touching storage
+type RewardSimulation struct {
+	Peers          []*PeerPoolItem
+	PeerAttributes *PeerAttributes //attributes of the authorized peer
+	Config         *Configuration
+	GlobalParam    *GlobalParam
+	GlobalParam2   *GlobalParam2
+	SplitCurve     *SplitCurve
+	HasGasAddress  bool //dapp fee is only split when gas address is set
+
+	PeerPubkey   string //peer to authorize
+	Pos          uint64 //ont to authorize
+	FeePerRound  uint64 //ong fee income of governance contract each round, unit: 10^-9 ong
+	Rounds       uint32
+	RoundSeconds uint32 //time of each round, used to calculate unbound ong
+	TimeOffset   uint32 //time offset from genesis block when authorizing
+}
+
+type RoundRewardRsp struct {
+	Round      uint32 `json:"round"`
+	PeerIncome uint64 `json:"peerIncome"` //fee split to the peer, unit: 10^-9 ong
+	PeerCost   uint64 `json:"peerCost"`   //percent of peer income not shared with authorize users
+	Reward     uint64 `json:"reward"`     //fee split to the authorization, unit: 10^-9 ong
+	UnboundOng uint64 `json:"unboundOng"` //ong unbound by the authorized ont, unit: 10^-9 ong
+}
+
+type StakingRewardRsp struct {
+	PeerPubkey      string            `json:"peerPubkey"`
+	Pos             uint64            `json:"pos"`
+	Consensus       bool              `json:"consensus"` //whether the peer is in top K after authorization
+	TotalReward     uint64            `json:"totalReward"`
+	TotalUnboundOng uint64            `json:"totalUnboundOng"`
+	Rounds          []*RoundRewardRsp `json:"rounds"`
+}
+
+//SimulateStakingReward project the fee split and unbound ong of authorizing Pos ont
+//to a peer for some rounds. Stakes and fee income are assumed not to change, and the
+//authorization only shares fee from the second round, as new pos takes effect in next view
+func SimulateStakingReward(sim *RewardSimulation) (*StakingRewardRsp, error) {
+	var target *PeerPoolItem
+	for _, peer := range sim.Peers {
+		if peer.PeerPubkey == sim.PeerPubkey {
+			target = peer
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("peerPubkey is not in peerPoolMap")
+	}
+	if target.Status != CandidateStatus && target.Status != ConsensusStatus {
+		return nil, fmt.Errorf("peerPubkey is not candidate and can not be authorized")
+	}
+	if sim.Pos == 0 {
+		return nil, fmt.Errorf("pos must >= 1")
+	}
+	//total reward of all rounds must not overflow
+	if sim.Rounds != 0 && sim.FeePerRound > math.MaxUint64/uint64(sim.Rounds) {
+		return nil, fmt.Errorf("feePerRound is too large for %d rounds", sim.Rounds)
+	}
+	if len(sim.SplitCurve.Yi) < len(Xi) {
+		return nil, fmt.Errorf("length of split curve is less than %d", len(Xi))
+	}
+	totalPos := target.TotalPos + sim.Pos
+	if totalPos > uint64(sim.GlobalParam.PosLimit)*target.InitPos {
+		return nil, fmt.Errorf("pos of this peer is full")
+	}
+	if totalPos > sim.PeerAttributes.MaxAuthorize {
+		return nil, fmt.Errorf("pos of this peer is more than peerAttributes.MaxAuthorize")
+	}
+
+	before, _, err := simulateSplit(sim, 0)
+	if err != nil {
+		return nil, err
+	}
+	after, consensus, err := simulateSplit(sim, sim.Pos)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &StakingRewardRsp{
+		PeerPubkey: sim.PeerPubkey,
+		Pos:        sim.Pos,
+		Consensus:  consensus,
+		Rounds:     make([]*RoundRewardRsp, 0, sim.Rounds),
+	}
+	for r := uint32(1); r <= sim.Rounds; r++ {
+		round := &RoundRewardRsp{
+			Round:    r,
+			PeerCost: simulatePeerCost(sim.PeerAttributes, r),
+		}
+		if r == 1 {
+			round.PeerIncome = before
+		} else {
+			round.PeerIncome = after
+			amount := new(big.Int).Div(new(big.Int).Mul(new(big.Int).SetUint64(round.PeerIncome),
+				new(big.Int).SetUint64(100-round.PeerCost)), new(big.Int).SetUint64(100))
+			round.Reward = new(big.Int).Div(new(big.Int).Mul(new(big.Int).SetUint64(sim.Pos), amount),
+				new(big.Int).SetUint64(totalPos)).Uint64()
+		}
+		start := sim.TimeOffset + (r-1)*sim.RoundSeconds
+		round.UnboundOng = utils.CalcUnbindOng(sim.Pos, start, start+sim.RoundSeconds)
+
+		rsp.TotalReward += round.Reward
+		rsp.TotalUnboundOng += round.UnboundOng
+		rsp.Rounds = append(rsp.Rounds, round)
+	}
+	return rsp, nil
+}
+
+//simulatePeerCost return the peer cost in effect at round, peer cost set now
+//takes effect in view T + 2
+func simulatePeerCost(peerAttributes *PeerAttributes, round uint32) uint64 {
+	switch round {
+	case 1:
+		return peerAttributes.TPeerCost
+	case 2:
+		return peerAttributes.T1PeerCost
+	default:
+		return peerAttributes.T2PeerCost
+	}
+}
+
+//simulateSplit calculate the fee split to the authorized peer in one round with pos
+//added to it, the same as executeSplit2
+func simulateSplit(sim *RewardSimulation, pos uint64) (uint64, bool, error) {
+	income := new(big.Int).SetUint64(sim.FeePerRound)
+	dappIncome := new(big.Int)
+	if sim.HasGasAddress {
+		dappIncome = new(big.Int).Div(new(big.Int).Mul(income, new(big.Int).SetUint64(uint64(sim.GlobalParam2.DappFee))),
+			new(big.Int).SetUint64(100))
+	}
+	nodeIncome := new(big.Int).Sub(income, dappIncome)
+
+	peersCandidate := []*CandidateSplitInfo{}
+	for _, peer := range sim.Peers {
+		if peer.Status == CandidateStatus || peer.Status == ConsensusStatus {
+			stake := peer.TotalPos + peer.InitPos
+			if peer.PeerPubkey == sim.PeerPubkey {
+				stake = stake + pos
+			}
+			peersCandidate = append(peersCandidate, &CandidateSplitInfo{
+				PeerPubkey: peer.PeerPubkey,
+				InitPos:    peer.InitPos,
+				Address:    peer.Address,
+				Stake:      stake,
+			})
+		}
+	}
+	k := int(sim.Config.K)
+	if len(peersCandidate) < k {
+		return 0, false, fmt.Errorf("num of candidate peers is less than K")
+	}
+
+	// sort peers by stake
+	sort.SliceStable(peersCandidate, func(i, j int) bool {
+		if peersCandidate[i].Stake > peersCandidate[j].Stake {
+			return true
+		} else if peersCandidate[i].Stake == peersCandidate[j].Stake {
+			return peersCandidate[i].PeerPubkey > peersCandidate[j].PeerPubkey
+		}
+		return false
+	})
+	index := 0
+	for i, peer := range peersCandidate {
+		if peer.PeerPubkey == sim.PeerPubkey {
+			index = i
+		}
+	}
+
+	if index < k {
+		var sum uint64
+		for i := 0; i < k; i++ {
+			sum += peersCandidate[i].Stake
+		}
+		if sum < uint64(k) {
+			return 0, true, nil
+		}
+		avg := sum / uint64(k)
+		var sumS uint64
+		for i := 0; i < k; i++ {
+			s, err := calcSplitCurve(sim.SplitCurve.Yi, peersCandidate[i].Stake, avg, uint64(sim.GlobalParam.Yita))
+			if err != nil {
+				return 0, true, err
+			}
+			peersCandidate[i].S = s
+			sumS += s
+		}
+		if sumS == 0 {
+			return 0, true, fmt.Errorf("sumS is 0")
+		}
+		consensusAmount := new(big.Int).Div(new(big.Int).Mul(nodeIncome, new(big.Int).SetUint64(uint64(sim.GlobalParam.A))),
+			new(big.Int).SetUint64(100))
+		nodeAmount := new(big.Int).Div(new(big.Int).Mul(consensusAmount, new(big.Int).SetUint64(peersCandidate[index].S)),
+			new(big.Int).SetUint64(sumS))
+		return nodeAmount.Uint64(), true, nil
+	}
+
+	length := len(peersCandidate)
+	if int(sim.GlobalParam2.CandidateFeeSplitNum) < length {
+		length = int(sim.GlobalParam2.CandidateFeeSplitNum)
+	}
+	if index >= length {
+		return 0, false, nil
+	}
+	var sum uint64
+	for i := k; i < length; i++ {
+		sum += peersCandidate[i].Stake
+	}
+	if sum == 0 {
+		return 0, false, nil
+	}
+	candidateAmount := new(big.Int).Div(new(big.Int).Mul(nodeIncome, new(big.Int).SetUint64(uint64(sim.GlobalParam.B))),
+		new(big.Int).SetUint64(100))
+	nodeAmount := new(big.Int).Div(new(big.Int).Mul(candidateAmount, new(big.Int).SetUint64(peersCandidate[index].Stake)),
+		new(big.Int).SetUint64(sum))
+	return nodeAmount.Uint64(), false, nil
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package governance
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRewardSimulation() *RewardSimulation {
+	peers := make([]*PeerPoolItem, 0)
+	for i := 1; i <= 7; i++ {
+		peers = append(peers, &PeerPoolItem{
+			Index:      uint32(i),
+			PeerPubkey: string(rune('a' + i)),
+			Address:    common.Address{byte(i)},
+			Status:     ConsensusStatus,
+			InitPos:    20000,
+		})
+	}
+	peers = append(peers, &PeerPoolItem{
+		Index:      8,
+		PeerPubkey: "target",
+		Address:    common.Address{8},
+		Status:     CandidateStatus,
+		InitPos:    10000,
+	})
+	return &RewardSimulation{
+		Peers: peers,
+		PeerAttributes: &PeerAttributes{
+			PeerPubkey:   "target",
+			MaxAuthorize: 100000,
+			TPeerCost:    100,
+			T1PeerCost:   50,
+			T2PeerCost:   20,
+		},
+		Config:       &Configuration{K: 7},
+		GlobalParam:  &GlobalParam{PosLimit: 10, A: 50, B: 50, Yita: 5},
+		GlobalParam2: &GlobalParam2{CandidateFeeSplitNum: 8, DappFee: 10},
+		SplitCurve:   &SplitCurve{Yi: Xi},
+		PeerPubkey:   "target",
+		Pos:          1000,
+		FeePerRound:  1000000000000,
+		Rounds:       3,
+		RoundSeconds: 86400,
+		TimeOffset:   1000,
+	}
+}
+
+func TestSimulateStakingReward(t *testing.T) {
+	sim := newTestRewardSimulation()
+	rsp, err := SimulateStakingReward(sim)
+	assert.Nil(t, err)
+	assert.False(t, rsp.Consensus)
+	assert.Equal(t, 3, len(rsp.Rounds))
+
+	//the only candidate peer takes all candidate income
+	income := sim.FeePerRound * uint64(sim.GlobalParam.B) / 100
+	assert.Equal(t, income, rsp.Rounds[0].PeerIncome)
+	//new pos takes effect in next round
+	assert.Equal(t, uint64(0), rsp.Rounds[0].Reward)
+	assert.Equal(t, income*50/100, rsp.Rounds[1].Reward)
+	assert.Equal(t, income*80/100, rsp.Rounds[2].Reward)
+	assert.Equal(t, rsp.Rounds[1].Reward+rsp.Rounds[2].Reward, rsp.TotalReward)
+	assert.Equal(t, utils.CalcUnbindOng(sim.Pos, 1000, 1000+3*86400), rsp.TotalUnboundOng)
+
+	//dapp fee is split when gas address is set
+	sim.HasGasAddress = true
+	rsp, err = SimulateStakingReward(sim)
+	assert.Nil(t, err)
+	assert.Equal(t, income*90/100, rsp.Rounds[0].PeerIncome)
+}
+
+func TestSimulateStakingRewardConsensus(t *testing.T) {
+	sim := newTestRewardSimulation()
+	sim.Pos = 20000
+	rsp, err := SimulateStakingReward(sim)
+	assert.Nil(t, err)
+	assert.True(t, rsp.Consensus)
+	//the peer gets more than average of consensus income with the most stake
+	consensusIncome := sim.FeePerRound * uint64(sim.GlobalParam.A) / 100
+	assert.True(t, rsp.Rounds[1].PeerIncome > consensusIncome/7)
+	assert.True(t, rsp.Rounds[1].PeerIncome < consensusIncome)
+
+	sim.Pos = 100001
+	_, err = SimulateStakingReward(sim)
+	assert.NotNil(t, err)
+
+	sim.Pos = 1000
+	sim.PeerPubkey = "unknown"
+	_, err = SimulateStakingReward(sim)
+	assert.NotNil(t, err)
+}
+
+func TestSimulateStakingRewardLargeFee(t *testing.T) {
+	sim := newTestRewardSimulation()
+	sim.FeePerRound = math.MaxUint64 / uint64(sim.Rounds)
+	rsp, err := SimulateStakingReward(sim)
+	assert.Nil(t, err)
+	//income multiplied by the shared percent exceeds uint64
+	income := new(big.Int).Div(new(big.Int).Mul(new(big.Int).SetUint64(sim.FeePerRound),
+		big.NewInt(int64(sim.GlobalParam.B))), big.NewInt(100))
+	assert.Equal(t, income.Uint64(), rsp.Rounds[1].PeerIncome)
+	reward := new(big.Int).Div(new(big.Int).Mul(income, big.NewInt(50)), big.NewInt(100))
+	assert.Equal(t, reward.Uint64(), rsp.Rounds[1].Reward)
+	assert.Equal(t, rsp.Rounds[1].Reward+rsp.Rounds[2].Reward, rsp.TotalReward)
+
+	sim.FeePerRound++
+	_, err = SimulateStakingReward(sim)
+	assert.NotNil(t, err)
+}
@@ -19,6 +19,7 @@
 package governance
 
 import (
+	"encoding/hex"
 	"fmt"
 	"io"
 	"sort"
@@ -464,3 +465,253 @@ func (this *SplitFeeAddress) Deserialization(source *common.ZeroCopySource) erro
 	this.Amount = amount
 	return nil
 }
+
+type ProposalType uint8
+
+const (
+	UpdateGlobalParamProposal ProposalType = iota
+	UpdateGlobalParam2Proposal
+	UpdateSplitCurveProposal
+	UpdateConfigProposal
+	SetGlobalParamProposal
+)
+
+type ProposalStatus uint8
+
+const (
+	ProposalVotingStatus ProposalStatus = iota
+	ProposalExecutedStatus
+	ProposalRejectedStatus
+	ProposalFailedStatus
+)
+
+type Proposal struct {
+	ID           uint64
+	Proposer     common.Address
+	Type         ProposalType
+	Content      []byte //serialized param of the method this proposal executes
+	Description  string
+	StartView    uint32 //view the proposal is created in
+	EndView      uint32 //last view the proposal can be voted in
+	ApproveStake uint64
+	RejectStake  uint64
+	Status       ProposalStatus
+}
+
+func (this *Proposal) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteUint64(this.ID)
+	this.Proposer.Serialization(sink)
+	sink.WriteUint8(uint8(this.Type))
+	sink.WriteVarBytes(this.Content)
+	sink.WriteString(this.Description)
+	sink.WriteUint32(this.StartView)
+	sink.WriteUint32(this.EndView)
+	sink.WriteUint64(this.ApproveStake)
+	sink.WriteUint64(this.RejectStake)
+	sink.WriteUint8(uint8(this.Status))
+}
+
+func (this *Proposal) Deserialization(source *common.ZeroCopySource) error {
+	id, err := utils.DecodeUint64(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize id error: %v", err)
+	}
+	proposer := new(common.Address)
+	err = proposer.Deserialization(source)
+	if err != nil {
+		return fmt.Errorf("address.Deserialize, deserialize proposer error: %v", err)
+	}
+	proposalType, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("serialization.ReadUint8, deserialize type error: %v", io.ErrUnexpectedEOF)
+	}
+	content, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadVarBytes, deserialize content error: %v", err)
+	}
+	description, err := utils.DecodeString(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadString, deserialize description error: %v", err)
+	}
+	startView, err := utils.DecodeUint32(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize startView error: %v", err)
+	}
+	endView, err := utils.DecodeUint32(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint32, deserialize endView error: %v", err)
+	}
+	approveStake, err := utils.DecodeUint64(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize approveStake error: %v", err)
+	}
+	rejectStake, err := utils.DecodeUint64(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize rejectStake error: %v", err)
+	}
+	status, eof := source.NextUint8()
+	if eof {
+		return fmt.Errorf("serialization.ReadUint8, deserialize status error: %v", io.ErrUnexpectedEOF)
+	}
+	this.ID = id
+	this.Proposer = *proposer
+	this.Type = ProposalType(proposalType)
+	this.Content = content
+	this.Description = description
+	this.StartView = startView
+	this.EndView = endView
+	this.ApproveStake = approveStake
+	this.RejectStake = rejectStake
+	this.Status = ProposalStatus(status)
+	return nil
+}
+
+type ProposalVote struct { //vote of an address for a proposal
+	Approve bool
+	Stake   uint64 //total stake of voter when voting
+}
+
+func (this *ProposalVote) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteBool(this.Approve)
+	sink.WriteUint64(this.Stake)
+}
+
+func (this *ProposalVote) Deserialization(source *common.ZeroCopySource) error {
+	approve, err := utils.DecodeBool(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadBool, deserialize approve error: %v", err)
+	}
+	stake, err := utils.DecodeUint64(source)
+	if err != nil {
+		return fmt.Errorf("serialization.ReadUint64, deserialize stake error: %v", err)
+	}
+	this.Approve = approve
+	this.Stake = stake
+	return nil
+}
+
+type ProposalList struct { //ids of proposals in voting
+	IDs []uint64
+}
+
+func (this *ProposalList) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarUint(uint64(len(this.IDs)))
+	for _, id := range this.IDs {
+		sink.WriteUint64(id)
+	}
+}
+
+func (this *ProposalList) Deserialization(source *common.ZeroCopySource) error {
+	n, _, irregular, eof := source.NextVarUint()
+	if irregular || eof {
+		return fmt.Errorf("serialization.ReadVarUint, deserialize length irregular:%v, eof: %v", irregular, eof)
+	}
+	ids := make([]uint64, 0)
+	for i := uint64(0); i < n; i++ {
+		id, err := utils.DecodeUint64(source)
+		if err != nil {
+			return fmt.Errorf("serialization.ReadUint64, deserialize id error: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	this.IDs = ids
+	return nil
+}
+
+//json responses of read-only methods
+type PeerPoolItemRsp struct {
+	Index      uint32 `json:"index"`
+	PeerPubkey string `json:"peerPubkey"`
+	Address    string `json:"address"`
+	Status     Status `json:"status"`
+	InitPos    uint64 `json:"initPos"`
+	TotalPos   uint64 `json:"totalPos"`
+}
+
+type AuthorizeInfoRsp struct {
+	PeerPubkey           string `json:"peerPubkey"`
+	Address              string `json:"address"`
+	ConsensusPos         uint64 `json:"consensusPos"`
+	CandidatePos         uint64 `json:"candidatePos"`
+	NewPos               uint64 `json:"newPos"`
+	WithdrawConsensusPos uint64 `json:"withdrawConsensusPos"`
+	WithdrawCandidatePos uint64 `json:"withdrawCandidatePos"`
+	WithdrawUnfreezePos  uint64 `json:"withdrawUnfreezePos"`
+}
+
+type SplitFeeAddressRsp struct {
+	Address string `json:"address"`
+	Amount  uint64 `json:"amount"`
+}
+
+type TotalStakeRsp struct {
+	Address    string `json:"address"`
+	Stake      uint64 `json:"stake"`
+	TimeOffset uint32 `json:"timeOffset"`
+}
+
+type GovernanceViewRsp struct {
+	View   uint32 `json:"view"`
+	Height uint32 `json:"height"`
+	TxHash string `json:"txHash"`
+}
+
+type PeerAttributesRsp struct {
+	PeerPubkey   string `json:"peerPubkey"`
+	MaxAuthorize uint64 `json:"maxAuthorize"`
+	T2PeerCost   uint64 `json:"t2PeerCost"`
+	T1PeerCost   uint64 `json:"t1PeerCost"`
+	TPeerCost    uint64 `json:"tPeerCost"`
+}
+
+type ProposalRsp struct {
+	ID           uint64         `json:"id"`
+	Proposer     string         `json:"proposer"`
+	Type         ProposalType   `json:"type"`
+	Content      string         `json:"content"`
+	Description  string         `json:"description"`
+	StartView    uint32         `json:"startView"`
+	EndView      uint32         `json:"endView"`
+	ApproveStake uint64         `json:"approveStake"`
+	RejectStake  uint64         `json:"rejectStake"`
+	Status       ProposalStatus `json:"status"`
+}
+
+func newPeerPoolItemRsp(item *PeerPoolItem) *PeerPoolItemRsp {
+	return &PeerPoolItemRsp{
+		Index:      item.Index,
+		PeerPubkey: item.PeerPubkey,
+		Address:    item.Address.ToBase58(),
+		Status:     item.Status,
+		InitPos:    item.InitPos,
+		TotalPos:   item.TotalPos,
+	}
+}
+
+func newAuthorizeInfoRsp(info *AuthorizeInfo) *AuthorizeInfoRsp {
+	return &AuthorizeInfoRsp{
+		PeerPubkey:           info.PeerPubkey,
+		Address:              info.Address.ToBase58(),
+		ConsensusPos:         info.ConsensusPos,
+		CandidatePos:         info.CandidatePos,
+		NewPos:               info.NewPos,
+		WithdrawConsensusPos: info.WithdrawConsensusPos,
+		WithdrawCandidatePos: info.WithdrawCandidatePos,
+		WithdrawUnfreezePos:  info.WithdrawUnfreezePos,
+	}
+}
+
+func newProposalRsp(proposal *Proposal) *ProposalRsp {
+	return &ProposalRsp{
+		ID:           proposal.ID,
+		Proposer:     proposal.Proposer.ToBase58(),
+		Type:         proposal.Type,
+		Content:      hex.EncodeToString(proposal.Content),
+		Description:  proposal.Description,
+		StartView:    proposal.StartView,
+		EndView:      proposal.EndView,
+		ApproveStake: proposal.ApproveStake,
+		RejectStake:  proposal.RejectStake,
+		Status:       proposal.Status,
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/ontio/ontology/common/serialization"
 	vbftconfig "github.com/ontio/ontology/consensus/vbft/config"
 	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/event"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	"github.com/ontio/ontology/smartcontract/service/native/auth"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
@@ -188,6 +189,18 @@ func getOngBalance(native *native.NativeService, address common.Address) (uint64
 }
 
 func splitCurve(native *native.NativeService, contract common.Address, pos uint64, avg uint64, yita uint64) (uint64, error) {
+	if avg == 0 {
+		return 0, fmt.Errorf("splitCurve, avg stake is 0")
+	}
+	splitCurve, err := getSplitCurve(native, contract)
+	if err != nil {
+		return 0, fmt.Errorf("getSplitCurve, get splitCurve error: %v", err)
+	}
+	return calcSplitCurve(splitCurve.Yi, pos, avg, yita)
+}
+
+//calcSplitCurve calculate fee split weight of a peer by linear interpolation of split curve
+func calcSplitCurve(Yi []uint32, pos uint64, avg uint64, yita uint64) (uint64, error) {
 	if avg == 0 {
 		return 0, fmt.Errorf("splitCurve, avg stake is 0")
 	}
@@ -197,11 +210,6 @@ func splitCurve(native *native.NativeService, contract common.Address, pos uint6
 		index = uint64(len(Xi) - 2)
 		xi = uint64(Xi[len(Xi)-1])
 	}
-	splitCurve, err := getSplitCurve(native, contract)
-	if err != nil {
-		return 0, fmt.Errorf("getSplitCurve, get splitCurve error: %v", err)
-	}
-	Yi := splitCurve.Yi
 	s := (uint64(Yi[index+1])*xi + uint64(Yi[index])*uint64(Xi[index+1]) - uint64(Yi[index])*xi - uint64(Yi[index+1])*uint64(Xi[index])) / (uint64(Xi[index+1]) - uint64(Xi[index]))
 	return s, nil
 }
@@ -343,6 +351,9 @@ func CheckVBFTConfig(configuration *config.VBFTConfig) error {
 	if len(configuration.VrfValue) < 128 {
 		return fmt.Errorf("initConfig. VrfValue must >= 128")
 	}
+	if err := checkBlockInterval(configuration.BlockInterval, configuration.MaxBlockInterval); err != nil {
+		return fmt.Errorf("initConfig. %v", err)
+	}
 
 	indexMap := make(map[uint32]struct{})
 	peerPubkeyMap := make(map[string]struct{})
@@ -374,6 +385,23 @@ func CheckVBFTConfig(configuration *config.VBFTConfig) error {
 	return nil
 }
 
+//check dynamic block interval params, zero maxBlockInterval disables it
+func checkBlockInterval(blockInterval, maxBlockInterval uint32) error {
+	if maxBlockInterval == 0 {
+		if blockInterval != 0 {
+			return fmt.Errorf("BlockInterval must be 0 if MaxBlockInterval is 0")
+		}
+		return nil
+	}
+	if maxBlockInterval < MIN_MAX_BLOCK_INTERVAL || maxBlockInterval > MAX_MAX_BLOCK_INTERVAL {
+		return fmt.Errorf("MaxBlockInterval must be in [%d, %d]", MIN_MAX_BLOCK_INTERVAL, MAX_MAX_BLOCK_INTERVAL)
+	}
+	if blockInterval > maxBlockInterval {
+		return fmt.Errorf("BlockInterval must <= MaxBlockInterval")
+	}
+	return nil
+}
+
 func getConfig(native *native.NativeService, contract common.Address) (*Configuration, error) {
 	config := new(Configuration)
 	configBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(VBFT_CONFIG)))
@@ -573,6 +601,29 @@ func putPenaltyStake(native *native.NativeService, contract common.Address, pena
 	return nil
 }
 
+//putDoubleSignEvidence store the evidence of peer at height, evidence can only
+//be reported once
+func putDoubleSignEvidence(native *native.NativeService, contract common.Address, height uint32, evidence *DoubleSignEvidenceParam) error {
+	peerPubkeyPrefix, err := hex.DecodeString(evidence.PeerPubkey)
+	if err != nil {
+		return fmt.Errorf("hex.DecodeString, peerPubkey format error: %v", err)
+	}
+	heightBytes, err := GetUint32Bytes(height)
+	if err != nil {
+		return fmt.Errorf("GetUint32Bytes, get heightBytes error: %v", err)
+	}
+	key := utils.ConcatKey(contract, []byte(DOUBLE_SIGN), peerPubkeyPrefix, heightBytes)
+	evidenceBytes, err := native.CacheDB.Get(key)
+	if err != nil {
+		return fmt.Errorf("get evidenceBytes error: %v", err)
+	}
+	if evidenceBytes != nil {
+		return fmt.Errorf("evidence of height %d is already reported", height)
+	}
+	native.CacheDB.Put(key, cstates.GenRawStorageItem(common.SerializeToBytes(evidence)))
+	return nil
+}
+
 func getTotalStake(native *native.NativeService, contract common.Address, address common.Address) (*TotalStake, error) {
 	totalStakeBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(TOTAL_STAKE),
 		address[:]))
@@ -756,3 +807,108 @@ func putGasAddress(native *native.NativeService, contract common.Address, gasAdd
 		cstates.GenRawStorageItem(common.SerializeToBytes(gasAddress)))
 	return nil
 }
+
+func getProposalIndex(native *native.NativeService, contract common.Address) (uint64, error) {
+	proposalIndexBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX)))
+	if err != nil {
+		return 0, fmt.Errorf("native.CacheDB.Get, get proposalIndex error: %v", err)
+	}
+	if proposalIndexBytes == nil {
+		return 0, nil
+	}
+	proposalIndexStore, err := cstates.GetValueFromRawStorageItem(proposalIndexBytes)
+	if err != nil {
+		return 0, fmt.Errorf("getProposalIndex, deserialize from raw storage item err:%v", err)
+	}
+	return GetBytesUint64(proposalIndexStore)
+}
+
+func putProposalIndex(native *native.NativeService, contract common.Address, proposalIndex uint64) {
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_INDEX)),
+		cstates.GenRawStorageItem(GetUint64Bytes(proposalIndex)))
+}
+
+func getProposal(native *native.NativeService, contract common.Address, id uint64) (*Proposal, error) {
+	proposalBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL), GetUint64Bytes(id)))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get proposalBytes error: %v", err)
+	}
+	if proposalBytes == nil {
+		return nil, fmt.Errorf("getProposal, proposal %d is not found", id)
+	}
+	proposalStore, err := cstates.GetValueFromRawStorageItem(proposalBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposal, deserialize from raw storage item err:%v", err)
+	}
+	proposal := new(Proposal)
+	if err := proposal.Deserialization(common.NewZeroCopySource(proposalStore)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposal error: %v", err)
+	}
+	return proposal, nil
+}
+
+func putProposal(native *native.NativeService, contract common.Address, proposal *Proposal) {
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL), GetUint64Bytes(proposal.ID)),
+		cstates.GenRawStorageItem(common.SerializeToBytes(proposal)))
+}
+
+func getProposalList(native *native.NativeService, contract common.Address) (*ProposalList, error) {
+	proposalListBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_LIST)))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get proposalListBytes error: %v", err)
+	}
+	proposalList := new(ProposalList)
+	if proposalListBytes == nil {
+		return proposalList, nil
+	}
+	proposalListStore, err := cstates.GetValueFromRawStorageItem(proposalListBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposalList, deserialize from raw storage item err:%v", err)
+	}
+	if err := proposalList.Deserialization(common.NewZeroCopySource(proposalListStore)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposalList error: %v", err)
+	}
+	return proposalList, nil
+}
+
+func putProposalList(native *native.NativeService, contract common.Address, proposalList *ProposalList) {
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_LIST)),
+		cstates.GenRawStorageItem(common.SerializeToBytes(proposalList)))
+}
+
+//getProposalVote return nil if address has not voted for the proposal
+func getProposalVote(native *native.NativeService, contract common.Address, id uint64, address common.Address) (*ProposalVote, error) {
+	voteBytes, err := native.CacheDB.Get(utils.ConcatKey(contract, []byte(PROPOSAL_VOTE), GetUint64Bytes(id), address[:]))
+	if err != nil {
+		return nil, fmt.Errorf("native.CacheDB.Get, get voteBytes error: %v", err)
+	}
+	if voteBytes == nil {
+		return nil, nil
+	}
+	voteStore, err := cstates.GetValueFromRawStorageItem(voteBytes)
+	if err != nil {
+		return nil, fmt.Errorf("getProposalVote, deserialize from raw storage item err:%v", err)
+	}
+	vote := new(ProposalVote)
+	if err := vote.Deserialization(common.NewZeroCopySource(voteStore)); err != nil {
+		return nil, fmt.Errorf("deserialize, deserialize proposalVote error: %v", err)
+	}
+	return vote, nil
+}
+
+func putProposalVote(native *native.NativeService, contract common.Address, id uint64, address common.Address, vote *ProposalVote) {
+	native.CacheDB.Put(utils.ConcatKey(contract, []byte(PROPOSAL_VOTE), GetUint64Bytes(id), address[:]),
+		cstates.GenRawStorageItem(common.SerializeToBytes(vote)))
+}
+
+func notifyProposal(native *native.NativeService, contract common.Address, method string, proposal *Proposal) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States: []interface{}{method, proposal.ID, proposal.Proposer.ToBase58(), uint8(proposal.Type),
+				uint8(proposal.Status), proposal.ApproveStake, proposal.RejectStake},
+		})
+}
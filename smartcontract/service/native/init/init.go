@@ -27,6 +27,8 @@ import (
 	params "github.com/ontio/ontology/smartcontract/service/native/global_params"
 	"github.com/ontio/ontology/smartcontract/service/native/governance"
 	"github.com/ontio/ontology/smartcontract/service/native/ong"
+	"github.com/ontio/ontology/smartcontract/service/native/oep4"
+	"github.com/ontio/ontology/smartcontract/service/native/oep5"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
 	"github.com/ontio/ontology/smartcontract/service/native/ontid"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -46,6 +48,8 @@ func init() {
 	ontid.Init()
 	auth.Init()
 	governance.InitGovernance()
+	oep4.InitOep4()
+	oep5.InitOep5()
 }
 
 func InitBytes(addr common.Address, method string) []byte {
@@ -0,0 +1,259 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package oep4 is the factory of oep4 fungible tokens. Each deploy creates a new token instance
+//identified by an increasing id, all token instances share the storage of the factory contract
+package oep4
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+func InitOep4() {
+	native.Contracts[utils.Oep4FactoryContractAddress] = RegisterOep4Contract
+}
+
+func RegisterOep4Contract(native *native.NativeService) {
+	native.Register(DEPLOY_NAME, Deploy)
+	native.Register(TRANSFER_NAME, Transfer)
+	native.Register(APPROVE_NAME, Approve)
+	native.Register(TRANSFERFROM_NAME, TransferFrom)
+	native.Register(NAME_NAME, Name)
+	native.Register(SYMBOL_NAME, Symbol)
+	native.Register(DECIMALS_NAME, Decimals)
+	native.Register(TOTALSUPPLY_NAME, TotalSupply)
+	native.Register(BALANCEOF_NAME, BalanceOf)
+	native.Register(ALLOWANCE_NAME, Allowance)
+	native.Register(GET_TOKEN_NAME, GetToken)
+}
+
+//Deploy creates a new token, total supply is issued to owner
+func Deploy(native *native.NativeService) ([]byte, error) {
+	var param DeployParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Deploy] DeployParam deserialize error!")
+	}
+	if len(param.Name) == 0 || len(param.Name) > MAX_NAME_LEN {
+		return utils.BYTE_FALSE, fmt.Errorf("[Deploy] length of name should be in [1, %d]", MAX_NAME_LEN)
+	}
+	if len(param.Symbol) == 0 || len(param.Symbol) > MAX_SYMBOL_LEN {
+		return utils.BYTE_FALSE, fmt.Errorf("[Deploy] length of symbol should be in [1, %d]", MAX_SYMBOL_LEN)
+	}
+	if param.Decimals > MAX_DECIMALS {
+		return utils.BYTE_FALSE, fmt.Errorf("[Deploy] decimals should not be larger than %d", MAX_DECIMALS)
+	}
+	if param.TotalSupply == 0 {
+		return utils.BYTE_FALSE, errors.NewErr("[Deploy] total supply should be larger than 0")
+	}
+	if !native.ContextRef.CheckWitness(param.Owner) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	id, err := utils.GetStorageUInt64(native, genTokenIndexKey(contract))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	id = id + 1
+	native.CacheDB.Put(genTokenIndexKey(contract), utils.GenUInt64StorageItem(id).ToArray())
+
+	putToken(native, contract, &Token{
+		ID:          id,
+		Owner:       param.Owner,
+		Name:        param.Name,
+		Symbol:      param.Symbol,
+		Decimals:    param.Decimals,
+		TotalSupply: param.TotalSupply,
+	})
+	native.CacheDB.Put(genBalanceKey(contract, id, param.Owner), utils.GenUInt64StorageItem(param.TotalSupply).ToArray())
+	addNotifications(native, contract, TRANSFER_NAME, id, common.ADDRESS_EMPTY, param.Owner, param.TotalSupply)
+	return common.BigIntToNeoBytes(big.NewInt(int64(id))), nil
+}
+
+func Transfer(native *native.NativeService) ([]byte, error) {
+	var param TransferParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Transfer] TransferParam deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	token, err := getToken(native, contract, param.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if param.Value > token.TotalSupply {
+		return utils.BYTE_FALSE, fmt.Errorf("transfer amount:%d over totalSupply:%d", param.Value, token.TotalSupply)
+	}
+	if !native.ContextRef.CheckWitness(param.From) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	if err := transfer(native, contract, param.ID, param.From, param.To, param.Value); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	addNotifications(native, contract, TRANSFER_NAME, param.ID, param.From, param.To, param.Value)
+	return utils.BYTE_TRUE, nil
+}
+
+func Approve(native *native.NativeService) ([]byte, error) {
+	var param TransferParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Approve] TransferParam deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	token, err := getToken(native, contract, param.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if param.Value > token.TotalSupply {
+		return utils.BYTE_FALSE, fmt.Errorf("approve amount:%d over totalSupply:%d", param.Value, token.TotalSupply)
+	}
+	if !native.ContextRef.CheckWitness(param.From) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	native.CacheDB.Put(genApproveKey(contract, param.ID, param.From, param.To), utils.GenUInt64StorageItem(param.Value).ToArray())
+	addNotifications(native, contract, APPROVAL_EVENT, param.ID, param.From, param.To, param.Value)
+	return utils.BYTE_TRUE, nil
+}
+
+func TransferFrom(native *native.NativeService) ([]byte, error) {
+	var param TransferFromParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[TransferFrom] TransferFromParam deserialize error!")
+	}
+	if param.Value == 0 {
+		return utils.BYTE_FALSE, nil
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	if _, err := getToken(native, contract, param.ID); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if !native.ContextRef.CheckWitness(param.Sender) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	approveKey := genApproveKey(contract, param.ID, param.From, param.Sender)
+	approveValue, err := utils.GetStorageUInt64(native, approveKey)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if approveValue < param.Value {
+		return utils.BYTE_FALSE, fmt.Errorf("[TransferFrom] approve balance insufficient! have %d, got %d", approveValue, param.Value)
+	} else if approveValue == param.Value {
+		native.CacheDB.Delete(approveKey)
+	} else {
+		native.CacheDB.Put(approveKey, utils.GenUInt64StorageItem(approveValue-param.Value).ToArray())
+	}
+	if err := transfer(native, contract, param.ID, param.From, param.To, param.Value); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	addNotifications(native, contract, TRANSFER_NAME, param.ID, param.From, param.To, param.Value)
+	return utils.BYTE_TRUE, nil
+}
+
+func Name(native *native.NativeService) ([]byte, error) {
+	token, err := getTokenByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return []byte(token.Name), nil
+}
+
+func Symbol(native *native.NativeService) ([]byte, error) {
+	token, err := getTokenByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return []byte(token.Symbol), nil
+}
+
+func Decimals(native *native.NativeService) ([]byte, error) {
+	token, err := getTokenByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.BigIntToNeoBytes(big.NewInt(int64(token.Decimals))), nil
+}
+
+func TotalSupply(native *native.NativeService) ([]byte, error) {
+	token, err := getTokenByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.BigIntToNeoBytes(new(big.Int).SetUint64(token.TotalSupply)), nil
+}
+
+func GetToken(native *native.NativeService) ([]byte, error) {
+	token, err := getTokenByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.SerializeToBytes(token), nil
+}
+
+func BalanceOf(native *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[BalanceOf] get token id error!")
+	}
+	addr, err := utils.DecodeAddress(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[BalanceOf] get address error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	amount, err := utils.GetStorageUInt64(native, genBalanceKey(contract, id, addr))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.BigIntToNeoBytes(new(big.Int).SetUint64(amount)), nil
+}
+
+func Allowance(native *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Allowance] get token id error!")
+	}
+	from, err := utils.DecodeAddress(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Allowance] get from address error!")
+	}
+	to, err := utils.DecodeAddress(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Allowance] get to address error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	amount, err := utils.GetStorageUInt64(native, genApproveKey(contract, id, from, to))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.BigIntToNeoBytes(new(big.Int).SetUint64(amount)), nil
+}
+
+func getTokenByInput(native *native.NativeService) (*Token, error) {
+	id, err := utils.DecodeVarUint(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return nil, errors.NewDetailErr(err, errors.ErrNoCode, "get token id error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	return getToken(native, contract, id)
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package oep4
+
+import (
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//Token is an oep4 token instance created by the factory
+type Token struct {
+	ID          uint64
+	Owner       common.Address
+	Name        string
+	Symbol      string
+	Decimals    uint64
+	TotalSupply uint64
+}
+
+func (this *Token) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, this.ID)
+	utils.EncodeAddress(sink, this.Owner)
+	sink.WriteString(this.Name)
+	sink.WriteString(this.Symbol)
+	utils.EncodeVarUint(sink, this.Decimals)
+	utils.EncodeVarUint(sink, this.TotalSupply)
+}
+
+func (this *Token) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.ID, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.Owner, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Name, err = utils.DecodeString(source); err != nil {
+		return err
+	}
+	if this.Symbol, err = utils.DecodeString(source); err != nil {
+		return err
+	}
+	if this.Decimals, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	this.TotalSupply, err = utils.DecodeVarUint(source)
+
+	return err
+}
+
+type DeployParam struct {
+	Owner       common.Address
+	Name        string
+	Symbol      string
+	Decimals    uint64
+	TotalSupply uint64
+}
+
+func (this *DeployParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeAddress(sink, this.Owner)
+	sink.WriteString(this.Name)
+	sink.WriteString(this.Symbol)
+	utils.EncodeVarUint(sink, this.Decimals)
+	utils.EncodeVarUint(sink, this.TotalSupply)
+}
+
+func (this *DeployParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.Owner, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Name, err = utils.DecodeString(source); err != nil {
+		return err
+	}
+	if this.Symbol, err = utils.DecodeString(source); err != nil {
+		return err
+	}
+	if this.Decimals, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	this.TotalSupply, err = utils.DecodeVarUint(source)
+
+	return err
+}
+
+type TransferParam struct {
+	ID    uint64
+	From  common.Address
+	To    common.Address
+	Value uint64
+}
+
+func (this *TransferParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, this.ID)
+	utils.EncodeAddress(sink, this.From)
+	utils.EncodeAddress(sink, this.To)
+	utils.EncodeVarUint(sink, this.Value)
+}
+
+func (this *TransferParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.ID, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.From, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.To, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	this.Value, err = utils.DecodeVarUint(source)
+
+	return err
+}
+
+type TransferFromParam struct {
+	ID     uint64
+	Sender common.Address
+	From   common.Address
+	To     common.Address
+	Value  uint64
+}
+
+func (this *TransferFromParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, this.ID)
+	utils.EncodeAddress(sink, this.Sender)
+	utils.EncodeAddress(sink, this.From)
+	utils.EncodeAddress(sink, this.To)
+	utils.EncodeVarUint(sink, this.Value)
+}
+
+func (this *TransferFromParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.ID, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.Sender, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.From, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.To, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	this.Value, err = utils.DecodeVarUint(source)
+
+	return err
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package oep4
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	//function name
+	DEPLOY_NAME       = "deploy"
+	TRANSFER_NAME     = "transfer"
+	APPROVE_NAME      = "approve"
+	TRANSFERFROM_NAME = "transferFrom"
+	NAME_NAME         = "name"
+	SYMBOL_NAME       = "symbol"
+	DECIMALS_NAME     = "decimals"
+	TOTALSUPPLY_NAME  = "totalSupply"
+	BALANCEOF_NAME    = "balanceOf"
+	ALLOWANCE_NAME    = "allowance"
+	GET_TOKEN_NAME    = "getToken"
+
+	//event name
+	APPROVAL_EVENT = "approval"
+
+	//key prefix
+	TOKEN_INDEX = "tokenIndex"
+	TOKEN       = "token"
+	BALANCE     = "balance"
+	APPROVE     = "approve"
+
+	//limits of token meta
+	MAX_NAME_LEN   = 64
+	MAX_SYMBOL_LEN = 16
+	MAX_DECIMALS   = 18
+)
+
+//addNotifications keeps the states of ont transfer event, with token id appended
+func addNotifications(native *native.NativeService, contract common.Address, method string, id uint64,
+	from, to common.Address, value uint64) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{method, from.ToBase58(), to.ToBase58(), value, id},
+		})
+}
+
+func genIDBytes(id uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint64(id)
+	return sink.Bytes()
+}
+
+func genTokenIndexKey(contract common.Address) []byte {
+	return utils.ConcatKey(contract, []byte(TOKEN_INDEX))
+}
+
+func genTokenKey(contract common.Address, id uint64) []byte {
+	return utils.ConcatKey(contract, []byte(TOKEN), genIDBytes(id))
+}
+
+func genBalanceKey(contract common.Address, id uint64, addr common.Address) []byte {
+	return utils.ConcatKey(contract, []byte(BALANCE), genIDBytes(id), addr[:])
+}
+
+func genApproveKey(contract common.Address, id uint64, from, to common.Address) []byte {
+	return utils.ConcatKey(contract, []byte(APPROVE), genIDBytes(id), from[:], to[:])
+}
+
+func getToken(native *native.NativeService, contract common.Address, id uint64) (*Token, error) {
+	item, err := utils.GetStorageItem(native, genTokenKey(contract, id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("token %d not found", id)
+	}
+	token := new(Token)
+	if err := token.Deserialization(common.NewZeroCopySource(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize token %d error: %v", id, err)
+	}
+	return token, nil
+}
+
+func putToken(native *native.NativeService, contract common.Address, token *Token) {
+	item := &cstates.StorageItem{Value: common.SerializeToBytes(token)}
+	native.CacheDB.Put(genTokenKey(contract, token.ID), item.ToArray())
+}
+
+func transfer(native *native.NativeService, contract common.Address, id uint64, from, to common.Address,
+	value uint64) error {
+	fromKey := genBalanceKey(contract, id, from)
+	fromBalance, err := utils.GetStorageUInt64(native, fromKey)
+	if err != nil {
+		return err
+	}
+	if fromBalance < value {
+		return fmt.Errorf("[Transfer] balance insufficient. token:%d, account:%s, balance:%d, transfer amount:%d",
+			id, from.ToBase58(), fromBalance, value)
+	} else if fromBalance == value {
+		native.CacheDB.Delete(fromKey)
+	} else {
+		native.CacheDB.Put(fromKey, utils.GenUInt64StorageItem(fromBalance-value).ToArray())
+	}
+
+	toKey := genBalanceKey(contract, id, to)
+	toBalance, err := utils.GetStorageUInt64(native, toKey)
+	if err != nil {
+		return err
+	}
+	native.CacheDB.Put(toKey, utils.GenUInt64StorageItem(toBalance+value).ToArray())
+	return nil
+}
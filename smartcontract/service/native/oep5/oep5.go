@@ -0,0 +1,321 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+//Package oep5 is the factory of oep5 non-fungible tokens. Each deploy creates a new collection
+//identified by an increasing id, tokens of a collection are minted by its owner
+package oep5
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+func InitOep5() {
+	native.Contracts[utils.Oep5FactoryContractAddress] = RegisterOep5Contract
+}
+
+func RegisterOep5Contract(native *native.NativeService) {
+	native.Register(DEPLOY_NAME, Deploy)
+	native.Register(MINT_NAME, Mint)
+	native.Register(TRANSFER_NAME, Transfer)
+	native.Register(APPROVE_NAME, Approve)
+	native.Register(TAKE_OWNERSHIP_NAME, TakeOwnership)
+	native.Register(NAME_NAME, Name)
+	native.Register(SYMBOL_NAME, Symbol)
+	native.Register(TOTALSUPPLY_NAME, TotalSupply)
+	native.Register(BALANCEOF_NAME, BalanceOf)
+	native.Register(OWNEROF_NAME, OwnerOf)
+	native.Register(GET_APPROVED_NAME, GetApproved)
+	native.Register(TOKEN_URI_NAME, TokenURI)
+	native.Register(GET_COLLECTION_NAME, GetCollection)
+}
+
+//Deploy creates a new collection without any token
+func Deploy(native *native.NativeService) ([]byte, error) {
+	var param DeployParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Deploy] DeployParam deserialize error!")
+	}
+	if len(param.Name) == 0 || len(param.Name) > MAX_NAME_LEN {
+		return utils.BYTE_FALSE, fmt.Errorf("[Deploy] length of name should be in [1, %d]", MAX_NAME_LEN)
+	}
+	if len(param.Symbol) == 0 || len(param.Symbol) > MAX_SYMBOL_LEN {
+		return utils.BYTE_FALSE, fmt.Errorf("[Deploy] length of symbol should be in [1, %d]", MAX_SYMBOL_LEN)
+	}
+	if !native.ContextRef.CheckWitness(param.Owner) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+
+	id, err := utils.GetStorageUInt64(native, genCollectionIndexKey(contract))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	id = id + 1
+	native.CacheDB.Put(genCollectionIndexKey(contract), utils.GenUInt64StorageItem(id).ToArray())
+
+	putCollection(native, contract, &Collection{
+		ID:     id,
+		Owner:  param.Owner,
+		Name:   param.Name,
+		Symbol: param.Symbol,
+	})
+	return common.BigIntToNeoBytes(big.NewInt(int64(id))), nil
+}
+
+//Mint creates a new token of collection, only the owner of collection can mint
+func Mint(native *native.NativeService) ([]byte, error) {
+	var param MintParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Mint] MintParam deserialize error!")
+	}
+	if len(param.URI) > MAX_URI_LEN {
+		return utils.BYTE_FALSE, fmt.Errorf("[Mint] length of uri should not be larger than %d", MAX_URI_LEN)
+	}
+	if param.To == common.ADDRESS_EMPTY {
+		return utils.BYTE_FALSE, errors.NewErr("[Mint] to address should not be empty")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	collection, err := getCollection(native, contract, param.ID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if !native.ContextRef.CheckWitness(collection.Owner) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+
+	collection.TotalSupply = collection.TotalSupply + 1
+	tokenID := collection.TotalSupply
+	putCollection(native, contract, collection)
+	if err := updateBalance(native, contract, param.ID, param.To, true); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	putAddress(native, genOwnerKey(contract, param.ID, tokenID), param.To)
+	if len(param.URI) != 0 {
+		item := &cstates.StorageItem{Value: []byte(param.URI)}
+		native.CacheDB.Put(genURIKey(contract, param.ID, tokenID), item.ToArray())
+	}
+	addNotifications(native, contract, TRANSFER_NAME, param.ID, common.ADDRESS_EMPTY, param.To, tokenID)
+	return common.BigIntToNeoBytes(new(big.Int).SetUint64(tokenID)), nil
+}
+
+func Transfer(native *native.NativeService) ([]byte, error) {
+	var param TransferParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Transfer] TransferParam deserialize error!")
+	}
+	if param.To == common.ADDRESS_EMPTY {
+		return utils.BYTE_FALSE, errors.NewErr("[Transfer] to address should not be empty")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	owner, err := getOwner(native, contract, param.ID, param.TokenID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if owner != param.From {
+		return utils.BYTE_FALSE, fmt.Errorf("[Transfer] token %d is not owned by %s", param.TokenID, param.From.ToBase58())
+	}
+	if !native.ContextRef.CheckWitness(param.From) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	if err := transfer(native, contract, param.ID, param.From, param.To, param.TokenID); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	addNotifications(native, contract, TRANSFER_NAME, param.ID, param.From, param.To, param.TokenID)
+	return utils.BYTE_TRUE, nil
+}
+
+//Approve allows To to take ownership of token, empty To address cancels the approval
+func Approve(native *native.NativeService) ([]byte, error) {
+	var param TransferParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[Approve] TransferParam deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	owner, err := getOwner(native, contract, param.ID, param.TokenID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if owner != param.From {
+		return utils.BYTE_FALSE, fmt.Errorf("[Approve] token %d is not owned by %s", param.TokenID, param.From.ToBase58())
+	}
+	if !native.ContextRef.CheckWitness(param.From) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	if param.To == common.ADDRESS_EMPTY {
+		native.CacheDB.Delete(genApprovedKey(contract, param.ID, param.TokenID))
+	} else {
+		putAddress(native, genApprovedKey(contract, param.ID, param.TokenID), param.To)
+	}
+	addNotifications(native, contract, APPROVAL_EVENT, param.ID, param.From, param.To, param.TokenID)
+	return utils.BYTE_TRUE, nil
+}
+
+//TakeOwnership transfers token to the approved address
+func TakeOwnership(native *native.NativeService) ([]byte, error) {
+	var param TakeOwnershipParam
+	if err := param.Deserialization(common.NewZeroCopySource(native.Input)); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[TakeOwnership] TakeOwnershipParam deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	owner, err := getOwner(native, contract, param.ID, param.TokenID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	approved, err := getAddress(native, genApprovedKey(contract, param.ID, param.TokenID))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if approved == common.ADDRESS_EMPTY || approved != param.To {
+		return utils.BYTE_FALSE, fmt.Errorf("[TakeOwnership] token %d is not approved to %s", param.TokenID, param.To.ToBase58())
+	}
+	if !native.ContextRef.CheckWitness(param.To) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	if err := transfer(native, contract, param.ID, owner, param.To, param.TokenID); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	addNotifications(native, contract, TRANSFER_NAME, param.ID, owner, param.To, param.TokenID)
+	return utils.BYTE_TRUE, nil
+}
+
+func Name(native *native.NativeService) ([]byte, error) {
+	collection, err := getCollectionByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return []byte(collection.Name), nil
+}
+
+func Symbol(native *native.NativeService) ([]byte, error) {
+	collection, err := getCollectionByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return []byte(collection.Symbol), nil
+}
+
+func TotalSupply(native *native.NativeService) ([]byte, error) {
+	collection, err := getCollectionByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.BigIntToNeoBytes(new(big.Int).SetUint64(collection.TotalSupply)), nil
+}
+
+func GetCollection(native *native.NativeService) ([]byte, error) {
+	collection, err := getCollectionByInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.SerializeToBytes(collection), nil
+}
+
+func BalanceOf(native *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[BalanceOf] get collection id error!")
+	}
+	addr, err := utils.DecodeAddress(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[BalanceOf] get address error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	amount, err := utils.GetStorageUInt64(native, genBalanceKey(contract, id, addr))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.BigIntToNeoBytes(new(big.Int).SetUint64(amount)), nil
+}
+
+func OwnerOf(native *native.NativeService) ([]byte, error) {
+	id, tokenID, err := decodeTokenInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	owner, err := getOwner(native, contract, id, tokenID)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return owner[:], nil
+}
+
+func GetApproved(native *native.NativeService) ([]byte, error) {
+	id, tokenID, err := decodeTokenInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	if _, err := getOwner(native, contract, id, tokenID); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	approved, err := getAddress(native, genApprovedKey(contract, id, tokenID))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return approved[:], nil
+}
+
+func TokenURI(native *native.NativeService) ([]byte, error) {
+	id, tokenID, err := decodeTokenInput(native)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	if _, err := getOwner(native, contract, id, tokenID); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	item, err := utils.GetStorageItem(native, genURIKey(contract, id, tokenID))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if item == nil {
+		return []byte{}, nil
+	}
+	return item.Value, nil
+}
+
+func getCollectionByInput(native *native.NativeService) (*Collection, error) {
+	id, err := utils.DecodeVarUint(common.NewZeroCopySource(native.Input))
+	if err != nil {
+		return nil, errors.NewDetailErr(err, errors.ErrNoCode, "get collection id error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	return getCollection(native, contract, id)
+}
+
+func decodeTokenInput(native *native.NativeService) (uint64, uint64, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return 0, 0, errors.NewDetailErr(err, errors.ErrNoCode, "get collection id error!")
+	}
+	tokenID, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return 0, 0, errors.NewDetailErr(err, errors.ErrNoCode, "get token id error!")
+	}
+	return id, tokenID, nil
+}
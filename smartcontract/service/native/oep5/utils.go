@@ -0,0 +1,193 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package oep5
+
+import (
+	"fmt"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	//function name
+	DEPLOY_NAME         = "deploy"
+	MINT_NAME           = "mint"
+	TRANSFER_NAME       = "transfer"
+	APPROVE_NAME        = "approve"
+	TAKE_OWNERSHIP_NAME = "takeOwnership"
+	NAME_NAME           = "name"
+	SYMBOL_NAME         = "symbol"
+	TOTALSUPPLY_NAME    = "totalSupply"
+	BALANCEOF_NAME      = "balanceOf"
+	OWNEROF_NAME        = "ownerOf"
+	GET_APPROVED_NAME   = "getApproved"
+	TOKEN_URI_NAME      = "tokenURI"
+	GET_COLLECTION_NAME = "getCollection"
+
+	//event name
+	APPROVAL_EVENT = "approval"
+
+	//key prefix
+	COLLECTION_INDEX = "collectionIndex"
+	COLLECTION       = "collection"
+	BALANCE          = "balance"
+	OWNER            = "owner"
+	APPROVED         = "approved"
+	URI              = "uri"
+
+	//limits of token meta
+	MAX_NAME_LEN   = 64
+	MAX_SYMBOL_LEN = 16
+	MAX_URI_LEN    = 256
+)
+
+//addNotifications keeps the states of ont transfer event, value is the token id and
+//collection id is appended
+func addNotifications(native *native.NativeService, contract common.Address, method string, id uint64,
+	from, to common.Address, tokenID uint64) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{method, from.ToBase58(), to.ToBase58(), tokenID, id},
+		})
+}
+
+func genIDBytes(id uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteUint64(id)
+	return sink.Bytes()
+}
+
+func genCollectionIndexKey(contract common.Address) []byte {
+	return utils.ConcatKey(contract, []byte(COLLECTION_INDEX))
+}
+
+func genCollectionKey(contract common.Address, id uint64) []byte {
+	return utils.ConcatKey(contract, []byte(COLLECTION), genIDBytes(id))
+}
+
+func genBalanceKey(contract common.Address, id uint64, addr common.Address) []byte {
+	return utils.ConcatKey(contract, []byte(BALANCE), genIDBytes(id), addr[:])
+}
+
+func genOwnerKey(contract common.Address, id, tokenID uint64) []byte {
+	return utils.ConcatKey(contract, []byte(OWNER), genIDBytes(id), genIDBytes(tokenID))
+}
+
+func genApprovedKey(contract common.Address, id, tokenID uint64) []byte {
+	return utils.ConcatKey(contract, []byte(APPROVED), genIDBytes(id), genIDBytes(tokenID))
+}
+
+func genURIKey(contract common.Address, id, tokenID uint64) []byte {
+	return utils.ConcatKey(contract, []byte(URI), genIDBytes(id), genIDBytes(tokenID))
+}
+
+func getCollection(native *native.NativeService, contract common.Address, id uint64) (*Collection, error) {
+	item, err := utils.GetStorageItem(native, genCollectionKey(contract, id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("collection %d not found", id)
+	}
+	collection := new(Collection)
+	if err := collection.Deserialization(common.NewZeroCopySource(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize collection %d error: %v", id, err)
+	}
+	return collection, nil
+}
+
+func putCollection(native *native.NativeService, contract common.Address, collection *Collection) {
+	item := &cstates.StorageItem{Value: common.SerializeToBytes(collection)}
+	native.CacheDB.Put(genCollectionKey(contract, collection.ID), item.ToArray())
+}
+
+//getAddress returns empty address if key not exists
+func getAddress(native *native.NativeService, key []byte) (common.Address, error) {
+	item, err := utils.GetStorageItem(native, key)
+	if err != nil {
+		return common.ADDRESS_EMPTY, err
+	}
+	if item == nil {
+		return common.ADDRESS_EMPTY, nil
+	}
+	return common.AddressParseFromBytes(item.Value)
+}
+
+func putAddress(native *native.NativeService, key []byte, addr common.Address) {
+	item := &cstates.StorageItem{Value: addr[:]}
+	native.CacheDB.Put(key, item.ToArray())
+}
+
+func getOwner(native *native.NativeService, contract common.Address, id, tokenID uint64) (common.Address, error) {
+	owner, err := getAddress(native, genOwnerKey(contract, id, tokenID))
+	if err != nil {
+		return common.ADDRESS_EMPTY, err
+	}
+	if owner == common.ADDRESS_EMPTY {
+		return common.ADDRESS_EMPTY, fmt.Errorf("token %d of collection %d not found", tokenID, id)
+	}
+	return owner, nil
+}
+
+//updateBalance increases or decreases the num of tokens owned by addr by one
+func updateBalance(native *native.NativeService, contract common.Address, id uint64, addr common.Address,
+	increase bool) error {
+	key := genBalanceKey(contract, id, addr)
+	balance, err := utils.GetStorageUInt64(native, key)
+	if err != nil {
+		return err
+	}
+	if increase {
+		balance = balance + 1
+	} else {
+		if balance == 0 {
+			return fmt.Errorf("balance of %s is zero", addr.ToBase58())
+		}
+		balance = balance - 1
+	}
+	if balance == 0 {
+		native.CacheDB.Delete(key)
+	} else {
+		native.CacheDB.Put(key, utils.GenUInt64StorageItem(balance).ToArray())
+	}
+	return nil
+}
+
+//transfer moves token to new owner and clears the approval of token
+func transfer(native *native.NativeService, contract common.Address, id uint64, from, to common.Address,
+	tokenID uint64) error {
+	if err := updateBalance(native, contract, id, from, false); err != nil {
+		return err
+	}
+	if err := updateBalance(native, contract, id, to, true); err != nil {
+		return err
+	}
+	putAddress(native, genOwnerKey(contract, id, tokenID), to)
+	native.CacheDB.Delete(genApprovedKey(contract, id, tokenID))
+	return nil
+}
@@ -42,12 +42,17 @@ func RegisterOngContract(native *native.NativeService) {
 	native.Register(ont.TRANSFER_NAME, OngTransfer)
 	native.Register(ont.APPROVE_NAME, OngApprove)
 	native.Register(ont.TRANSFERFROM_NAME, OngTransferFrom)
+	native.Register(ont.TRANSFERMULTI_NAME, OngTransferMulti)
 	native.Register(ont.NAME_NAME, OngName)
 	native.Register(ont.SYMBOL_NAME, OngSymbol)
 	native.Register(ont.DECIMALS_NAME, OngDecimals)
 	native.Register(ont.TOTALSUPPLY_NAME, OngTotalSupply)
 	native.Register(ont.BALANCEOF_NAME, OngBalanceOf)
 	native.Register(ont.ALLOWANCE_NAME, OngAllowance)
+	native.Register(ont.CREATE_VESTING_NAME, OngCreateVesting)
+	native.Register(ont.RELEASE_VESTING_NAME, ont.ReleaseVesting)
+	native.Register(ont.REVOKE_VESTING_NAME, ont.RevokeVesting)
+	native.Register(ont.GET_VESTING_NAME, ont.GetVesting)
 }
 
 // 这是 ong系统合约的 init 方法
@@ -62,10 +67,52 @@ func OngInit(native *native.NativeService) ([]byte, error) {
 		return utils.BYTE_FALSE, errors.NewErr("Init ong has been completed!")
 	}
 
+	//ong may be prefunded to accounts of solo chain. The prefund is minted on
+	//top of the total supply, so the ong held by ont contract to be unbound
+	//is not affected
+	distribute := make(map[common.Address]uint64)
+	var addrs []common.Address
+	total := uint64(constants.ONG_TOTAL_SUPPLY)
+	source := common.NewZeroCopySource(native.Input)
+	buf, _, irregular, eof := source.NextVarBytes()
+	if irregular {
+		return utils.BYTE_FALSE, common.ErrIrregularData
+	}
+	if !eof && len(buf) > 0 {
+		input := common.NewZeroCopySource(buf)
+		num, err := utils.DecodeVarUint(input)
+		if err != nil {
+			return utils.BYTE_FALSE, fmt.Errorf("read number error:%v", err)
+		}
+		for i := uint64(0); i < num; i++ {
+			addr, err := utils.DecodeAddress(input)
+			if err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("read address error:%v", err)
+			}
+			value, err := utils.DecodeVarUint(input)
+			if err != nil {
+				return utils.BYTE_FALSE, fmt.Errorf("read value error:%v", err)
+			}
+			var overflow bool
+			total, overflow = common.SafeAdd(total, value)
+			if overflow {
+				return utils.BYTE_FALSE, fmt.Errorf("wrong config. prefund overflow")
+			}
+			if _, ok := distribute[addr]; !ok {
+				addrs = append(addrs, addr)
+			}
+			distribute[addr] += value
+		}
+	}
+
 	item := utils.GenUInt64StorageItem(constants.ONG_TOTAL_SUPPLY)
-	native.CacheDB.Put(ont.GenTotalSupplyKey(contract), item.ToArray())
+	native.CacheDB.Put(ont.GenTotalSupplyKey(contract), utils.GenUInt64StorageItem(total).ToArray())
 	native.CacheDB.Put(append(contract[:], utils.OntContractAddress[:]...), item.ToArray())
 	ont.AddNotifications(native, contract, &ont.State{To: utils.OntContractAddress, Value: constants.ONG_TOTAL_SUPPLY})
+	for _, addr := range addrs {
+		native.CacheDB.Put(ont.GenBalanceKey(contract, addr), utils.GenUInt64StorageItem(distribute[addr]).ToArray())
+		ont.AddNotifications(native, contract, &ont.State{To: addr, Value: distribute[addr]})
+	}
 	return utils.BYTE_TRUE, nil
 }
 
@@ -92,6 +139,25 @@ func OngTransfer(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+func OngTransferMulti(native *native.NativeService) ([]byte, error) {
+	var transfers ont.TransferMulti
+	source := common.NewZeroCopySource(native.Input)
+	if err := transfers.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[OngTransferMulti] TransferMulti deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	for _, batch := range transfers.Batches {
+		_, _, total, err := ont.MultiTransfer(native, contract, &batch, constants.ONG_TOTAL_SUPPLY)
+		if err != nil {
+			return utils.BYTE_FALSE, err
+		}
+		if total != 0 {
+			ont.AddMultiNotifications(native, contract, &batch, total)
+		}
+	}
+	return utils.BYTE_TRUE, nil
+}
+
 
 // ong 系统合约的 Approve 方法
 func OngApprove(native *native.NativeService) ([]byte, error) {
@@ -165,3 +231,7 @@ func OngBalanceOf(native *native.NativeService) ([]byte, error) {
 func OngAllowance(native *native.NativeService) ([]byte, error) {
 	return ont.GetBalanceValue(native, ont.APPROVE_FLAG)
 }
+
+func OngCreateVesting(native *native.NativeService) ([]byte, error) {
+	return ont.CreateVesting(native, constants.ONG_TOTAL_SUPPLY)
+}
@@ -46,12 +46,17 @@ func RegisterOntContract(native *native.NativeService) {
 	native.Register(TRANSFER_NAME, OntTransfer)
 	native.Register(APPROVE_NAME, OntApprove)
 	native.Register(TRANSFERFROM_NAME, OntTransferFrom)
+	native.Register(TRANSFERMULTI_NAME, OntTransferMulti)
 	native.Register(NAME_NAME, OntName)
 	native.Register(SYMBOL_NAME, OntSymbol)
 	native.Register(DECIMALS_NAME, OntDecimals)
 	native.Register(TOTALSUPPLY_NAME, OntTotalSupply)
 	native.Register(BALANCEOF_NAME, OntBalanceOf)
 	native.Register(ALLOWANCE_NAME, OntAllowance)
+	native.Register(CREATE_VESTING_NAME, OntCreateVesting)
+	native.Register(RELEASE_VESTING_NAME, ReleaseVesting)
+	native.Register(REVOKE_VESTING_NAME, RevokeVesting)
+	native.Register(GET_VESTING_NAME, GetVesting)
 }
 
 func OntInit(native *native.NativeService) ([]byte, error) {
@@ -170,6 +175,39 @@ func OntTransferFrom(native *native.NativeService) ([]byte, error) {
 	return utils.BYTE_TRUE, nil
 }
 
+func OntTransferMulti(native *native.NativeService) ([]byte, error) {
+	var transfers TransferMulti
+	source := common.NewZeroCopySource(native.Input)
+	if err := transfers.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[OntTransferMulti] TransferMulti deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	for _, batch := range transfers.Batches {
+		fromBalance, toBalances, total, err := MultiTransfer(native, contract, &batch, constants.ONT_TOTAL_SUPPLY)
+		if err != nil {
+			return utils.BYTE_FALSE, err
+		}
+		if total == 0 {
+			continue
+		}
+
+		if err := grantOng(native, contract, batch.From, fromBalance); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+		for i, v := range batch.Recipients {
+			if v.Value == 0 {
+				continue
+			}
+			if err := grantOng(native, contract, v.To, toBalances[i]); err != nil {
+				return utils.BYTE_FALSE, err
+			}
+		}
+
+		AddMultiNotifications(native, contract, &batch, total)
+	}
+	return utils.BYTE_TRUE, nil
+}
+
 func OntApprove(native *native.NativeService) ([]byte, error) {
 	var state State
 	source := common.NewZeroCopySource(native.Input)
@@ -19,6 +19,10 @@
 package ont
 
 import (
+	"fmt"
+	"math"
+	"math/big"
+
 	"github.com/ontio/ontology/common"
 	"github.com/ontio/ontology/smartcontract/service/native/utils"
 )
@@ -111,3 +115,210 @@ func (this *TransferFrom) Deserialization(source *common.ZeroCopySource) error {
 
 	return err
 }
+
+type VestingParam struct {
+	Creator     common.Address
+	Beneficiary common.Address
+	Amount      uint64
+	Start       uint64 //unix timestamp vesting starts
+	Cliff       uint64 //nothing is vested before cliff
+	End         uint64 //all amount is vested at end
+	Revocable   bool
+}
+
+func (this *VestingParam) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeAddress(sink, this.Creator)
+	utils.EncodeAddress(sink, this.Beneficiary)
+	utils.EncodeVarUint(sink, this.Amount)
+	utils.EncodeVarUint(sink, this.Start)
+	utils.EncodeVarUint(sink, this.Cliff)
+	utils.EncodeVarUint(sink, this.End)
+	sink.WriteBool(this.Revocable)
+}
+
+func (this *VestingParam) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.Creator, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Beneficiary, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Amount, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.Start, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.Cliff, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.End, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	this.Revocable, err = utils.DecodeBool(source)
+
+	return err
+}
+
+type VestingSchedule struct {
+	ID            uint64
+	Creator       common.Address
+	Beneficiary   common.Address
+	Amount        uint64
+	Released      uint64
+	Start         uint32
+	Cliff         uint32
+	End           uint32
+	Revocable     bool
+	UnboundOffset uint32 //ong of locked ont is unbound to beneficiary since this offset
+}
+
+//Vested returns the amount vested at timestamp, linear between start and end
+func (this *VestingSchedule) Vested(timestamp uint32) uint64 {
+	if timestamp < this.Cliff {
+		return 0
+	}
+	if timestamp >= this.End {
+		return this.Amount
+	}
+	vested := new(big.Int).SetUint64(this.Amount)
+	vested.Mul(vested, big.NewInt(int64(timestamp-this.Start)))
+	vested.Div(vested, big.NewInt(int64(this.End-this.Start)))
+	return vested.Uint64()
+}
+
+func (this *VestingSchedule) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, this.ID)
+	utils.EncodeAddress(sink, this.Creator)
+	utils.EncodeAddress(sink, this.Beneficiary)
+	utils.EncodeVarUint(sink, this.Amount)
+	utils.EncodeVarUint(sink, this.Released)
+	utils.EncodeVarUint(sink, uint64(this.Start))
+	utils.EncodeVarUint(sink, uint64(this.Cliff))
+	utils.EncodeVarUint(sink, uint64(this.End))
+	sink.WriteBool(this.Revocable)
+	utils.EncodeVarUint(sink, uint64(this.UnboundOffset))
+}
+
+func (this *VestingSchedule) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	if this.ID, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.Creator, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Beneficiary, err = utils.DecodeAddress(source); err != nil {
+		return err
+	}
+	if this.Amount, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	if this.Released, err = utils.DecodeVarUint(source); err != nil {
+		return err
+	}
+	timestamps := make([]uint64, 3)
+	for i := range timestamps {
+		if timestamps[i], err = utils.DecodeVarUint(source); err != nil {
+			return err
+		}
+		if timestamps[i] > math.MaxUint32 {
+			return fmt.Errorf("timestamp %d overflow", timestamps[i])
+		}
+	}
+	this.Start, this.Cliff, this.End = uint32(timestamps[0]), uint32(timestamps[1]), uint32(timestamps[2])
+	if this.Revocable, err = utils.DecodeBool(source); err != nil {
+		return err
+	}
+	offset, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	if offset > math.MaxUint32 {
+		return fmt.Errorf("unbound offset %d overflow", offset)
+	}
+	this.UnboundOffset = uint32(offset)
+	return nil
+}
+
+// TransferMulti, transfers of each batch share a single witness check of sender
+type TransferMulti struct {
+	Batches []TransferBatch
+}
+
+func (this *TransferMulti) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeVarUint(sink, uint64(len(this.Batches)))
+	for _, v := range this.Batches {
+		v.Serialization(sink)
+	}
+}
+
+func (this *TransferMulti) Deserialization(source *common.ZeroCopySource) error {
+	n, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	for i := 0; uint64(i) < n; i++ {
+		var batch TransferBatch
+		if err := batch.Deserialization(source); err != nil {
+			return err
+		}
+		this.Batches = append(this.Batches, batch)
+	}
+	return nil
+}
+
+type TransferBatch struct {
+	From       common.Address
+	Recipients []Recipient
+}
+
+func (this *TransferBatch) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeAddress(sink, this.From)
+	utils.EncodeVarUint(sink, uint64(len(this.Recipients)))
+	for _, v := range this.Recipients {
+		v.Serialization(sink)
+	}
+}
+
+func (this *TransferBatch) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	this.From, err = utils.DecodeAddress(source)
+	if err != nil {
+		return err
+	}
+	n, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return err
+	}
+	for i := 0; uint64(i) < n; i++ {
+		var recipient Recipient
+		if err := recipient.Deserialization(source); err != nil {
+			return err
+		}
+		this.Recipients = append(this.Recipients, recipient)
+	}
+	return nil
+}
+
+type Recipient struct {
+	To    common.Address
+	Value uint64
+}
+
+func (this *Recipient) Serialization(sink *common.ZeroCopySink) {
+	utils.EncodeAddress(sink, this.To)
+	utils.EncodeVarUint(sink, this.Value)
+}
+
+func (this *Recipient) Deserialization(source *common.ZeroCopySource) error {
+	var err error
+	this.To, err = utils.DecodeAddress(source)
+	if err != nil {
+		return err
+	}
+	this.Value, err = utils.DecodeVarUint(source)
+
+	return err
+}
@@ -42,3 +42,65 @@ func TestState_Serialize(t *testing.T) {
 
 	assert.Equal(t, state, state2)
 }
+
+func TestVestingSchedule(t *testing.T) {
+	schedule := &VestingSchedule{
+		ID:            1,
+		Creator:       common.AddressFromVmCode([]byte{1, 2, 3}),
+		Beneficiary:   common.AddressFromVmCode([]byte{4, 5, 6}),
+		Amount:        1000000000000000000,
+		Released:      10,
+		Start:         1000,
+		Cliff:         2000,
+		End:           11000,
+		Revocable:     true,
+		UnboundOffset: 500,
+	}
+	schedule2 := new(VestingSchedule)
+	err := schedule2.Deserialization(common.NewZeroCopySource(common.SerializeToBytes(schedule)))
+	assert.Nil(t, err)
+	assert.Equal(t, schedule, schedule2)
+
+	assert.Equal(t, uint64(0), schedule.Vested(1999))
+	assert.Equal(t, uint64(100000000000000000), schedule.Vested(2000))
+	assert.Equal(t, uint64(500000000000000000), schedule.Vested(6000))
+	assert.Equal(t, schedule.Amount, schedule.Vested(11000))
+	assert.Equal(t, schedule.Amount, schedule.Vested(20000))
+}
+
+func TestVestingParam(t *testing.T) {
+	param := VestingParam{
+		Creator:     common.AddressFromVmCode([]byte{1, 2, 3}),
+		Beneficiary: common.AddressFromVmCode([]byte{4, 5, 6}),
+		Amount:      100,
+		Start:       1000,
+		Cliff:       1000,
+		End:         2000,
+	}
+	sink := common.NewZeroCopySink(nil)
+	param.Serialization(sink)
+
+	param2 := VestingParam{}
+	assert.Nil(t, param2.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, param, param2)
+}
+
+func TestTransferMulti_Serialize(t *testing.T) {
+	transfers := TransferMulti{
+		Batches: []TransferBatch{
+			{
+				From: common.AddressFromVmCode([]byte{1, 2, 3}),
+				Recipients: []Recipient{
+					{To: common.AddressFromVmCode([]byte{4, 5, 6}), Value: 1},
+					{To: common.AddressFromVmCode([]byte{7, 8, 9}), Value: 2},
+				},
+			},
+		},
+	}
+	sink := common.NewZeroCopySink(nil)
+	transfers.Serialization(sink)
+
+	transfers2 := TransferMulti{}
+	assert.Nil(t, transfers2.Deserialization(common.NewZeroCopySource(sink.Bytes())))
+	assert.Equal(t, transfers, transfers2)
+}
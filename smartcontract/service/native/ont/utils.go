@@ -31,18 +31,25 @@ import (
 )
 
 const (
-	UNBOUND_TIME_OFFSET = "unboundTimeOffset"
-	TOTAL_SUPPLY_NAME   = "totalSupply"
-	INIT_NAME           = "init"
-	TRANSFER_NAME       = "transfer"
-	APPROVE_NAME        = "approve"
-	TRANSFERFROM_NAME   = "transferFrom"
-	NAME_NAME           = "name"
-	SYMBOL_NAME         = "symbol"
-	DECIMALS_NAME       = "decimals"
-	TOTALSUPPLY_NAME    = "totalSupply"
-	BALANCEOF_NAME      = "balanceOf"
-	ALLOWANCE_NAME      = "allowance"
+	UNBOUND_TIME_OFFSET  = "unboundTimeOffset"
+	TOTAL_SUPPLY_NAME    = "totalSupply"
+	INIT_NAME            = "init"
+	TRANSFER_NAME        = "transfer"
+	APPROVE_NAME         = "approve"
+	TRANSFERFROM_NAME    = "transferFrom"
+	TRANSFERMULTI_NAME   = "transferMulti"
+	NAME_NAME            = "name"
+	SYMBOL_NAME          = "symbol"
+	DECIMALS_NAME        = "decimals"
+	TOTALSUPPLY_NAME     = "totalSupply"
+	BALANCEOF_NAME       = "balanceOf"
+	ALLOWANCE_NAME       = "allowance"
+	CREATE_VESTING_NAME  = "createVesting"
+	RELEASE_VESTING_NAME = "releaseVesting"
+	REVOKE_VESTING_NAME  = "revokeVesting"
+	GET_VESTING_NAME     = "getVesting"
+	VESTING_INDEX        = "vestingIndex"
+	VESTING              = "vesting"
 )
 
 func AddNotifications(native *native.NativeService, contract common.Address, state *State) {
@@ -56,6 +63,25 @@ func AddNotifications(native *native.NativeService, contract common.Address, sta
 		})
 }
 
+//AddMultiNotifications add one event for a batch of transfers
+func AddMultiNotifications(native *native.NativeService, contract common.Address, batch *TransferBatch, total uint64) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	recipients := make([]interface{}, 0, len(batch.Recipients))
+	for _, v := range batch.Recipients {
+		if v.Value == 0 {
+			continue
+		}
+		recipients = append(recipients, []interface{}{v.To.ToBase58(), v.Value})
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{TRANSFERMULTI_NAME, batch.From.ToBase58(), total, recipients},
+		})
+}
+
 func GetToUInt64StorageItem(toBalance, value uint64) *cstates.StorageItem {
 	sink := common.NewZeroCopySink(nil)
 	sink.WriteUint64(toBalance + value)
@@ -87,6 +113,46 @@ func Transfer(native *native.NativeService, contract common.Address, state *Stat
 	return fromBalance, toBalance, nil
 }
 
+//MultiTransfer debits the total of batch from sender once and credits every recipient,
+//returns the balance of sender and recipients before transfer
+func MultiTransfer(native *native.NativeService, contract common.Address, batch *TransferBatch,
+	totalSupply uint64) (uint64, []uint64, uint64, error) {
+	if !native.ContextRef.CheckWitness(batch.From) {
+		return 0, nil, 0, errors.NewErr("authentication failed!")
+	}
+
+	var total uint64
+	for _, v := range batch.Recipients {
+		if v.Value > totalSupply {
+			return 0, nil, 0, fmt.Errorf("[MultiTransfer] transfer amount:%d over totalSupply:%d", v.Value, totalSupply)
+		}
+		total += v.Value
+		if total > totalSupply {
+			return 0, nil, 0, fmt.Errorf("[MultiTransfer] total amount of batch over totalSupply:%d", totalSupply)
+		}
+	}
+	if total == 0 {
+		return 0, nil, 0, nil
+	}
+
+	fromBalance, err := fromTransfer(native, GenBalanceKey(contract, batch.From), total)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	toBalances := make([]uint64, len(batch.Recipients))
+	for i, v := range batch.Recipients {
+		if v.Value == 0 {
+			continue
+		}
+		toBalances[i], err = toTransfer(native, GenBalanceKey(contract, v.To), v.Value)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+	}
+	return fromBalance, toBalances, total, nil
+}
+
 func GenApproveKey(contract, from, to common.Address) []byte {
 	temp := append(contract[:], from[:]...)
 	return append(temp, to[:]...)
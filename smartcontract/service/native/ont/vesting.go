@@ -0,0 +1,263 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ont
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/config"
+	"github.com/ontio/ontology/common/constants"
+	cstates "github.com/ontio/ontology/core/states"
+	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/smartcontract/event"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+//Vesting escrow shared by ont and ong contract. Escrowed tokens are removed from the
+//balance of creator and only kept in the schedule until released to beneficiary or
+//returned to creator by revoking. Ong of locked ont keeps unbinding to beneficiary.
+
+func OntCreateVesting(native *native.NativeService) ([]byte, error) {
+	return CreateVesting(native, constants.ONT_TOTAL_SUPPLY)
+}
+
+func CreateVesting(native *native.NativeService, totalSupply uint64) ([]byte, error) {
+	var param VestingParam
+	source := common.NewZeroCopySource(native.Input)
+	if err := param.Deserialization(source); err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[CreateVesting] VestingParam deserialize error!")
+	}
+	if param.Amount == 0 || param.Amount > totalSupply {
+		return utils.BYTE_FALSE, fmt.Errorf("[CreateVesting] vesting amount:%d should be in (0, %d]", param.Amount, totalSupply)
+	}
+	if param.End > math.MaxUint32 || param.Start >= param.End || param.Cliff < param.Start || param.Cliff > param.End {
+		return utils.BYTE_FALSE, fmt.Errorf("[CreateVesting] invalid schedule, start:%d, cliff:%d, end:%d",
+			param.Start, param.Cliff, param.End)
+	}
+	if !native.ContextRef.CheckWitness(param.Creator) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	fromBalance, err := fromTransfer(native, GenBalanceKey(contract, param.Creator), param.Amount)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if contract == utils.OntContractAddress {
+		if err := grantOng(native, contract, param.Creator, fromBalance); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+	}
+
+	id, err := utils.GetStorageUInt64(native, genVestingIndexKey(contract))
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	id = id + 1
+	native.CacheDB.Put(genVestingIndexKey(contract), utils.GenUInt64StorageItem(id).ToArray())
+
+	schedule := &VestingSchedule{
+		ID:            id,
+		Creator:       param.Creator,
+		Beneficiary:   param.Beneficiary,
+		Amount:        param.Amount,
+		Start:         uint32(param.Start),
+		Cliff:         uint32(param.Cliff),
+		End:           uint32(param.End),
+		Revocable:     param.Revocable,
+		UnboundOffset: currentUnboundOffset(native),
+	}
+	putVesting(native, contract, schedule)
+	addVestingNotifications(native, contract, CREATE_VESTING_NAME, id, param.Creator, param.Amount)
+	return common.BigIntToNeoBytes(big.NewInt(int64(id))), nil
+}
+
+//ReleaseVesting transfers vested but unreleased amount to beneficiary
+func ReleaseVesting(native *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[ReleaseVesting] id deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	schedule, err := getVesting(native, contract, id)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if !native.ContextRef.CheckWitness(schedule.Beneficiary) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	value := schedule.Vested(native.Time) - schedule.Released
+	if value == 0 {
+		return utils.BYTE_FALSE, fmt.Errorf("[ReleaseVesting] no vested amount of vesting %d to release", id)
+	}
+	if err := grantVestingOng(native, contract, schedule); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	schedule.Released = schedule.Released + value
+	if err := releaseTo(native, contract, schedule.Beneficiary, value); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if schedule.Released == schedule.Amount {
+		native.CacheDB.Delete(genVestingKey(contract, id))
+	} else {
+		putVesting(native, contract, schedule)
+	}
+	addVestingNotifications(native, contract, RELEASE_VESTING_NAME, id, schedule.Beneficiary, value)
+	return utils.BYTE_TRUE, nil
+}
+
+//RevokeVesting releases vested amount to beneficiary and returns the rest to creator
+func RevokeVesting(native *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[RevokeVesting] id deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	schedule, err := getVesting(native, contract, id)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	if !schedule.Revocable {
+		return utils.BYTE_FALSE, fmt.Errorf("[RevokeVesting] vesting %d is not revocable", id)
+	}
+	if !native.ContextRef.CheckWitness(schedule.Creator) {
+		return utils.BYTE_FALSE, errors.NewErr("authentication failed!")
+	}
+	if err := grantVestingOng(native, contract, schedule); err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	vested := schedule.Vested(native.Time)
+	if vested > schedule.Released {
+		if err := releaseTo(native, contract, schedule.Beneficiary, vested-schedule.Released); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+	}
+	if schedule.Amount > vested {
+		if err := releaseTo(native, contract, schedule.Creator, schedule.Amount-vested); err != nil {
+			return utils.BYTE_FALSE, err
+		}
+	}
+	native.CacheDB.Delete(genVestingKey(contract, id))
+	addVestingNotifications(native, contract, REVOKE_VESTING_NAME, id, schedule.Creator, schedule.Amount-vested)
+	return utils.BYTE_TRUE, nil
+}
+
+func GetVesting(native *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(native.Input)
+	id, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.NewDetailErr(err, errors.ErrNoCode, "[GetVesting] id deserialize error!")
+	}
+	contract := native.ContextRef.CurrentContext().ContractAddress
+	schedule, err := getVesting(native, contract, id)
+	if err != nil {
+		return utils.BYTE_FALSE, err
+	}
+	return common.SerializeToBytes(schedule), nil
+}
+
+func releaseTo(native *native.NativeService, contract, address common.Address, value uint64) error {
+	toBalance, err := toTransfer(native, GenBalanceKey(contract, address), value)
+	if err != nil {
+		return err
+	}
+	if contract == utils.OntContractAddress {
+		return grantOng(native, contract, address, toBalance)
+	}
+	return nil
+}
+
+//grantVestingOng approves ong unbound by the locked ont of schedule to beneficiary
+func grantVestingOng(native *native.NativeService, contract common.Address, schedule *VestingSchedule) error {
+	if contract != utils.OntContractAddress {
+		return nil
+	}
+	endOffset := currentUnboundOffset(native)
+	if endOffset <= schedule.UnboundOffset {
+		return nil
+	}
+	value := utils.CalcUnbindOng(schedule.Amount-schedule.Released, schedule.UnboundOffset, endOffset)
+	if value != 0 {
+		args, err := getApproveArgs(native, contract, utils.OngContractAddress, schedule.Beneficiary, value)
+		if err != nil {
+			return err
+		}
+		if _, err := native.NativeCall(utils.OngContractAddress, "approve", args); err != nil {
+			return err
+		}
+	}
+	schedule.UnboundOffset = endOffset
+	return nil
+}
+
+func currentUnboundOffset(native *native.NativeService) uint32 {
+	if native.Time <= constants.GENESIS_BLOCK_TIMESTAMP {
+		return 0
+	}
+	return native.Time - constants.GENESIS_BLOCK_TIMESTAMP
+}
+
+func addVestingNotifications(native *native.NativeService, contract common.Address, method string, id uint64,
+	address common.Address, value uint64) {
+	if !config.DefConfig.Common.EnableEventLog {
+		return
+	}
+	native.Notifications = append(native.Notifications,
+		&event.NotifyEventInfo{
+			ContractAddress: contract,
+			States:          []interface{}{method, id, address.ToBase58(), value},
+		})
+}
+
+func getVesting(native *native.NativeService, contract common.Address, id uint64) (*VestingSchedule, error) {
+	item, err := utils.GetStorageItem(native, genVestingKey(contract, id))
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, fmt.Errorf("vesting %d not found", id)
+	}
+	schedule := new(VestingSchedule)
+	if err := schedule.Deserialization(common.NewZeroCopySource(item.Value)); err != nil {
+		return nil, fmt.Errorf("deserialize vesting %d error: %v", id, err)
+	}
+	return schedule, nil
+}
+
+func putVesting(native *native.NativeService, contract common.Address, schedule *VestingSchedule) {
+	item := &cstates.StorageItem{Value: common.SerializeToBytes(schedule)}
+	native.CacheDB.Put(genVestingKey(contract, schedule.ID), item.ToArray())
+}
+
+func genVestingIndexKey(contract common.Address) []byte {
+	return append(contract[:], VESTING_INDEX...)
+}
+
+func genVestingKey(contract common.Address, id uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteBytes(contract[:])
+	sink.WriteBytes([]byte(VESTING))
+	sink.WriteUint64(id)
+	return sink.Bytes()
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontid
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+const (
+	MAX_CREDENTIAL_HASH_SIZE = 64
+
+	credential_valid   byte = 0x01
+	credential_revoked byte = 0x02
+)
+
+// credential records the status of a verifiable credential committed by issuer
+type credential struct {
+	subject    []byte
+	status     byte
+	commitTime uint32
+	revokeTime uint32
+}
+
+func (this *credential) Serialization(sink *common.ZeroCopySink) {
+	sink.WriteVarBytes(this.subject)
+	sink.WriteByte(this.status)
+	sink.WriteUint32(this.commitTime)
+	sink.WriteUint32(this.revokeTime)
+}
+
+func (this *credential) Deserialization(source *common.ZeroCopySource) error {
+	subject, _, irregular, eof := source.NextVarBytes()
+	if irregular {
+		return common.ErrIrregularData
+	}
+	status, eof1 := source.NextByte()
+	commitTime, eof2 := source.NextUint32()
+	revokeTime, eof3 := source.NextUint32()
+	if eof || eof1 || eof2 || eof3 {
+		return io.ErrUnexpectedEOF
+	}
+	this.subject = subject
+	this.status = status
+	this.commitTime = commitTime
+	this.revokeTime = revokeTime
+	return nil
+}
+
+func credentialKey(encID, hash []byte) []byte {
+	key := make([]byte, 0, len(encID)+1+len(hash))
+	key = append(key, encID...)
+	key = append(key, FIELD_CREDENTIAL)
+	return append(key, hash...)
+}
+
+func getCredential(srvc *native.NativeService, encID, hash []byte) (*credential, error) {
+	item, err := utils.GetStorageItem(srvc, credentialKey(encID, hash))
+	if err != nil {
+		return nil, err
+	} else if item == nil {
+		return nil, nil
+	}
+	c := new(credential)
+	if err := c.Deserialization(common.NewZeroCopySource(item.Value)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func putCredential(srvc *native.NativeService, encID, hash []byte, c *credential) {
+	utils.PutBytes(srvc, credentialKey(encID, hash), common.SerializeToBytes(c))
+}
+
+// verifyIssuer checks the signature of issuer by its own key or by its controller
+func verifyIssuer(srvc *native.NativeService, encID []byte, source *common.ZeroCopySource, byController bool) error {
+	if byController {
+		return verifyControllerSignature(srvc, encID, source)
+	}
+	index, err := utils.DecodeVarUint(source)
+	if err != nil {
+		return fmt.Errorf("index error, %s", err)
+	}
+	return checkWitnessByIndex(srvc, encID, uint32(index))
+}
+
+func decodeCredentialArgs(source *common.ZeroCopySource) ([]byte, []byte, []byte, error) {
+	// arg0: issuer ID
+	issuer, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return nil, nil, nil, errors.New("argument 0 error")
+	}
+	encID, err := encodeID(issuer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// arg1: credential hash
+	hash, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return nil, nil, nil, errors.New("argument 1 error")
+	}
+	if len(hash) == 0 || len(hash) > MAX_CREDENTIAL_HASH_SIZE {
+		return nil, nil, nil, fmt.Errorf("invalid credential hash size %d", len(hash))
+	}
+	return issuer, encID, hash, nil
+}
+
+func commitCredential(srvc *native.NativeService) ([]byte, error) {
+	return credentialCommit(srvc, false)
+}
+
+func commitCredentialByController(srvc *native.NativeService) ([]byte, error) {
+	return credentialCommit(srvc, true)
+}
+
+func revokeCredential(srvc *native.NativeService) ([]byte, error) {
+	return credentialRevoke(srvc, false)
+}
+
+func revokeCredentialByController(srvc *native.NativeService) ([]byte, error) {
+	return credentialRevoke(srvc, true)
+}
+
+func credentialCommit(srvc *native.NativeService, byController bool) ([]byte, error) {
+	source := common.NewZeroCopySource(srvc.Input)
+	issuer, encID, hash, err := decodeCredentialArgs(source)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit credential error: %s", err)
+	}
+	// arg2: subject ID, may be empty
+	subject, err := utils.DecodeVarBytes(source)
+	if err != nil {
+		return utils.BYTE_FALSE, errors.New("commit credential error: argument 2 error")
+	}
+	if len(subject) != 0 && !account.VerifyID(string(subject)) {
+		return utils.BYTE_FALSE, errors.New("commit credential error: invalid subject ID")
+	}
+	if !isValid(srvc, encID) {
+		return utils.BYTE_FALSE, fmt.Errorf("commit credential error: %s is not registered or already revoked", string(issuer))
+	}
+	if err := verifyIssuer(srvc, encID, source, byController); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit credential error: verification failed, %s", err)
+	}
+
+	c, err := getCredential(srvc, encID, hash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("commit credential error: %s", err)
+	} else if c != nil {
+		return utils.BYTE_FALSE, errors.New("commit credential error: credential already committed")
+	}
+	putCredential(srvc, encID, hash, &credential{
+		subject:    subject,
+		status:     credential_valid,
+		commitTime: srvc.Time,
+	})
+
+	triggerCredentialEvent(srvc, "commit", issuer, hash, subject)
+	return utils.BYTE_TRUE, nil
+}
+
+func credentialRevoke(srvc *native.NativeService, byController bool) ([]byte, error) {
+	source := common.NewZeroCopySource(srvc.Input)
+	issuer, encID, hash, err := decodeCredentialArgs(source)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke credential error: %s", err)
+	}
+	if !isValid(srvc, encID) {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke credential error: %s is not registered or already revoked", string(issuer))
+	}
+	if err := verifyIssuer(srvc, encID, source, byController); err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke credential error: verification failed, %s", err)
+	}
+
+	c, err := getCredential(srvc, encID, hash)
+	if err != nil {
+		return utils.BYTE_FALSE, fmt.Errorf("revoke credential error: %s", err)
+	} else if c == nil {
+		return utils.BYTE_FALSE, errors.New("revoke credential error: credential not exist")
+	} else if c.status == credential_revoked {
+		return utils.BYTE_FALSE, errors.New("revoke credential error: credential already revoked")
+	}
+	c.status = credential_revoked
+	c.revokeTime = srvc.Time
+	putCredential(srvc, encID, hash, c)
+
+	triggerCredentialEvent(srvc, "revoke", issuer, hash, c.subject)
+	return utils.BYTE_TRUE, nil
+}
+
+// GetCredentialStatus returns "valid", "revoked" or "not exist". Credentials of a
+// revoked issuer are treated as revoked
+func GetCredentialStatus(srvc *native.NativeService) ([]byte, error) {
+	source := common.NewZeroCopySource(srvc.Input)
+	_, encID, hash, err := decodeCredentialArgs(source)
+	if err != nil {
+		return nil, fmt.Errorf("get credential status failed: %s", err)
+	}
+	c, err := getCredential(srvc, encID, hash)
+	if err != nil {
+		return nil, fmt.Errorf("get credential status failed: %s", err)
+	} else if c == nil {
+		return []byte("not exist"), nil
+	}
+	if c.status == credential_revoked || checkIDState(srvc, encID) == flag_revoke {
+		return []byte("revoked"), nil
+	}
+	return []byte("valid"), nil
+}
+
+func triggerCredentialEvent(srvc *native.NativeService, op string, issuer, hash, subject []byte) {
+	st := []interface{}{"Credential", op, string(issuer), hex.EncodeToString(hash), string(subject)}
+	newEvent(srvc, st)
+}
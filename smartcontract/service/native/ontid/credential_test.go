@@ -0,0 +1,180 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package ontid
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+)
+
+func TestCredential(t *testing.T) {
+	testcase(t, CaseCredential)
+}
+
+// Test case: commit and revoke credentials by issuer and by its controller
+func CaseCredential(t *testing.T, n *native.NativeService) {
+	a0 := account.NewAccount("")
+	a1 := account.NewAccount("")
+	issuer, _ := account.GenerateID()
+	ctrl, _ := account.GenerateID()
+	controlled, _ := account.GenerateID()
+	subject, _ := account.GenerateID()
+	hash := []byte("credential hash 0")
+
+	// 1. commit by unregistered issuer, should fail
+	if err := commitCred(n, issuer, hash, subject, 1, a0.Address, false); err == nil {
+		t.Error("credential committed by unregistered issuer")
+	}
+
+	if err := regID(n, issuer, a0); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2. commit without valid signature, should fail
+	if err := commitCred(n, issuer, hash, subject, 1, a1.Address, false); err == nil {
+		t.Error("credential committed without valid signature")
+	}
+
+	// 3. commit with invalid subject, should fail
+	if err := commitCred(n, issuer, hash, "did:ont::123", 1, a0.Address, false); err == nil {
+		t.Error("credential committed with invalid subject")
+	}
+
+	// 4. commit and check status
+	if status := credStatus(t, n, issuer, hash); status != "not exist" {
+		t.Errorf("status of uncommitted credential is %s", status)
+	}
+	if err := commitCred(n, issuer, hash, subject, 1, a0.Address, false); err != nil {
+		t.Fatal(err)
+	}
+	if status := credStatus(t, n, issuer, hash); status != "valid" {
+		t.Errorf("status of committed credential is %s", status)
+	}
+
+	// 5. commit again, should fail
+	if err := commitCred(n, issuer, hash, subject, 1, a0.Address, false); err == nil {
+		t.Error("credential committed twice")
+	}
+
+	// 6. revoke without valid signature, should fail
+	if err := revokeCred(n, issuer, hash, 1, a1.Address, false); err == nil {
+		t.Error("credential revoked without valid signature")
+	}
+
+	// 7. revoke
+	if err := revokeCred(n, issuer, hash, 1, a0.Address, false); err != nil {
+		t.Fatal(err)
+	}
+	if status := credStatus(t, n, issuer, hash); status != "revoked" {
+		t.Errorf("status of revoked credential is %s", status)
+	}
+	if err := revokeCred(n, issuer, hash, 1, a0.Address, false); err == nil {
+		t.Error("credential revoked twice")
+	}
+
+	// 8. commit and revoke by controller
+	if err := regID(n, ctrl, a1); err != nil {
+		t.Fatal(err)
+	}
+	if err := regControlledID(n, controlled, ctrl, 1, a1.Address); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitCred(n, controlled, hash, "", 1, a0.Address, true); err == nil {
+		t.Error("credential committed without valid controller signature")
+	}
+	if err := commitCred(n, controlled, hash, "", 1, a1.Address, true); err != nil {
+		t.Fatal(err)
+	}
+	if status := credStatus(t, n, controlled, hash); status != "valid" {
+		t.Errorf("status of committed credential is %s", status)
+	}
+	if err := revokeCred(n, controlled, hash, 1, a1.Address, true); err != nil {
+		t.Fatal(err)
+	}
+	if status := credStatus(t, n, controlled, hash); status != "revoked" {
+		t.Errorf("status of revoked credential is %s", status)
+	}
+
+	// 9. credentials of revoked issuer are revoked
+	hash1 := []byte("credential hash 1")
+	if err := commitCred(n, issuer, hash1, subject, 1, a0.Address, false); err != nil {
+		t.Fatal(err)
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes([]byte(issuer))
+	utils.EncodeVarUint(sink, 1)
+	n.Input = sink.Bytes()
+	n.Tx.SignedAddr = []common.Address{a0.Address}
+	if _, err := revokeID(n); err != nil {
+		t.Fatal(err)
+	}
+	if status := credStatus(t, n, issuer, hash1); status != "revoked" {
+		t.Errorf("status of credential of revoked issuer is %s", status)
+	}
+}
+
+func commitCred(n *native.NativeService, issuer string, hash []byte, subject string, index uint64,
+	addr common.Address, byController bool) error {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes([]byte(issuer))
+	sink.WriteVarBytes(hash)
+	sink.WriteVarBytes([]byte(subject))
+	utils.EncodeVarUint(sink, index)
+	n.Input = sink.Bytes()
+	n.Tx.SignedAddr = []common.Address{addr}
+	var err error
+	if byController {
+		_, err = commitCredentialByController(n)
+	} else {
+		_, err = commitCredential(n)
+	}
+	return err
+}
+
+func revokeCred(n *native.NativeService, issuer string, hash []byte, index uint64, addr common.Address,
+	byController bool) error {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes([]byte(issuer))
+	sink.WriteVarBytes(hash)
+	utils.EncodeVarUint(sink, index)
+	n.Input = sink.Bytes()
+	n.Tx.SignedAddr = []common.Address{addr}
+	var err error
+	if byController {
+		_, err = revokeCredentialByController(n)
+	} else {
+		_, err = revokeCredential(n)
+	}
+	return err
+}
+
+func credStatus(t *testing.T, n *native.NativeService, issuer string, hash []byte) string {
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteVarBytes([]byte(issuer))
+	sink.WriteVarBytes(hash)
+	n.Input = sink.Bytes()
+	res, err := GetCredentialStatus(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(res)
+}
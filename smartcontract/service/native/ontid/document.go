@@ -0,0 +1,270 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontid
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/common"
+)
+
+const (
+	DID_CONTEXT = "https://www.w3.org/ns/did/v1"
+
+	KEY_TYPE_ECDSA   = "EcdsaSecp256r1VerificationKey2019"
+	KEY_TYPE_SM2     = "SM2VerificationKey2019"
+	KEY_TYPE_ED25519 = "Ed25519VerificationKey2018"
+)
+
+// DIDDocument is the W3C DID Core representation of an ONT ID
+type DIDDocument struct {
+	Context            []string              `json:"@context"`
+	Id                 string                `json:"id"`
+	Controller         []string              `json:"controller,omitempty"`
+	ControllerGroup    *DocumentGroup        `json:"controllerGroup,omitempty"`
+	VerificationMethod []*VerificationMethod `json:"verificationMethod"`
+	Authentication     []string              `json:"authentication"`
+	RevokedKeys        []string              `json:"revokedVerificationMethod,omitempty"`
+	Recovery           *DocumentGroup        `json:"recovery,omitempty"`
+	RecoveryAddress    string                `json:"recoveryAddress,omitempty"`
+	Attributes         []*DocumentAttribute  `json:"attribute,omitempty"`
+}
+
+// VerificationMethod describes a public key which is in use
+type VerificationMethod struct {
+	Id           string `json:"id"`
+	Type         string `json:"type"`
+	Controller   string `json:"controller"`
+	PublicKeyHex string `json:"publicKeyHex"`
+}
+
+// DocumentAttribute is an attribute of ONT ID rendered as text
+type DocumentAttribute struct {
+	Key   string `json:"key"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// DocumentGroup is the readable form of Group, whose members are either
+// ONT IDs or nested groups
+type DocumentGroup struct {
+	Members   []interface{} `json:"members"`
+	Threshold uint          `json:"threshold"`
+}
+
+// KeyId returns the DID URL of the public key with the given index
+func KeyId(id string, index uint32) string {
+	return fmt.Sprintf("%s#keys-%d", id, index)
+}
+
+// ParseDDO renders the result of getDDO as a DID document. The public keys
+// returned by getDDO are only those in use, revoked keys should be recorded
+// with AddRevokedKey.
+func ParseDDO(id string, ddo []byte) (*DIDDocument, error) {
+	doc := &DIDDocument{
+		Context:            []string{DID_CONTEXT},
+		Id:                 id,
+		VerificationMethod: make([]*VerificationMethod, 0),
+		Authentication:     make([]string, 0),
+	}
+	source := common.NewZeroCopySource(ddo)
+	keys, err := nextDDOField(source)
+	if err != nil {
+		return nil, fmt.Errorf("parse public keys error: %s", err)
+	}
+	attrs, err := nextDDOField(source)
+	if err != nil {
+		return nil, fmt.Errorf("parse attributes error: %s", err)
+	}
+	oldRec, err := nextDDOField(source)
+	if err != nil {
+		return nil, fmt.Errorf("parse recovery error: %s", err)
+	}
+	controller, err := nextDDOField(source)
+	if err != nil {
+		return nil, fmt.Errorf("parse controller error: %s", err)
+	}
+	// the new recovery is absent in the DDO of earlier versions
+	var rec []byte
+	if source.Len() > 0 {
+		rec, err = nextDDOField(source)
+		if err != nil {
+			return nil, fmt.Errorf("parse recovery error: %s", err)
+		}
+	}
+
+	if err = doc.parseKeys(keys); err != nil {
+		return nil, err
+	}
+	if err = doc.parseAttributes(attrs); err != nil {
+		return nil, err
+	}
+	if len(controller) > 0 {
+		if strings.HasPrefix(string(controller), "did:") {
+			doc.Controller = []string{string(controller)}
+		} else {
+			doc.ControllerGroup, err = parseDocumentGroup(controller)
+			if err != nil {
+				return nil, fmt.Errorf("parse controller error: %s", err)
+			}
+			doc.Controller = doc.ControllerGroup.ids()
+		}
+	}
+	if len(oldRec) > 0 {
+		addr, err := common.AddressParseFromBytes(oldRec)
+		if err != nil {
+			return nil, fmt.Errorf("parse recovery error: %s", err)
+		}
+		doc.RecoveryAddress = addr.ToBase58()
+	}
+	if len(rec) > 0 {
+		doc.Recovery, err = parseDocumentGroup(rec)
+		if err != nil {
+			return nil, fmt.Errorf("parse recovery error: %s", err)
+		}
+	}
+	return doc, nil
+}
+
+// AddRevokedKey records the public key with the given index as revoked
+func (doc *DIDDocument) AddRevokedKey(index uint32) {
+	doc.RevokedKeys = append(doc.RevokedKeys, KeyId(doc.Id, index))
+}
+
+func (doc *DIDDocument) parseKeys(data []byte) error {
+	source := common.NewZeroCopySource(data)
+	for source.Len() > 0 {
+		index, eof := source.NextUint32()
+		if eof {
+			return fmt.Errorf("parse public keys error: key index missing")
+		}
+		pk, _, irregular, eof := source.NextVarBytes()
+		if irregular || eof {
+			return fmt.Errorf("parse public keys error: key %d missing", index)
+		}
+		keyType, err := verificationKeyType(pk)
+		if err != nil {
+			return fmt.Errorf("parse public keys error: key %d, %s", index, err)
+		}
+		keyId := KeyId(doc.Id, index)
+		doc.VerificationMethod = append(doc.VerificationMethod, &VerificationMethod{
+			Id:           keyId,
+			Type:         keyType,
+			Controller:   doc.Id,
+			PublicKeyHex: hex.EncodeToString(pk),
+		})
+		doc.Authentication = append(doc.Authentication, keyId)
+	}
+	return nil
+}
+
+func (doc *DIDDocument) parseAttributes(data []byte) error {
+	source := common.NewZeroCopySource(data)
+	for source.Len() > 0 {
+		var attr attribute
+		if err := attr.Deserialization(source); err != nil {
+			return fmt.Errorf("parse attributes error: %s", err)
+		}
+		doc.Attributes = append(doc.Attributes, &DocumentAttribute{
+			Key:   string(attr.key),
+			Type:  string(attr.valueType),
+			Value: string(attr.value),
+		})
+	}
+	return nil
+}
+
+func verificationKeyType(data []byte) (string, error) {
+	pk, err := keypair.DeserializePublicKey(data)
+	if err != nil {
+		return "", err
+	}
+	switch keypair.GetKeyType(pk) {
+	case keypair.PK_ECDSA:
+		return KEY_TYPE_ECDSA, nil
+	case keypair.PK_SM2:
+		return KEY_TYPE_SM2, nil
+	case keypair.PK_EDDSA:
+		return KEY_TYPE_ED25519, nil
+	default:
+		return "", fmt.Errorf("unsupported key type")
+	}
+}
+
+func nextDDOField(source *common.ZeroCopySource) ([]byte, error) {
+	data, _, irregular, eof := source.NextVarBytes()
+	if irregular {
+		return nil, common.ErrIrregularData
+	}
+	if eof {
+		return nil, fmt.Errorf("unexpected end of DDO")
+	}
+	return data, nil
+}
+
+// parseDocumentGroup decodes the json output of Group, in which the ONT IDs
+// are encoded in base64
+func parseDocumentGroup(data []byte) (*DocumentGroup, error) {
+	var raw struct {
+		Members   []json.RawMessage `json:"members"`
+		Threshold uint              `json:"threshold"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	g := &DocumentGroup{
+		Members:   make([]interface{}, 0, len(raw.Members)),
+		Threshold: raw.Threshold,
+	}
+	for _, m := range raw.Members {
+		var encoded string
+		if err := json.Unmarshal(m, &encoded); err == nil {
+			id, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, err
+			}
+			g.Members = append(g.Members, string(id))
+			continue
+		}
+		sub, err := parseDocumentGroup(m)
+		if err != nil {
+			return nil, err
+		}
+		g.Members = append(g.Members, sub)
+	}
+	return g, nil
+}
+
+func (g *DocumentGroup) ids() []string {
+	res := make([]string, 0)
+	for _, m := range g.Members {
+		switch t := m.(type) {
+		case string:
+			res = append(res, t)
+		case *DocumentGroup:
+			res = append(res, t.ids()...)
+		}
+	}
+	return res
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package ontid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ontio/ontology-crypto/keypair"
+	"github.com/ontio/ontology/account"
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native"
+)
+
+func TestDIDDocument(t *testing.T) {
+	testcase(t, CaseDIDDocument)
+}
+
+// Test case: render the DDO of an ID with revoked key, attribute and recovery
+func CaseDIDDocument(t *testing.T, n *native.NativeService) {
+	a0 := account.NewAccount("")
+	a1 := account.NewAccount("")
+	a2 := account.NewAccount("")
+	id, _ := account.GenerateID()
+	rec, _ := account.GenerateID()
+	if err := regID(n, id, a0); err != nil {
+		t.Fatal(err)
+	}
+	if err := regID(n, rec, a2); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1. add key #2 and #3, then remove key #2
+	for _, a := range []*account.Account{a1, a2} {
+		sink := common.NewZeroCopySink(nil)
+		sink.WriteString(id)
+		sink.WriteVarBytes(keypair.SerializePublicKey(a.PubKey()))
+		sink.WriteVarBytes(keypair.SerializePublicKey(a0.PubKey()))
+		n.Input = sink.Bytes()
+		n.Tx.SignedAddr = []common.Address{a0.Address}
+		if _, err := addKey(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sink := common.NewZeroCopySink(nil)
+	sink.WriteString(id)
+	sink.WriteVarBytes(keypair.SerializePublicKey(a1.PubKey()))
+	sink.WriteVarBytes(keypair.SerializePublicKey(a0.PubKey()))
+	n.Input = sink.Bytes()
+	n.Tx.SignedAddr = []common.Address{a0.Address}
+	if _, err := removeKey(n); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2. add attribute and recovery
+	encID, _ := encodeID([]byte(id))
+	attr := attribute{
+		key:       []byte("name"),
+		valueType: []byte("string"),
+		value:     []byte("alice"),
+	}
+	if err := insertOrUpdateAttr(n, encID, &attr); err != nil {
+		t.Fatal(err)
+	}
+	g := &Group{Members: []interface{}{[]byte(rec)}, Threshold: 1}
+	if err := setRec(n, id, g, a0.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	// 3. render the DDO
+	sink.Reset()
+	sink.WriteString(id)
+	n.Input = sink.Bytes()
+	ddo, err := GetDDO(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ParseDDO(id, ddo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.AddRevokedKey(2)
+
+	if len(doc.VerificationMethod) != 2 {
+		t.Fatalf("%d verification methods, expect 2", len(doc.VerificationMethod))
+	}
+	for i, a := range []*account.Account{a0, a2} {
+		m := doc.VerificationMethod[i]
+		pk := hex.EncodeToString(keypair.SerializePublicKey(a.PubKey()))
+		if m.Type != KEY_TYPE_ECDSA || m.Controller != id || m.PublicKeyHex != pk {
+			t.Errorf("invalid verification method %v", m)
+		}
+		if doc.Authentication[i] != m.Id {
+			t.Errorf("authentication %s, expect %s", doc.Authentication[i], m.Id)
+		}
+	}
+	if doc.VerificationMethod[1].Id != KeyId(id, 3) {
+		t.Errorf("key id %s, expect %s", doc.VerificationMethod[1].Id, KeyId(id, 3))
+	}
+	if len(doc.RevokedKeys) != 1 || doc.RevokedKeys[0] != KeyId(id, 2) {
+		t.Errorf("invalid revoked keys %v", doc.RevokedKeys)
+	}
+	if len(doc.Attributes) != 1 || *doc.Attributes[0] != (DocumentAttribute{"name", "string", "alice"}) {
+		t.Errorf("invalid attributes %v", doc.Attributes)
+	}
+	if doc.Recovery == nil || len(doc.Recovery.Members) != 1 || doc.Recovery.Members[0] != rec {
+		t.Errorf("invalid recovery %v", doc.Recovery)
+	}
+	if len(doc.Controller) != 0 {
+		t.Errorf("unexpected controller %v", doc.Controller)
+	}
+	if _, err := json.Marshal(doc); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseDocumentGroup(t *testing.T) {
+	id0, _ := account.GenerateID()
+	id1, _ := account.GenerateID()
+	id2, _ := account.GenerateID()
+	g := &Group{
+		Members: []interface{}{
+			[]byte(id0),
+			&Group{Members: []interface{}{[]byte(id1), []byte(id2)}, Threshold: 2},
+		},
+		Threshold: 1,
+	}
+	res, err := parseDocumentGroup(g.ToJson())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Threshold != 1 || len(res.Members) != 2 || res.Members[0] != id0 {
+		t.Fatalf("invalid group %v", res)
+	}
+	sub, ok := res.Members[1].(*DocumentGroup)
+	if !ok || sub.Threshold != 2 {
+		t.Fatalf("invalid sub group %v", res.Members[1])
+	}
+	ids := res.ids()
+	if len(ids) != 3 || ids[0] != id0 || ids[1] != id1 || ids[2] != id2 {
+		t.Errorf("invalid group members %v", ids)
+	}
+}
@@ -49,9 +49,14 @@ func RegisterIDContract(srvc *native.NativeService) {
 	srvc.Register("removeAttributeByController", removeAttributeByController)
 	srvc.Register("verifySignature", verifySignature)
 	srvc.Register("verifyController", verifyController)
+	srvc.Register("commitCredential", commitCredential)
+	srvc.Register("commitCredentialByController", commitCredentialByController)
+	srvc.Register("revokeCredential", revokeCredential)
+	srvc.Register("revokeCredentialByController", revokeCredentialByController)
 	srvc.Register("getPublicKeys", GetPublicKeys)
 	srvc.Register("getKeyState", GetKeyState)
 	srvc.Register("getAttributes", GetAttributes)
 	srvc.Register("getDDO", GetDDO)
+	srvc.Register("getCredentialStatus", GetCredentialStatus)
 	return
 }
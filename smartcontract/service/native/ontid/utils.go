@@ -57,6 +57,7 @@ const (
 	FIELD_ATTR       byte = 2
 	FIELD_RECOVERY   byte = 3
 	FIELD_CONTROLLER byte = 4
+	FIELD_CREDENTIAL byte = 5
 )
 
 func encodeID(id []byte) ([]byte, error) {
@@ -18,6 +18,9 @@
 package testsuite
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/ontio/ontology/core/store/common"
 	"github.com/ontio/ontology/core/store/overlaydb"
 )
@@ -43,6 +46,57 @@ func (self *MockDB) BatchDelete(key []byte) {
 	delete(self.db, string(key))
 }
 
+func (self *MockDB) NewIterator(prefix []byte) common.StoreIterator {
+	iter := &mockIterator{index: -1}
+	for key := range self.db {
+		if strings.HasPrefix(key, string(prefix)) {
+			iter.keys = append(iter.keys, key)
+		}
+	}
+	sort.Strings(iter.keys)
+	iter.values = make([]string, len(iter.keys))
+	for i, key := range iter.keys {
+		iter.values[i] = self.db[key]
+	}
+	return iter
+}
+
+type mockIterator struct {
+	keys   []string
+	values []string
+	index  int
+}
+
+func (self *mockIterator) First() bool {
+	self.index = 0
+	return self.index < len(self.keys)
+}
+
+func (self *mockIterator) Next() bool {
+	self.index += 1
+	return self.index < len(self.keys)
+}
+
+func (self *mockIterator) Key() []byte {
+	if self.index < 0 || self.index >= len(self.keys) {
+		return nil
+	}
+	return []byte(self.keys[self.index])
+}
+
+func (self *mockIterator) Value() []byte {
+	if self.index < 0 || self.index >= len(self.keys) {
+		return nil
+	}
+	return []byte(self.values[self.index])
+}
+
+func (self *mockIterator) Release() {}
+
+func (self *mockIterator) Error() error {
+	return nil
+}
+
 func NewOverlayDB() *overlaydb.OverlayDB {
 	return overlaydb.NewOverlayDB(&MockDB{nil, make(map[string]string)})
 }
@@ -19,6 +19,7 @@ package testsuite
 
 import (
 	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/common/constants"
 	"github.com/ontio/ontology/smartcontract/service/native"
 	_ "github.com/ontio/ontology/smartcontract/service/native/init"
 	"github.com/ontio/ontology/smartcontract/service/native/ont"
@@ -76,3 +77,147 @@ func TestTransfer(t *testing.T) {
 		return nil, nil
 	})
 }
+
+func ongAllowance(native *native.NativeService, addr common.Address) uint64 {
+	key := ont.GenApproveKey(utils.OngContractAddress, utils.OntContractAddress, addr)
+	value, _ := utils.GetStorageUInt64(native, key)
+	return value
+}
+
+func vestingInput(id uint64) []byte {
+	sink := common.NewZeroCopySink(nil)
+	utils.EncodeVarUint(sink, id)
+	return sink.Bytes()
+}
+
+func TestOntVesting(t *testing.T) {
+	InvokeNativeContract(t, utils.OntContractAddress, func(native *native.NativeService) ([]byte, error) {
+		a := RandomAddress()
+		b := RandomAddress()
+		setOntBalance(native.CacheDB, a, 1000)
+		start := constants.GENESIS_BLOCK_TIMESTAMP + 1000
+		native.Time = start
+
+		param := &ont.VestingParam{Creator: a, Beneficiary: b, Amount: 1000, Start: uint64(start),
+			Cliff: uint64(start + 100), End: uint64(start + 1000), Revocable: true}
+		_, err := invokeWithSigner(native, b, common.SerializeToBytes(param), ont.OntCreateVesting)
+		assert.NotNil(t, err)
+		buf, err := invokeWithSigner(native, a, common.SerializeToBytes(param), ont.OntCreateVesting)
+		assert.Nil(t, err)
+		id := common.BigIntFromNeoBytes(buf).Uint64()
+		assert.Equal(t, uint64(1), id)
+		assert.Equal(t, 0, ontBalanceOf(native, a))
+		//ong of the escrowed ont is granted to creator until escrowed
+		assert.Equal(t, utils.CalcUnbindOng(1000, 0, 1000), ongAllowance(native, a))
+
+		//nothing vested before cliff
+		native.Time = start + 50
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.ReleaseVesting)
+		assert.NotNil(t, err)
+
+		native.Time = start + 500
+		_, err = invokeWithSigner(native, a, vestingInput(id), ont.ReleaseVesting)
+		assert.NotNil(t, err)
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.ReleaseVesting)
+		assert.Nil(t, err)
+		assert.Equal(t, 500, ontBalanceOf(native, b))
+		//ong of the locked ont is granted to beneficiary
+		locked := utils.CalcUnbindOng(1000, 1000, 1500)
+		assert.Equal(t, locked, ongAllowance(native, b))
+		buf, err = invokeWithSigner(native, b, vestingInput(id), ont.GetVesting)
+		assert.Nil(t, err)
+		schedule := new(ont.VestingSchedule)
+		assert.Nil(t, schedule.Deserialization(common.NewZeroCopySource(buf)))
+		assert.Equal(t, uint64(500), schedule.Released)
+		assert.Equal(t, uint32(1500), schedule.UnboundOffset)
+
+		//revoke settles vested amount to beneficiary and returns the rest to creator
+		native.Time = start + 700
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.RevokeVesting)
+		assert.NotNil(t, err)
+		_, err = invokeWithSigner(native, a, vestingInput(id), ont.RevokeVesting)
+		assert.Nil(t, err)
+		assert.Equal(t, 700, ontBalanceOf(native, b))
+		assert.Equal(t, 300, ontBalanceOf(native, a))
+		locked += utils.CalcUnbindOng(500, 1500, 1700)
+		owned := utils.CalcUnbindOng(500, 1500, 1700)
+		assert.Equal(t, locked+owned, ongAllowance(native, b))
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.GetVesting)
+		assert.NotNil(t, err)
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.ReleaseVesting)
+		assert.NotNil(t, err)
+
+		//irrevocable vesting is fully released at end
+		param = &ont.VestingParam{Creator: a, Beneficiary: b, Amount: 300, Start: uint64(native.Time),
+			Cliff: uint64(native.Time), End: uint64(native.Time + 100)}
+		buf, err = invokeWithSigner(native, a, common.SerializeToBytes(param), ont.OntCreateVesting)
+		assert.Nil(t, err)
+		id = common.BigIntFromNeoBytes(buf).Uint64()
+		assert.Equal(t, uint64(2), id)
+		_, err = invokeWithSigner(native, a, vestingInput(id), ont.RevokeVesting)
+		assert.NotNil(t, err)
+		native.Time += 200
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.ReleaseVesting)
+		assert.Nil(t, err)
+		assert.Equal(t, 1000, ontBalanceOf(native, b))
+		assert.Equal(t, 0, ontBalanceOf(native, a))
+		_, err = invokeWithSigner(native, b, vestingInput(id), ont.GetVesting)
+		assert.NotNil(t, err)
+		return nil, nil
+	})
+}
+
+func ontTransferMulti(native *native.NativeService, signer, from common.Address, recipients ...ont.Recipient) error {
+	transfers := &ont.TransferMulti{Batches: []ont.TransferBatch{{From: from, Recipients: recipients}}}
+	_, err := invokeWithSigner(native, signer, common.SerializeToBytes(transfers), ont.OntTransferMulti)
+	return err
+}
+
+func TestOntTransferMulti(t *testing.T) {
+	InvokeNativeContract(t, utils.OntContractAddress, func(native *native.NativeService) ([]byte, error) {
+		a := RandomAddress()
+		b := RandomAddress()
+		c := RandomAddress()
+		d := RandomAddress()
+		setOntBalance(native.CacheDB, a, 1000)
+		setOntBalance(native.CacheDB, b, 100)
+		setOntBalance(native.CacheDB, d, 500)
+		native.Time = constants.GENESIS_BLOCK_TIMESTAMP + 1000
+
+		//missing witness of from
+		err := ontTransferMulti(native, b, a, ont.Recipient{To: b, Value: 10})
+		assert.NotNil(t, err)
+		assert.Equal(t, 1000, ontBalanceOf(native, a))
+
+		//duplicate recipient is credited twice but granted ong once
+		err = ontTransferMulti(native, a, a, ont.Recipient{To: b, Value: 10}, ont.Recipient{To: c, Value: 200},
+			ont.Recipient{To: b, Value: 20})
+		assert.Nil(t, err)
+		assert.Equal(t, 770, ontBalanceOf(native, a))
+		assert.Equal(t, 130, ontBalanceOf(native, b))
+		assert.Equal(t, 200, ontBalanceOf(native, c))
+		assert.Equal(t, utils.CalcUnbindOng(1000, 0, 1000), ongAllowance(native, a))
+		assert.Equal(t, utils.CalcUnbindOng(100, 0, 1000), ongAllowance(native, b))
+		assert.Equal(t, uint64(0), ongAllowance(native, c))
+
+		//recipient equal to from keeps the balance and is granted ong once
+		err = ontTransferMulti(native, d, d, ont.Recipient{To: d, Value: 50})
+		assert.Nil(t, err)
+		assert.Equal(t, 500, ontBalanceOf(native, d))
+		assert.Equal(t, utils.CalcUnbindOng(500, 0, 1000), ongAllowance(native, d))
+
+		//total over supply
+		err = ontTransferMulti(native, a, a, ont.Recipient{To: b, Value: constants.ONT_TOTAL_SUPPLY + 1})
+		assert.NotNil(t, err)
+		err = ontTransferMulti(native, a, a, ont.Recipient{To: b, Value: constants.ONT_TOTAL_SUPPLY},
+			ont.Recipient{To: c, Value: 1})
+		assert.NotNil(t, err)
+		//debit is not enough
+		err = ontTransferMulti(native, a, a, ont.Recipient{To: b, Value: 700}, ont.Recipient{To: c, Value: 71})
+		assert.NotNil(t, err)
+		assert.Equal(t, 770, ontBalanceOf(native, a))
+		assert.Equal(t, 130, ontBalanceOf(native, b))
+		assert.Equal(t, 200, ontBalanceOf(native, c))
+		return nil, nil
+	})
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright (C) 2018 The ontology Authors
+ * This file is part of The ontology library.
+ *
+ * The ontology is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Lesser General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * The ontology is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Lesser General Public License for more details.
+ *
+ * You should have received a copy of the GNU Lesser General Public License
+ * along with The ontology.  If not, see <http://www.gnu.org/licenses/>.
+ */
+package testsuite
+
+import (
+	"testing"
+
+	"github.com/ontio/ontology/common"
+	"github.com/ontio/ontology/smartcontract/service/native"
+	_ "github.com/ontio/ontology/smartcontract/service/native/init"
+	"github.com/ontio/ontology/smartcontract/service/native/oep4"
+	"github.com/ontio/ontology/smartcontract/service/native/oep5"
+	"github.com/ontio/ontology/smartcontract/service/native/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func invokeWithSigner(native *native.NativeService, signer common.Address, input []byte,
+	method native.Handler) ([]byte, error) {
+	native.Tx.SignedAddr = []common.Address{signer}
+	native.Input = input
+	return method(native)
+}
+
+func oep4BalanceOf(native *native.NativeService, id uint64, addr common.Address) uint64 {
+	sink := common.NewZeroCopySink(nil)
+	utils.EncodeVarUint(sink, id)
+	utils.EncodeAddress(sink, addr)
+	native.Input = sink.Bytes()
+	buf, _ := oep4.BalanceOf(native)
+	return common.BigIntFromNeoBytes(buf).Uint64()
+}
+
+func TestOep4Factory(t *testing.T) {
+	InvokeNativeContract(t, utils.Oep4FactoryContractAddress, func(native *native.NativeService) ([]byte, error) {
+		a := RandomAddress()
+		b := RandomAddress()
+		c := RandomAddress()
+
+		deploy := &oep4.DeployParam{Owner: a, Name: "Token", Symbol: "TK", Decimals: 8, TotalSupply: 10000}
+		_, err := invokeWithSigner(native, b, common.SerializeToBytes(deploy), oep4.Deploy)
+		assert.NotNil(t, err)
+		buf, err := invokeWithSigner(native, a, common.SerializeToBytes(deploy), oep4.Deploy)
+		assert.Nil(t, err)
+		id := common.BigIntFromNeoBytes(buf).Uint64()
+		assert.Equal(t, uint64(1), id)
+		assert.Equal(t, uint64(10000), oep4BalanceOf(native, id, a))
+
+		sink := common.NewZeroCopySink(nil)
+		utils.EncodeVarUint(sink, id)
+		native.Input = sink.Bytes()
+		buf, err = oep4.Symbol(native)
+		assert.Nil(t, err)
+		assert.Equal(t, "TK", string(buf))
+
+		transfer := &oep4.TransferParam{ID: id, From: a, To: b, Value: 100}
+		_, err = invokeWithSigner(native, b, common.SerializeToBytes(transfer), oep4.Transfer)
+		assert.NotNil(t, err)
+		_, err = invokeWithSigner(native, a, common.SerializeToBytes(transfer), oep4.Transfer)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(9900), oep4BalanceOf(native, id, a))
+		assert.Equal(t, uint64(100), oep4BalanceOf(native, id, b))
+
+		approve := &oep4.TransferParam{ID: id, From: b, To: c, Value: 60}
+		_, err = invokeWithSigner(native, b, common.SerializeToBytes(approve), oep4.Approve)
+		assert.Nil(t, err)
+		transferFrom := &oep4.TransferFromParam{ID: id, Sender: c, From: b, To: c, Value: 70}
+		_, err = invokeWithSigner(native, c, common.SerializeToBytes(transferFrom), oep4.TransferFrom)
+		assert.NotNil(t, err)
+		transferFrom.Value = 60
+		_, err = invokeWithSigner(native, c, common.SerializeToBytes(transferFrom), oep4.TransferFrom)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(40), oep4BalanceOf(native, id, b))
+		assert.Equal(t, uint64(60), oep4BalanceOf(native, id, c))
+
+		//balances of another token are separated
+		deploy.TotalSupply = 5
+		buf, err = invokeWithSigner(native, a, common.SerializeToBytes(deploy), oep4.Deploy)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(2), common.BigIntFromNeoBytes(buf).Uint64())
+		assert.Equal(t, uint64(5), oep4BalanceOf(native, 2, a))
+		assert.Equal(t, uint64(0), oep4BalanceOf(native, 2, b))
+		return nil, nil
+	})
+}
+
+func TestOep5Factory(t *testing.T) {
+	InvokeNativeContract(t, utils.Oep5FactoryContractAddress, func(native *native.NativeService) ([]byte, error) {
+		a := RandomAddress()
+		b := RandomAddress()
+		c := RandomAddress()
+
+		deploy := &oep5.DeployParam{Owner: a, Name: "Collection", Symbol: "NFT"}
+		buf, err := invokeWithSigner(native, a, common.SerializeToBytes(deploy), oep5.Deploy)
+		assert.Nil(t, err)
+		id := common.BigIntFromNeoBytes(buf).Uint64()
+
+		mint := &oep5.MintParam{ID: id, To: b, URI: "ipfs://token"}
+		_, err = invokeWithSigner(native, b, common.SerializeToBytes(mint), oep5.Mint)
+		assert.NotNil(t, err)
+		buf, err = invokeWithSigner(native, a, common.SerializeToBytes(mint), oep5.Mint)
+		assert.Nil(t, err)
+		tokenID := common.BigIntFromNeoBytes(buf).Uint64()
+		assert.Equal(t, uint64(1), tokenID)
+
+		sink := common.NewZeroCopySink(nil)
+		utils.EncodeVarUint(sink, id)
+		utils.EncodeVarUint(sink, tokenID)
+		token := sink.Bytes()
+		native.Input = token
+		buf, err = oep5.OwnerOf(native)
+		assert.Nil(t, err)
+		assert.Equal(t, b[:], buf)
+		native.Input = token
+		buf, err = oep5.TokenURI(native)
+		assert.Nil(t, err)
+		assert.Equal(t, "ipfs://token", string(buf))
+
+		transfer := &oep5.TransferParam{ID: id, From: a, To: c, TokenID: tokenID}
+		_, err = invokeWithSigner(native, a, common.SerializeToBytes(transfer), oep5.Transfer)
+		assert.NotNil(t, err)
+
+		approve := &oep5.TransferParam{ID: id, From: b, To: c, TokenID: tokenID}
+		_, err = invokeWithSigner(native, b, common.SerializeToBytes(approve), oep5.Approve)
+		assert.Nil(t, err)
+		take := &oep5.TakeOwnershipParam{ID: id, To: c, TokenID: tokenID}
+		_, err = invokeWithSigner(native, c, common.SerializeToBytes(take), oep5.TakeOwnership)
+		assert.Nil(t, err)
+		native.Input = token
+		buf, err = oep5.OwnerOf(native)
+		assert.Nil(t, err)
+		assert.Equal(t, c[:], buf)
+		native.Input = token
+		buf, err = oep5.GetApproved(native)
+		assert.Nil(t, err)
+		assert.Equal(t, common.ADDRESS_EMPTY[:], buf)
+
+		//approval is cleared after transfer
+		_, err = invokeWithSigner(native, c, common.SerializeToBytes(take), oep5.TakeOwnership)
+		assert.NotNil(t, err)
+		return nil, nil
+	})
+}
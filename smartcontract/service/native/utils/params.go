@@ -47,6 +47,12 @@ var (
 
 	// 治理系统合约
 	GovernanceContractAddress, _ = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07})
+
+	// oep4 token factory contract
+	Oep4FactoryContractAddress, _ = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08})
+
+	// oep5 token factory contract
+	Oep5FactoryContractAddress, _ = common.AddressParseFromBytes([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09})
 )
 
 func IsNativeContract(addr common.Address) bool {
@@ -55,6 +61,8 @@ func IsNativeContract(addr common.Address) bool {
 		bytes.Compare(addr[:], OntIDContractAddress[:]) == 0 ||
 		bytes.Compare(addr[:], ParamContractAddress[:]) == 0 ||
 		bytes.Compare(addr[:], AuthContractAddress[:]) == 0 ||
-		bytes.Compare(addr[:], GovernanceContractAddress[:]) == 0
+		bytes.Compare(addr[:], GovernanceContractAddress[:]) == 0 ||
+		bytes.Compare(addr[:], Oep4FactoryContractAddress[:]) == 0 ||
+		bytes.Compare(addr[:], Oep5FactoryContractAddress[:]) == 0
 
 }
@@ -170,6 +170,17 @@ func (tp *TXPool) GetTransaction(hash common.Uint256) *types.Transaction {
 	return tp.txList[hash].Tx
 }
 
+// GetTxList returns all the transactions in the pool.
+func (tp *TXPool) GetTxList() []*types.Transaction {
+	tp.RLock()
+	defer tp.RUnlock()
+	txList := make([]*types.Transaction, 0, len(tp.txList))
+	for _, txEntry := range tp.txList {
+		txList = append(txList, txEntry.Tx)
+	}
+	return txList
+}
+
 // GetTxStatus returns a transaction status if it is contained in the pool
 // and nil otherwise.
 func (tp *TXPool) GetTxStatus(hash common.Uint256) *TxStatus {
@@ -236,15 +247,19 @@ func (tp *TXPool) GetUnverifiedTxs(txs []*types.Transaction,
 	return res
 }
 
-// RemoveTxsBelowGasPrice drops all transactions below the gas price
-func (tp *TXPool) RemoveTxsBelowGasPrice(gasPrice uint64) {
+// RemoveTxsBelowGasPrice drops all transactions below the gas price and
+// returns the dropped ones
+func (tp *TXPool) RemoveTxsBelowGasPrice(gasPrice uint64) []*types.Transaction {
 	tp.Lock()
 	defer tp.Unlock()
+	var removed []*types.Transaction
 	for _, txEntry := range tp.txList {
 		if txEntry.Tx.GasPrice < gasPrice {
 			delete(tp.txList, txEntry.Tx.Hash())
+			removed = append(removed, txEntry.Tx)
 		}
 	}
+	return removed
 }
 
 // Remain returns the remaining tx list to cleanup
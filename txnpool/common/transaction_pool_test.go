@@ -99,3 +99,25 @@ func TestTxPool(t *testing.T) {
 		return
 	}
 }
+
+func TestRemoveTxsBelowGasPrice(t *testing.T) {
+	txPool := &TXPool{}
+	txPool.Init()
+
+	mutable := &types.MutableTransaction{
+		TxType:   types.InvokeNeo,
+		Nonce:    uint32(time.Now().Unix()),
+		GasPrice: 500,
+		Payload:  &payload.InvokeCode{Code: []byte{}},
+	}
+	highTxn, _ := mutable.IntoImmutable()
+
+	assert.True(t, txPool.AddTxList(&TXEntry{Tx: txn, Attrs: []*TXAttr{}}))
+	assert.True(t, txPool.AddTxList(&TXEntry{Tx: highTxn, Attrs: []*TXAttr{}}))
+
+	removed := txPool.RemoveTxsBelowGasPrice(500)
+	assert.Equal(t, 1, len(removed))
+	assert.Equal(t, txn.Hash(), removed[0].Hash())
+	assert.Nil(t, txPool.GetTransaction(txn.Hash()))
+	assert.NotNil(t, txPool.GetTransaction(highTxn.Hash()))
+}
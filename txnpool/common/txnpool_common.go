@@ -55,9 +55,10 @@ const (
 type SenderType uint8
 
 const (
-	NilSender  SenderType = iota
-	NetSender             // Net sends tx req
-	HttpSender            // Http sends tx req
+	NilSender       SenderType = iota
+	NetSender                  // Net sends tx req
+	HttpSender                 // Http sends tx req
+	ConsensusSender            // Consensus sends tx req
 )
 
 func (sender SenderType) Sender() string {
@@ -68,6 +69,8 @@ func (sender SenderType) Sender() string {
 		return "net sender"
 	case HttpSender:
 		return "http sender"
+	case ConsensusSender:
+		return "consensus sender"
 	default:
 		return "unknown sender"
 	}
@@ -136,6 +139,16 @@ type GetTxnRsp struct {
 	Txn *types.Transaction
 }
 
+// GetTxnListReq specifies the api that how to get all the verified
+// transactions in the pool.
+type GetTxnListReq struct {
+}
+
+// GetTxnListRsp returns a transaction list for GetTxnListReq.
+type GetTxnListRsp struct {
+	Txs []*types.Transaction
+}
+
 // CheckTxnReq specifies the api that how to check whether a
 // transaction in the pool.
 // Input: a transaction hash
@@ -230,6 +230,17 @@ func (ta *TxActor) Receive(context actor.Context) {
 				context.Self())
 		}
 
+	case *tc.GetTxnListReq:
+		sender := context.Sender()
+
+		log.Debugf("txpool-tx actor receives getting tx list req from %v", sender)
+
+		res := ta.server.getTxList()
+		if sender != nil {
+			sender.Request(&tc.GetTxnListRsp{Txs: res},
+				context.Self())
+		}
+
 	case *tc.GetTxnStats:
 		sender := context.Sender()
 
@@ -332,6 +343,14 @@ func (tpa *TxPoolActor) Receive(context actor.Context) {
 
 		tpa.server.verifyBlock(msg, sender)
 
+	case *tc.TxReq:
+		log.Debugf("txpool actor receives tx from %v", msg.Sender.Sender())
+
+		//consensus submits its txs through the pool actor
+		if pid := tpa.server.GetPID(tc.TxActor); pid != nil {
+			pid.Tell(msg)
+		}
+
 	case *message.SaveBlockCompleteMsg:
 		sender := context.Sender()
 
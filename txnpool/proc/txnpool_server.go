@@ -30,6 +30,8 @@ import (
 	"github.com/ontio/ontology/core/ledger"
 	tx "github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/events"
+	"github.com/ontio/ontology/events/message"
 	httpcom "github.com/ontio/ontology/http/base/common"
 	params "github.com/ontio/ontology/smartcontract/service/native/global_params"
 	nutils "github.com/ontio/ontology/smartcontract/service/native/utils"
@@ -46,9 +48,10 @@ type txStats struct {
 }
 
 type serverPendingTx struct {
-	tx     *tx.Transaction   // Pending tx
-	sender tc.SenderType     // Indicate which sender tx is from
-	ch     chan *tc.TxResult // channel to send tx result
+	tx       *tx.Transaction   // Pending tx
+	sender   tc.SenderType     // Indicate which sender tx is from
+	ch       chan *tc.TxResult // channel to send tx result
+	reVerify bool              // Indicate tx was taken out of the pool to re-verify
 }
 
 type pendingBlock struct {
@@ -222,6 +225,26 @@ func (s *TXPoolServer) checkPendingBlockOk(hash common.Uint256,
 	}
 }
 
+// publishPendingTx notifies the subscribers that a transaction passed
+// verification and entered the tx pool.
+func publishPendingTx(t *tx.Transaction) {
+	if events.DefActorPublisher == nil {
+		return
+	}
+	events.DefActorPublisher.Publish(message.TOPIC_TXPOOL_PENDING_TX,
+		&message.TxPoolPendingTxMsg{Tx: t})
+}
+
+// publishDropTx notifies the subscribers that a transaction was removed
+// from the tx pool without being packed into a block.
+func publishDropTx(t *tx.Transaction, reason string) {
+	if events.DefActorPublisher == nil {
+		return
+	}
+	events.DefActorPublisher.Publish(message.TOPIC_TXPOOL_DROP_TX,
+		&message.TxPoolDropTxMsg{Tx: t, Reason: reason})
+}
+
 // getPendingListSize return the length of the pending tx list.
 func (s *TXPoolServer) getPendingListSize() int {
 	s.mu.Lock()
@@ -265,7 +288,7 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 		return
 	}
 
-	if err == errors.ErrNoError && ((pt.sender == tc.HttpSender) ||
+	if err == errors.ErrNoError && ((pt.sender == tc.HttpSender || pt.sender == tc.ConsensusSender) ||
 		(pt.sender == tc.NetSender && !s.disableBroadcastNetTx)) {
 		pid := s.GetPID(tc.NetActor)
 		if pid != nil {
@@ -273,10 +296,16 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 		}
 	}
 
-	if pt.sender == tc.HttpSender && pt.ch != nil {
+	if pt.ch != nil {
 		replyTxResult(pt.ch, hash, err, err.Error())
 	}
 
+	if err == errors.ErrNoError && !pt.reVerify {
+		publishPendingTx(pt.tx)
+	} else if err != errors.ErrNoError && pt.reVerify {
+		publishDropTx(pt.tx, err.Error())
+	}
+
 	delete(s.allPendingTxs, hash)
 
 	if len(s.allPendingTxs) < tc.MAX_LIMITATION {
@@ -297,7 +326,7 @@ func (s *TXPoolServer) removePendingTx(hash common.Uint256,
 // setPendingTx adds a transaction to the pending list, if the
 // transaction is already in the pending list, just return false.
 func (s *TXPoolServer) setPendingTx(tx *tx.Transaction,
-	sender tc.SenderType, txResultCh chan *tc.TxResult) bool {
+	sender tc.SenderType, txResultCh chan *tc.TxResult, reVerify bool) bool {
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -308,9 +337,10 @@ func (s *TXPoolServer) setPendingTx(tx *tx.Transaction,
 	}
 
 	pt := &serverPendingTx{
-		tx:     tx,
-		sender: sender,
-		ch:     txResultCh,
+		tx:       tx,
+		sender:   sender,
+		ch:       txResultCh,
+		reVerify: reVerify,
 	}
 
 	s.allPendingTxs[tx.Hash()] = pt
@@ -325,7 +355,7 @@ func (s *TXPoolServer) assignTxToWorker(tx *tx.Transaction,
 		return false
 	}
 
-	if ok := s.setPendingTx(tx, sender, txResultCh); !ok {
+	if ok := s.setPendingTx(tx, sender, txResultCh, false); !ok {
 		s.increaseStats(tc.DuplicateStats)
 		if sender == tc.HttpSender && txResultCh != nil {
 			replyTxResult(txResultCh, tx.Hash(), errors.ErrDuplicateInput,
@@ -491,6 +521,11 @@ func (s *TXPoolServer) getTransaction(hash common.Uint256) *tx.Transaction {
 	return s.txPool.GetTransaction(hash)
 }
 
+// getTxList returns all the transactions in the tx pool.
+func (s *TXPoolServer) getTxList() []*tx.Transaction {
+	return s.txPool.GetTxList()
+}
+
 // getTxPool returns a tx list for consensus.
 func (s *TXPoolServer) getTxPool(byCount bool, height uint32) []*tc.TXEntry {
 	s.setHeight(height)
@@ -542,15 +577,18 @@ func (s *TXPoolServer) cleanTransactionList(txs []*tx.Transaction, height uint32
 		}
 
 		if oldGasPrice < gasPrice {
-			s.txPool.RemoveTxsBelowGasPrice(gasPrice)
+			for _, t := range s.txPool.RemoveTxsBelowGasPrice(gasPrice) {
+				publishDropTx(t, errors.ErrGasPrice.Error())
+			}
 		}
 	}
 	// Cleanup tx pool
 	if !s.disablePreExec {
 		remain := s.txPool.Remain()
 		for _, t := range remain {
-			if ok, _ := preExecCheck(t); !ok {
+			if ok, desc := preExecCheck(t); !ok {
 				log.Debugf("cleanTransactionList: preExecCheck tx %x failed", t.Hash())
+				publishDropTx(t, desc)
 				continue
 			}
 			s.reVerifyStateful(t, tc.NilSender)
@@ -628,7 +666,7 @@ func (s *TXPoolServer) getTransactionCount() int {
 
 // reVerifyStateful re-verify a transaction's stateful data.
 func (s *TXPoolServer) reVerifyStateful(tx *tx.Transaction, sender tc.SenderType) {
-	if ok := s.setPendingTx(tx, sender, nil); !ok {
+	if ok := s.setPendingTx(tx, sender, nil, true); !ok {
 		s.increaseStats(tc.DuplicateStats)
 		return
 	}
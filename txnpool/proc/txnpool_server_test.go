@@ -26,6 +26,8 @@ import (
 	"github.com/ontio/ontology/core/payload"
 	"github.com/ontio/ontology/core/types"
 	"github.com/ontio/ontology/errors"
+	"github.com/ontio/ontology/events"
+	"github.com/ontio/ontology/events/message"
 	tc "github.com/ontio/ontology/txnpool/common"
 	"github.com/ontio/ontology/validator/stateless"
 	vt "github.com/ontio/ontology/validator/types"
@@ -285,3 +287,55 @@ func TestValidator(t *testing.T) {
 
 	t.Log("Ending validator testing")
 }
+
+func TestPublishPendingAndDropTx(t *testing.T) {
+	events.Init()
+	received := make(chan interface{}, 2)
+	subPID := actor.Spawn(actor.FromFunc(func(c actor.Context) {
+		switch msg := c.Message().(type) {
+		case *message.TxPoolPendingTxMsg, *message.TxPoolDropTxMsg:
+			received <- msg
+		}
+	}))
+	sub := events.NewActorSubscriber(subPID)
+	sub.Subscribe(message.TOPIC_TXPOOL_PENDING_TX)
+	sub.Subscribe(message.TOPIC_TXPOOL_DROP_TX)
+	time.Sleep(100 * time.Millisecond)
+
+	s := NewTxPoolServer(tc.MAX_WORKER_NUM, true, false)
+	defer s.Stop()
+
+	// Case 1: a new tx passes verification, it is published as pending
+	s.allPendingTxs[txn.Hash()] = &serverPendingTx{tx: txn, sender: sender}
+	s.removePendingTx(txn.Hash(), errors.ErrNoError)
+	select {
+	case msg := <-received:
+		pending, ok := msg.(*message.TxPoolPendingTxMsg)
+		assert.True(t, ok)
+		assert.Equal(t, txn.Hash(), pending.Tx.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("pending tx is not published")
+	}
+
+	// Case 2: a tx fails re-verification, it is published as dropped
+	s.allPendingTxs[txn.Hash()] = &serverPendingTx{tx: txn, sender: sender, reVerify: true}
+	s.removePendingTx(txn.Hash(), errors.ErrTransactionBalance)
+	select {
+	case msg := <-received:
+		drop, ok := msg.(*message.TxPoolDropTxMsg)
+		assert.True(t, ok)
+		assert.Equal(t, txn.Hash(), drop.Tx.Hash())
+		assert.Equal(t, errors.ErrTransactionBalance.Error(), drop.Reason)
+	case <-time.After(time.Second):
+		t.Fatal("dropped tx is not published")
+	}
+
+	// Case 3: a tx in the pool passes re-verification, nothing is published
+	s.allPendingTxs[txn.Hash()] = &serverPendingTx{tx: txn, sender: sender, reVerify: true}
+	s.removePendingTx(txn.Hash(), errors.ErrNoError)
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected message %v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}